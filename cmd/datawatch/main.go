@@ -8,12 +8,15 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
-	"github.com/alexanderjulianmartinez/data-watch/internal/cdc/debezium"
+	_ "github.com/alexanderjulianmartinez/data-watch/internal/cdc/debezium"
 	"github.com/alexanderjulianmartinez/data-watch/internal/config"
 	"github.com/alexanderjulianmartinez/data-watch/internal/drift"
-	"github.com/alexanderjulianmartinez/data-watch/internal/source/mysql"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+	_ "github.com/alexanderjulianmartinez/data-watch/internal/source/mysql"
+	_ "github.com/alexanderjulianmartinez/data-watch/internal/source/postgres"
 )
 
 func main() {
@@ -32,6 +35,10 @@ func run(args []string) error {
 	switch args[1] {
 	case "check":
 		return runCheck(args[2:])
+	case "reconcile":
+		return runReconcile(args[2:])
+	case "checksum":
+		return runChecksum(args[2:])
 	case "help", "--help", "-h":
 		printUsage()
 		return nil
@@ -59,69 +66,105 @@ func runCheck(args []string) error {
 		return fmt.Errorf("failed to load config %s: %w", *configPath, err)
 	}
 
-	inspector, err := mysql.NewInspector(cfg.Source.DSN, cfg.Source.Schema)
+	// Resolve both inspectors up front so an unsupported type fails before any connection is made
+	cdcInspector, err := cdc.New(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create MySQL inspector: %w", err)
+		return fmt.Errorf("failed to create CDC inspector: %w", err)
 	}
+	// Replaying a long schema history topic can take a while; report progress on stderr
+	if pr, ok := cdcInspector.(cdc.ProgressReporter); ok {
+		pr.SetProgress(os.Stderr)
+	}
+	inspector, err := source.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create %s inspector: %w", cfg.Source.Type, err)
+	}
+	defer inspector.Close()
 
 	ctx := context.Background()
 	mysqlResult, err := inspector.Inspect(ctx)
 	if err != nil {
-		return fmt.Errorf("mysql inspection failed: %w", err)
+		return fmt.Errorf("%s inspection failed: %w", inspector.Name(), err)
 	}
 
-	fmt.Printf("Found %d table(s) in MySQL\n", len(mysqlResult.Tables))
+	fmt.Printf("Found %d table(s) in %s\n", len(mysqlResult.Tables), inspector.Name())
 	for _, table := range mysqlResult.Tables {
 		fmt.Printf("Table: %s\n", table.Name)
 		fmt.Printf("  Columns: %d\n", len(table.Columns))
 		fmt.Printf("  Row count: %d\n", table.RowCount)
 	}
+	if len(mysqlResult.Warnings) > 0 {
+		fmt.Println("Warnings:")
+		for _, w := range mysqlResult.Warnings {
+			fmt.Printf("  - %s\n", w)
+		}
+	}
+
+	// Let the CDC inspector evaluate connector filters against the inspected tables
+	if sa, ok := cdcInspector.(cdc.SourceAware); ok {
+		sa.SetSource(cfg.Source.Schema, mysqlResult.Tables, mysqlResult.OtherTables)
+	}
 
 	// Collect per-connector CDC inspection results (if supported)
 	var connectorResults []*cdc.ConnectorResult
-	if cfg.CDC.Type == "debezium" {
-		inspector := debezium.New(cfg.CDC)
-		// Prefer InspectConnectors when available
-		if multi, ok := (interface{}(inspector)).(interface {
-			InspectConnectors(context.Context) ([]*cdc.ConnectorResult, error)
-		}); ok {
-			connectorResults, err = multi.InspectConnectors(context.Background())
-			if err != nil {
-				return fmt.Errorf("failed to inspect CDC connectors: %w", err)
+	// Prefer InspectConnectors when available
+	if multi, ok := cdcInspector.(cdc.ConnectorInspector); ok {
+		connectorResults, err = multi.InspectConnectors(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to inspect CDC connectors: %w", err)
+		}
+		fmt.Println("\nCDC:", cdcInspector.Name())
+		for _, cr := range connectorResults {
+			fmt.Printf("  Connector: %s\n", cr.Name)
+			fmt.Printf("    Connector reachable: %v\n", cr.Result.ConnectorReachable)
+			if len(cr.Result.CapturedTables) > 0 {
+				fmt.Printf("    CDC Tables: %v\n", cr.Result.CapturedTables)
 			}
-			fmt.Println("\nCDC:", inspector.Name())
-			for _, cr := range connectorResults {
-				fmt.Printf("  Connector: %s\n", cr.Name)
-				fmt.Printf("    Connector reachable: %v\n", cr.Result.ConnectorReachable)
-				if len(cr.Result.CapturedTables) > 0 {
-					fmt.Printf("    CDC Tables: %v\n", cr.Result.CapturedTables)
-				}
-				if len(cr.Result.Warnings) > 0 {
-					fmt.Println("    Warnings:")
-					for _, w := range cr.Result.Warnings {
-						fmt.Printf("      - %s\n", w)
+			if len(cr.Result.LastEventTimes) > 0 {
+				fmt.Println("    Last events:")
+				for _, t := range cr.Result.CapturedTables {
+					last, ok := cr.Result.LastEventTimes[t]
+					if !ok {
+						continue
 					}
+					fmt.Printf("      - %s: %s\n", t, last.Format(time.RFC3339))
 				}
 			}
-		} else {
-			// Fallback to legacy aggregated Inspect
-			single, err := inspector.Inspect(context.Background())
-			if err != nil {
-				return fmt.Errorf("failed to inspect CDC (legacy): %w", err)
-			}
-			connectorResults = []*cdc.ConnectorResult{{Name: "", Result: single}}
-			fmt.Println("\nCDC:", inspector.Name())
-			fmt.Println("  Connector reachable:", single.ConnectorReachable)
-			if len(single.CapturedTables) > 0 {
-				fmt.Println("  CDC Tables:", single.CapturedTables)
-			}
-			if len(single.Warnings) > 0 {
-				fmt.Println("  Warnings:")
-				for _, w := range single.Warnings {
-					fmt.Printf("    - %s\n", w)
+			if hb := cr.Result.Heartbeat; hb != nil && !hb.Last.IsZero() {
+				fmt.Printf("    Last heartbeat: %s (%s)\n", hb.Last.Format(time.RFC3339), hb.Topic)
+			}
+			if len(cr.Result.Warnings) > 0 {
+				fmt.Println("    Warnings:")
+				for _, w := range cr.Result.Warnings {
+					fmt.Printf("      - %s\n", w.Message)
 				}
 			}
 		}
+	} else {
+		// Fallback to legacy aggregated Inspect
+		single, err := cdcInspector.Inspect(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to inspect CDC (legacy): %w", err)
+		}
+		connectorResults = []*cdc.ConnectorResult{{Name: "", Result: single}}
+		fmt.Println("\nCDC:", cdcInspector.Name())
+		fmt.Println("  Connector reachable:", single.ConnectorReachable)
+		if len(single.CapturedTables) > 0 {
+			fmt.Println("  CDC Tables:", single.CapturedTables)
+		}
+		if len(single.Warnings) > 0 {
+			fmt.Println("  Warnings:")
+			for _, w := range single.Warnings {
+				fmt.Printf("    - %s\n", w.Message)
+			}
+		}
+	}
+
+	// Measure how far each connector trails the source binlog
+	for _, cr := range connectorResults {
+		if cr.Result != nil && cr.Result.Lag == nil {
+			cr.Result.Lag = drift.MeasureLag(mysqlResult, cr.Result.Offset, time.Now())
+		}
 	}
 
 	// Do not auto-populate CDC schemas from MySQL. Only use CDC-provided schemas for validation.
@@ -129,14 +172,32 @@ func runCheck(args []string) error {
 	// Validate per-connector and aggregate issues for summary
 	reportsByConnector := map[string]*drift.Report{}
 	overallIssues := []drift.Issue{}
+	// Configured-table checks span all connectors
+	tablesReport := drift.ValidateTables(mysqlResult, connectorResults, cfg)
+	// So do the source server's binlog settings
+	tablesReport.Issues = append(tablesReport.Issues, drift.ValidateServer(mysqlResult, connectorResults, cfg).Issues...)
+	overallIssues = append(overallIssues, tablesReport.Issues...)
 	if len(connectorResults) == 0 {
 		// No CDC connectors detected; validate with nil CDC result
-		rep := drift.Validate(mysqlResult, nil)
+		rep := drift.ValidateWithOptions(mysqlResult, nil, drift.Options{Config: cfg})
 		reportsByConnector[""] = rep
 		overallIssues = append(overallIssues, rep.Issues...)
 	} else {
 		for _, cr := range connectorResults {
-			rep := drift.Validate(mysqlResult, cr.Result)
+			opts := drift.Options{Config: cfg}
+			// Check the connector account's privileges when the source can read them
+			if gi, ok := inspector.(source.GrantInspector); ok && cr.Result != nil && cr.Result.DatabaseUser != "" {
+				grants, err := gi.FetchGrants(ctx, cr.Result.DatabaseUser)
+				switch {
+				case err != nil:
+					fmt.Fprintf(os.Stderr, "datawatch warning: could not read grants for connector %s user %s: %v\n", cr.Name, cr.Result.DatabaseUser, err)
+				case grants == nil:
+					fmt.Fprintf(os.Stderr, "datawatch warning: grants for connector %s user %s are not visible to the DataWatch account (needs SELECT on the mysql schema)\n", cr.Name, cr.Result.DatabaseUser)
+				default:
+					opts.Grants = grants
+				}
+			}
+			rep := drift.ValidateWithOptions(mysqlResult, cr.Result, opts)
 			reportsByConnector[cr.Name] = rep
 			overallIssues = append(overallIssues, rep.Issues...)
 		}
@@ -192,6 +253,7 @@ func runCheck(args []string) error {
 		out := struct {
 			MySQL      interface{}    `json:"mysql"`
 			Connectors []connectorOut `json:"connectors"`
+			Drift      *drift.Report  `json:"drift"`
 			Summary    struct {
 				Info  int `json:"info"`
 				Warn  int `json:"warn"`
@@ -199,6 +261,17 @@ func runCheck(args []string) error {
 			} `json:"summary"`
 		}{
 			MySQL: mysqlResult,
+			Drift: tablesReport,
+		}
+		for _, iss := range tablesReport.Issues {
+			switch iss.Severity {
+			case drift.SeverityBlock:
+				out.Summary.Block++
+			case drift.SeverityWarn:
+				out.Summary.Warn++
+			case drift.SeverityInfo:
+				out.Summary.Info++
+			}
 		}
 
 		// populate connectors
@@ -243,6 +316,17 @@ func runCheck(args []string) error {
 	fmt.Println("\nDrift Check:")
 	// If we have per-connector reports, print each connector's drift separately.
 	if len(reportsByConnector) > 0 && len(connectorResults) > 0 {
+		if len(tablesReport.Issues) > 0 {
+			fmt.Println("  Configured tables:")
+			for _, iss := range tablesReport.Issues {
+				if iss.Table == "" {
+					fmt.Printf("    - [%s] %s\n", iss.Severity, iss.Message)
+				} else {
+					fmt.Printf("    - [%s] %s: %s\n", iss.Severity, iss.Table, iss.Message)
+				}
+			}
+			fmt.Println()
+		}
 		for _, cr := range connectorResults {
 			name := cr.Name
 			rep := reportsByConnector[name]
@@ -250,6 +334,9 @@ func runCheck(args []string) error {
 				continue
 			}
 			fmt.Printf("  Connector: %s\n", name)
+			if cr.Result != nil && cr.Result.Lag != nil {
+				fmt.Printf("    Replication lag: %s\n", drift.FormatLag(cr.Result.Lag))
+			}
 			if len(rep.Issues) == 0 {
 				fmt.Println("    No drift detected")
 				continue
@@ -416,14 +503,309 @@ func runCheck(args []string) error {
 	return nil
 }
 
+func runReconcile(args []string) error {
+	fs := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Path to config YAML file (required)")
+	sampleSize := fs.Int("sample", 100, "Rows to sample per table")
+	tableName := fs.String("table", "", "Reconcile only this table")
+	failOn := fs.String("fail-on", "block", "Exit non-zero if highest issue severity >= LEVEL. One of: info,warn,block")
+	format := fs.String("format", "human", "Output format. One of: human, json (default: human)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *configPath == "" {
+		return fmt.Errorf("required flag --config is missing; run 'datawatch help' for usage")
+	}
+	if *sampleSize <= 0 {
+		return fmt.Errorf("--sample must be positive")
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config %s: %w", *configPath, err)
+	}
+
+	cdcInspector, err := cdc.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create CDC inspector: %w", err)
+	}
+	multi, ok := cdcInspector.(cdc.ConnectorInspector)
+	replayer, ok2 := cdcInspector.(cdc.TopicReplayer)
+	if !ok || !ok2 {
+		return fmt.Errorf("CDC type %s cannot replay change events", cfg.CDC.Type)
+	}
+	if pr, ok := cdcInspector.(cdc.ProgressReporter); ok {
+		pr.SetProgress(os.Stderr)
+	}
+	inspector, err := source.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create %s inspector: %w", cfg.Source.Type, err)
+	}
+	defer inspector.Close()
+	sampler, ok := inspector.(source.RowSampler)
+	if !ok {
+		return fmt.Errorf("source type %s cannot sample rows", cfg.Source.Type)
+	}
+
+	ctx := context.Background()
+	sourceResult, err := inspector.Inspect(ctx)
+	if err != nil {
+		return fmt.Errorf("%s inspection failed: %w", inspector.Name(), err)
+	}
+	if sa, ok := cdcInspector.(cdc.SourceAware); ok {
+		sa.SetSource(cfg.Source.Schema, sourceResult.Tables, sourceResult.OtherTables)
+	}
+	connectorResults, err := multi.InspectConnectors(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to inspect CDC connectors: %w", err)
+	}
+
+	type connectorOut struct {
+		Name   string                   `json:"name"`
+		Tables []*drift.ReconcileResult `json:"tables"`
+	}
+	var out struct {
+		Connectors []connectorOut `json:"connectors"`
+		Summary    struct {
+			Info  int `json:"info"`
+			Warn  int `json:"warn"`
+			Block int `json:"block"`
+		} `json:"summary"`
+	}
+	highest := 0
+	for _, cr := range connectorResults {
+		c := connectorOut{Name: cr.Name}
+		tables := append([]string(nil), cr.Result.CapturedTables...)
+		sort.Strings(tables)
+		for _, t := range tables {
+			topic, ok := cr.Result.DataTopics[t]
+			if !ok || !cfg.IncludesTable(t) || (*tableName != "" && !strings.EqualFold(t, *tableName)) {
+				continue
+			}
+			var table *source.TableInfo
+			for n := range sourceResult.Tables {
+				if strings.EqualFold(sourceResult.Tables[n].Name, t) {
+					table = &sourceResult.Tables[n]
+				}
+			}
+			if table == nil {
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Reconciling %s with topic %s\n", table.Name, topic)
+			_, compacted := cr.Result.KeyCounts[t]
+			res, err := drift.ReconcileTable(ctx, sampler, replayer, *table, topic, drift.ReconcileOptions{
+				SampleSize: *sampleSize,
+				KeyColumns: cr.Result.KeyColumns[t],
+				Settings:   cr.Result.EmitSettings[t],
+				Policies:   cr.Result.ColumnPolicies[t],
+				Compacted:  compacted,
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "datawatch warning: could not reconcile %s for connector %s: %v\n", t, cr.Name, err)
+				continue
+			}
+			for _, iss := range res.Issues {
+				switch iss.Severity {
+				case drift.SeverityBlock:
+					out.Summary.Block++
+					highest = 2
+				case drift.SeverityWarn:
+					out.Summary.Warn++
+					highest = max(highest, 1)
+				case drift.SeverityInfo:
+					out.Summary.Info++
+				}
+			}
+			c.Tables = append(c.Tables, res)
+		}
+		out.Connectors = append(out.Connectors, c)
+	}
+
+	failOnRank := 2
+	switch strings.ToLower(strings.TrimSpace(*failOn)) {
+	case "info":
+		failOnRank = 0
+	case "warn":
+		failOnRank = 1
+	}
+
+	if strings.ToLower(strings.TrimSpace(*format)) == "json" {
+		b, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	} else {
+		fmt.Println("Reconcile:")
+		for _, c := range out.Connectors {
+			fmt.Printf("  Connector: %s\n", c.Name)
+			if len(c.Tables) == 0 {
+				fmt.Println("    No tables reconciled")
+			}
+			for _, res := range c.Tables {
+				fmt.Printf("    Table: %s (topic %s)\n", res.Table, res.Topic)
+				fmt.Printf("      Sampled %d rows, %d match; %d topic keys looked up\n", res.Sampled, res.Matched, res.TopicSampled)
+				for _, iss := range res.Issues {
+					if iss.Column == "" {
+						fmt.Printf("      - [%s] %s\n", iss.Severity, iss.Message)
+					} else {
+						fmt.Printf("      - [%s] %s.%s %s\n", iss.Severity, iss.Table, iss.Column, iss.Message)
+					}
+				}
+			}
+		}
+		fmt.Printf("\nSummary: %d INFO / %d WARN / %d BLOCK\n", out.Summary.Info, out.Summary.Warn, out.Summary.Block)
+	}
+
+	if highest >= failOnRank && highest > 0 {
+		os.Exit(highest)
+	}
+	return nil
+}
+
+func runChecksum(args []string) error {
+	fs := flag.NewFlagSet("checksum", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Path to config YAML file (required)")
+	tableName := fs.String("table", "", "Compare only this table")
+	failOn := fs.String("fail-on", "block", "Exit non-zero if highest issue severity >= LEVEL. One of: info,warn,block")
+	format := fs.String("format", "human", "Output format. One of: human, json (default: human)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *configPath == "" {
+		return fmt.Errorf("required flag --config is missing; run 'datawatch help' for usage")
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config %s: %w", *configPath, err)
+	}
+	if cfg.Sink == nil {
+		return fmt.Errorf("config %s has no sink section", *configPath)
+	}
+
+	inspector, err := source.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create %s inspector: %w", cfg.Source.Type, err)
+	}
+	defer inspector.Close()
+	srcReader, ok := inspector.(drift.ChecksumReader)
+	if !ok {
+		return fmt.Errorf("source type %s cannot checksum key ranges", cfg.Source.Type)
+	}
+	sinkInspector, err := source.New(cfg.Sink.SourceConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create %s sink inspector: %w", cfg.Sink.Type, err)
+	}
+	defer sinkInspector.Close()
+	sinkReader, ok := sinkInspector.(drift.ChecksumReader)
+	if !ok {
+		return fmt.Errorf("sink type %s cannot checksum key ranges", cfg.Sink.Type)
+	}
+
+	ctx := context.Background()
+	sourceResult, err := inspector.Inspect(ctx)
+	if err != nil {
+		return fmt.Errorf("%s inspection failed: %w", inspector.Name(), err)
+	}
+
+	var out struct {
+		Tables  []*drift.ChecksumResult `json:"tables"`
+		Summary struct {
+			Info  int `json:"info"`
+			Warn  int `json:"warn"`
+			Block int `json:"block"`
+		} `json:"summary"`
+	}
+	tables := append([]source.TableInfo(nil), sourceResult.Tables...)
+	sort.Slice(tables, func(a, b int) bool { return tables[a].Name < tables[b].Name })
+	opts := drift.ChecksumOptions{
+		ChunkSize:        cfg.Sink.Chunk(),
+		Pause:            cfg.Sink.ChunkPause(),
+		MaxRowsPerSecond: cfg.Sink.MaxRowsPerSecond,
+	}
+	highest := 0
+	for _, table := range tables {
+		if !cfg.IncludesTable(table.Name) || (*tableName != "" && !strings.EqualFold(table.Name, *tableName)) {
+			continue
+		}
+		sinkTable := cfg.Sink.TableName(table.Name)
+		fmt.Fprintf(os.Stderr, "Comparing %s with sink table %s\n", table.Name, sinkTable)
+		res, err := drift.CompareChecksums(ctx, srcReader, sinkReader, table, sinkTable, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "datawatch warning: could not compare %s: %v\n", table.Name, err)
+			continue
+		}
+		for _, iss := range res.Issues {
+			switch iss.Severity {
+			case drift.SeverityBlock:
+				out.Summary.Block++
+				highest = 2
+			case drift.SeverityWarn:
+				out.Summary.Warn++
+				highest = max(highest, 1)
+			case drift.SeverityInfo:
+				out.Summary.Info++
+			}
+		}
+		out.Tables = append(out.Tables, res)
+	}
+
+	failOnRank := 2
+	switch strings.ToLower(strings.TrimSpace(*failOn)) {
+	case "info":
+		failOnRank = 0
+	case "warn":
+		failOnRank = 1
+	}
+
+	if strings.ToLower(strings.TrimSpace(*format)) == "json" {
+		b, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	} else {
+		fmt.Println("Checksum:")
+		if len(out.Tables) == 0 {
+			fmt.Println("  No tables compared")
+		}
+		for _, res := range out.Tables {
+			fmt.Printf("  Table: %s (sink %s)\n", res.Table, res.SinkTable)
+			fmt.Printf("    %d chunks, %d mismatched; %d source rows, %d sink rows\n", res.Chunks, len(res.MismatchedChunks), res.SourceRows, res.SinkRows)
+			for _, iss := range res.Issues {
+				if iss.Column == "" {
+					fmt.Printf("    - [%s] %s\n", iss.Severity, iss.Message)
+				} else {
+					fmt.Printf("    - [%s] %s.%s %s\n", iss.Severity, iss.Table, iss.Column, iss.Message)
+				}
+			}
+		}
+		fmt.Printf("\nSummary: %d INFO / %d WARN / %d BLOCK\n", out.Summary.Info, out.Summary.Warn, out.Summary.Block)
+	}
+
+	if highest >= failOnRank && highest > 0 {
+		os.Exit(highest)
+	}
+	return nil
+}
+
 func printUsage() {
 	fmt.Print(`DataWatch - CDC validation tool
 
 Usage:
 	datawatch check --config <path> [--format json|human] [--fail-on info|warn|block]
+	datawatch reconcile --config <path> [--sample N] [--table NAME] [--format json|human] [--fail-on info|warn|block]
+	datawatch checksum --config <path> [--table NAME] [--format json|human] [--fail-on info|warn|block]
 
 Commands:
-	check     Run validation checks against MySQL and CDC connectors
+	check     Run validation checks against the source database (MySQL or Postgres) and CDC connectors
+	reconcile Compare sampled MySQL rows with the latest change events in each table's data topic
+	checksum  Compare source tables with the sink database in primary key chunks, read-only and throttled
 	help      Show this help message
 
 Flags (check):
@@ -431,8 +813,23 @@ Flags (check):
 	--format    Output format: 'human' (default) or 'json'
 	--fail-on   Exit non-zero if highest issue severity >= LEVEL. One of: info, warn, block
 
+Flags (reconcile):
+	--config    Path to config YAML file (required)
+	--sample    Rows to sample per table, and live topic keys to look up (default 100)
+	--table     Reconcile only this table
+	--format    Output format: 'human' (default) or 'json'
+	--fail-on   Exit non-zero if highest issue severity >= LEVEL. One of: info, warn, block
+
+Flags (checksum):
+	--config    Path to config YAML file with a sink section (required)
+	--table     Compare only this table
+	--format    Output format: 'human' (default) or 'json'
+	--fail-on   Exit non-zero if highest issue severity >= LEVEL. One of: info, warn, block
+
 Examples:
 	datawatch check --config examples/config.yaml
 	datawatch check --config examples/config.yaml --format json --fail-on warn
+	datawatch reconcile --config examples/config.yaml --sample 500 --table orders
+	datawatch checksum --config examples/config.yaml --table orders --format json
 `)
 }
@@ -4,6 +4,8 @@ go 1.25.6
 
 require (
 	github.com/go-sql-driver/mysql v1.9.3
+	github.com/lib/pq v1.10.9
+	github.com/segmentio/kafka-go v0.4.30
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -11,5 +13,4 @@ require (
 	filippo.io/edwards25519 v1.1.0 // indirect
 	github.com/klauspost/compress v1.14.2 // indirect
 	github.com/pierrec/lz4/v4 v4.1.14 // indirect
-	github.com/segmentio/kafka-go v0.4.30 // indirect
 )
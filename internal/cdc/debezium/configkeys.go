@@ -0,0 +1,184 @@
+package debezium
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// renamedKey is a connector setting whose name changed between Debezium
+// generations.
+type renamedKey struct {
+	legacy  string
+	current string
+	// removedOnly is set for keys 1.x already read under both names (the
+	// legacy one deprecated) and 2.0 stopped reading. Other keys were renamed
+	// in 2.0, so each generation reads exactly one name.
+	removedOnly bool
+}
+
+var renamedKeys = []renamedKey{
+	{legacy: "database.history.kafka.topic", current: "schema.history.internal.kafka.topic"},
+	{legacy: "database.history.kafka.bootstrap.servers", current: "schema.history.internal.kafka.bootstrap.servers"},
+	{legacy: "database.history", current: "schema.history.internal"},
+	{legacy: "database.history.store.only.captured.tables.ddl", current: "schema.history.internal.store.only.captured.tables.ddl"},
+	{legacy: "database.history.skip.unparseable.ddl", current: "schema.history.internal.skip.unparseable.ddl"},
+	{legacy: "database.server.name", current: "topic.prefix"},
+	{legacy: "heartbeat.topics.prefix", current: "topic.heartbeat.prefix"},
+	{legacy: "database.whitelist", current: "database.include.list", removedOnly: true},
+	{legacy: "database.blacklist", current: "database.exclude.list", removedOnly: true},
+	{legacy: "table.whitelist", current: "table.include.list", removedOnly: true},
+	{legacy: "table.blacklist", current: "table.exclude.list", removedOnly: true},
+	{legacy: "column.whitelist", current: "column.include.list", removedOnly: true},
+	{legacy: "column.blacklist", current: "column.exclude.list", removedOnly: true},
+}
+
+// connectorSettings resolves settings from a connector config the way the
+// Debezium version running it would.
+type connectorSettings struct {
+	config map[string]interface{}
+	// major is the Debezium major version: from the installed plugin when
+	// known, otherwise inferred from which generation of keys is set, and 0
+	// when that is ambiguous.
+	major int
+}
+
+func newConnectorSettings(config map[string]interface{}, pluginVersion string) connectorSettings {
+	s := connectorSettings{config: config, major: majorVersion(pluginVersion)}
+	if s.major == 0 {
+		legacy, current := s.renamedKeysSet()
+		switch {
+		case len(current) > 0 && len(legacy) == 0:
+			s.major = 2
+		case len(legacy) > 0 && len(current) == 0:
+			s.major = 1
+		}
+	}
+	return s
+}
+
+// majorVersion parses the major version of a plugin version such as
+// "2.4.0.Final", returning 0 when it cannot.
+func majorVersion(v string) int {
+	major, _, _ := strings.Cut(strings.TrimSpace(v), ".")
+	n, err := strconv.Atoi(major)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+func (s connectorSettings) lookup(key string) (string, bool) {
+	v, ok := s.config[key].(string)
+	return v, ok
+}
+
+// get returns the value of a setting by its current (2.x) name, falling back
+// to the legacy name when the running version reads it.
+func (s connectorSettings) get(key string) (string, bool) {
+	rk, renamed := renamedKeyFor(key)
+	if !renamed {
+		return s.lookup(key)
+	}
+	switch {
+	case s.major >= 2:
+		return s.lookup(rk.current)
+	case s.major == 1 && !rk.removedOnly:
+		return s.lookup(rk.legacy)
+	}
+	if v, ok := s.lookup(rk.current); ok {
+		return v, true
+	}
+	return s.lookup(rk.legacy)
+}
+
+func renamedKeyFor(current string) (renamedKey, bool) {
+	for _, rk := range renamedKeys {
+		if rk.current == current {
+			return rk, true
+		}
+	}
+	return renamedKey{}, false
+}
+
+// renamedKeysSet returns the keys set under their 1.x-only and 2.x-only
+// names. Keys 1.x reads under both names only count as legacy.
+func (s connectorSettings) renamedKeysSet() (legacy, current []string) {
+	for _, rk := range renamedKeys {
+		if _, ok := s.config[rk.legacy]; ok {
+			legacy = append(legacy, rk.legacy)
+		}
+		if _, ok := s.config[rk.current]; ok && !rk.removedOnly {
+			current = append(current, rk.current)
+		}
+	}
+	sort.Strings(legacy)
+	sort.Strings(current)
+	return legacy, current
+}
+
+// configKeyWarnings reports a connector that mixes 1.x and 2.x keys, sets
+// keys the running version ignores, or relies on deprecated keys.
+func configKeyWarnings(connector string, s connectorSettings) []string {
+	var warnings []string
+	legacy, current := s.renamedKeysSet()
+	var legacyRenamed []string
+	for _, k := range legacy {
+		if rk, _ := legacyKey(k); !rk.removedOnly {
+			legacyRenamed = append(legacyRenamed, k)
+		}
+	}
+	if len(legacyRenamed) > 0 && len(current) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Connector %s mixes Debezium 1.x and 2.x configuration keys (%s with %s); each version reads only one of them", connector, strings.Join(legacyRenamed, ", "), strings.Join(current, ", ")))
+	}
+	for _, k := range legacy {
+		rk, _ := legacyKey(k)
+		switch {
+		case s.major >= 2:
+			warnings = append(warnings, fmt.Sprintf("Connector %s sets configuration key %s, which Debezium %d.x ignores; use %s", connector, rk.legacy, s.major, rk.current))
+		case rk.removedOnly:
+			warnings = append(warnings, fmt.Sprintf("Connector %s uses deprecated configuration key %s, which Debezium 2.x ignores; use %s", connector, rk.legacy, rk.current))
+		}
+	}
+	if s.major == 1 {
+		for _, k := range current {
+			rk, _ := renamedKeyFor(k)
+			warnings = append(warnings, fmt.Sprintf("Connector %s sets configuration key %s, which Debezium 1.x ignores; use %s", connector, rk.current, rk.legacy))
+		}
+	}
+	return warnings
+}
+
+func legacyKey(legacy string) (renamedKey, bool) {
+	for _, rk := range renamedKeys {
+		if rk.legacy == legacy {
+			return rk, true
+		}
+	}
+	return renamedKey{}, false
+}
+
+// emitSettingKeys are the settings that change the Connect type Debezium
+// emits for a column.
+var emitSettingKeys = []string{
+	"decimal.handling.mode",
+	"time.precision.mode",
+	"bigint.unsigned.handling.mode",
+	"binary.handling.mode",
+}
+
+// emitSettings returns the emit settings a connector sets, or nil if it
+// relies on the defaults for all of them.
+func emitSettings(s connectorSettings) map[string]string {
+	var out map[string]string
+	for _, k := range emitSettingKeys {
+		if v, ok := s.get(k); ok {
+			if out == nil {
+				out = map[string]string{}
+			}
+			out[k] = v
+		}
+	}
+	return out
+}
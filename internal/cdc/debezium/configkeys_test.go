@@ -0,0 +1,85 @@
+package debezium
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConnectorSettingsGet(t *testing.T) {
+	v1 := map[string]interface{}{
+		"database.history.kafka.topic": "history.v1",
+		"table.whitelist":              "shop.orders",
+	}
+	v2 := map[string]interface{}{
+		"schema.history.internal.kafka.topic": "history.v2",
+		"table.include.list":                  "shop.orders",
+	}
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		version string
+		key     string
+		want    string
+		wantOK  bool
+	}{
+		{"1.x keys inferred", v1, "", "schema.history.internal.kafka.topic", "history.v1", true},
+		{"1.x deprecated whitelist", v1, "", "table.include.list", "shop.orders", true},
+		{"1.x keys on 2.x plugin", v1, "2.4.0.Final", "schema.history.internal.kafka.topic", "", false},
+		{"2.x keys inferred", v2, "", "schema.history.internal.kafka.topic", "history.v2", true},
+		{"2.x keys on 1.x plugin", v2, "1.9.7.Final", "schema.history.internal.kafka.topic", "", false},
+		{"unrenamed key", v2, "", "snapshot.mode", "", false},
+	}
+	for _, tt := range tests {
+		s := newConnectorSettings(tt.config, tt.version)
+		got, ok := s.get(tt.key)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: get(%s) = %q, %v; want %q, %v", tt.name, tt.key, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestConfigKeyWarnings(t *testing.T) {
+	mixed := newConnectorSettings(map[string]interface{}{
+		"database.history.kafka.topic":                    "history",
+		"schema.history.internal.kafka.bootstrap.servers": "kafka:9092",
+		"topic.prefix": "dbserver1",
+	}, "")
+	w := configKeyWarnings("c1", mixed)
+	want := []string{"Connector c1 mixes Debezium 1.x and 2.x configuration keys (database.history.kafka.topic with schema.history.internal.kafka.bootstrap.servers, topic.prefix); each version reads only one of them"}
+	if !reflect.DeepEqual(w, want) {
+		t.Fatalf("expected %q, got %q", want, w)
+	}
+
+	onV2 := newConnectorSettings(map[string]interface{}{"database.server.name": "dbserver1", "table.whitelist": "shop.orders"}, "2.1.2.Final")
+	w = configKeyWarnings("c2", onV2)
+	if len(w) != 2 || !strings.Contains(w[0], "database.server.name, which Debezium 2.x ignores; use topic.prefix") ||
+		!strings.Contains(w[1], "table.whitelist, which Debezium 2.x ignores; use table.include.list") {
+		t.Fatalf("expected ignored-key warnings, got %q", w)
+	}
+
+	deprecated := newConnectorSettings(map[string]interface{}{"database.server.name": "dbserver1", "column.blacklist": "shop.users.ssn"}, "")
+	w = configKeyWarnings("c3", deprecated)
+	if len(w) != 1 || !strings.Contains(w[0], "deprecated configuration key column.blacklist") {
+		t.Fatalf("expected a deprecation warning, got %q", w)
+	}
+
+	if w := configKeyWarnings("c4", newConnectorSettings(map[string]interface{}{"topic.prefix": "dbserver1"}, "")); len(w) != 0 {
+		t.Fatalf("expected no warnings for a clean 2.x config, got %q", w)
+	}
+}
+
+func TestEmitSettings(t *testing.T) {
+	s := newConnectorSettings(map[string]interface{}{
+		"topic.prefix":          "dbserver1",
+		"decimal.handling.mode": "string",
+		"time.precision.mode":   "connect",
+	}, "")
+	want := map[string]string{"decimal.handling.mode": "string", "time.precision.mode": "connect"}
+	if got := emitSettings(s); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := emitSettings(newConnectorSettings(map[string]interface{}{"topic.prefix": "dbserver1"}, "")); got != nil {
+		t.Fatalf("expected nil for default settings, got %v", got)
+	}
+}
@@ -0,0 +1,411 @@
+package debezium
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+// This file holds a small MySQL DDL reader used when a schema history record
+// carries no structured tableChanges. It understands just enough of the
+// grammar to recover column names, types, nullability and the primary key;
+// anything it does not recognise is skipped rather than guessed.
+
+// tableConstraintWords start a table-level definition rather than a column.
+var tableConstraintWords = map[string]bool{
+	"PRIMARY": true, "KEY": true, "INDEX": true, "UNIQUE": true, "CONSTRAINT": true,
+	"FOREIGN": true, "FULLTEXT": true, "SPATIAL": true, "CHECK": true,
+}
+
+// splitStatements splits a DDL string into individual statements on
+// top-level semicolons, with comments removed.
+func splitStatements(ddl string) []string {
+	var stmts []string
+	for _, s := range splitTopLevel(stripSQLComments(ddl), ';') {
+		if s = strings.TrimSpace(s); s != "" {
+			stmts = append(stmts, s)
+		}
+	}
+	return stmts
+}
+
+// stripSQLComments removes /* */, -- and # comments outside quoted strings.
+// Versioned comments (/*!80023 INVISIBLE */) keep their content, since MySQL
+// executes it.
+func stripSQLComments(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := skipQuoted(s, i)
+			b.WriteString(s[i:end])
+			i = end - 1
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			body := s[i+2 : i+2+end]
+			if strings.HasPrefix(body, "!") {
+				body = strings.TrimLeft(body[1:], "0123456789")
+				b.WriteString(" " + body + " ")
+			} else {
+				b.WriteByte(' ')
+			}
+			i = i + 2 + end + 1
+		case c == '#' || (c == '-' && strings.HasPrefix(s[i:], "-- ")):
+			end := strings.IndexByte(s[i:], '\n')
+			if end < 0 {
+				return b.String()
+			}
+			b.WriteByte(' ')
+			i += end
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// skipQuoted returns the index just past the quoted string starting at s[i].
+func skipQuoted(s string, i int) int {
+	q := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case s[j] == '\\' && q != '`':
+			j++
+		case s[j] == q:
+			if j+1 < len(s) && s[j+1] == q {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// splitTopLevel splits s on sep, ignoring separators inside quotes or parentheses.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(s, i) - 1
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// matchingParen returns the index of the ')' closing the '(' at s[open], or -1.
+func matchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(s, i) - 1
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// consumeWords strips the given case-insensitive keywords from the start of s.
+func consumeWords(s string, words ...string) (string, bool) {
+	rest := strings.TrimSpace(s)
+	for _, w := range words {
+		if len(rest) < len(w) || !strings.EqualFold(rest[:len(w)], w) {
+			return s, false
+		}
+		if len(rest) > len(w) && isIdentChar(rune(rest[len(w)])) {
+			return s, false
+		}
+		rest = strings.TrimSpace(rest[len(w):])
+	}
+	return rest, true
+}
+
+// consumeOptional strips the keywords if present and returns s otherwise.
+func consumeOptional(s string, words ...string) string {
+	rest, _ := consumeWords(s, words...)
+	return rest
+}
+
+func isIdentChar(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// readIdent reads a single backticked, double-quoted or bare identifier.
+func readIdent(s string) (ident, rest string, quoted bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", "", false
+	}
+	if s[0] == '`' || s[0] == '"' {
+		end := skipQuoted(s, 0)
+		q := string(s[0])
+		return strings.ReplaceAll(s[1:end-1], q+q, q), s[end:], true
+	}
+	i := 0
+	for i < len(s) && isIdentChar(rune(s[i])) {
+		i++
+	}
+	return s[:i], s[i:], false
+}
+
+// readQualifiedName reads [db.]table, returning an empty db when unqualified.
+func readQualifiedName(s string) (db, table, rest string) {
+	first, rest, _ := readIdent(s)
+	if strings.HasPrefix(rest, ".") {
+		second, rest2, _ := readIdent(rest[1:])
+		return first, second, rest2
+	}
+	return "", first, rest
+}
+
+// parseColumnList parses "(a, b(10) DESC, c)" into column names.
+func parseColumnList(s string) []string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "(") {
+		return nil
+	}
+	end := matchingParen(s, 0)
+	if end < 0 {
+		return nil
+	}
+	var cols []string
+	for _, part := range splitTopLevel(s[1:end], ',') {
+		if name, _, _ := readIdent(part); name != "" {
+			cols = append(cols, name)
+		}
+	}
+	return cols
+}
+
+// maskQuoted blanks out quoted string literals so keyword searches cannot
+// match inside DEFAULT or COMMENT values.
+func maskQuoted(s string) string {
+	b := []byte(s)
+	for i := 0; i < len(b); i++ {
+		if b[i] == '\'' || b[i] == '"' {
+			end := skipQuoted(s, i)
+			for j := i; j < end; j++ {
+				b[j] = ' '
+			}
+			i = end - 1
+		}
+	}
+	return string(b)
+}
+
+// containsWords reports whether the space-separated keyword sequence occurs
+// in s as whole words, ignoring case and quoted literals.
+func containsWords(s, words string) bool {
+	fields := strings.Fields(strings.ToUpper(maskQuoted(s)))
+	want := strings.Fields(strings.ToUpper(words))
+	for i := 0; i+len(want) <= len(fields); i++ {
+		match := true
+		for j := range want {
+			if strings.Trim(fields[i+j], ",()") != want[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// wordAfter returns the word following the keyword sequence in s, if any.
+func wordAfter(s, words string) string {
+	fields := strings.Fields(maskQuoted(s))
+	want := strings.Fields(strings.ToUpper(words))
+	for i := 0; i+len(want) < len(fields); i++ {
+		match := true
+		for j := range want {
+			if strings.ToUpper(fields[i+j]) != want[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			next := strings.Trim(fields[i+len(want)], "`'\",")
+			if next == "=" && i+len(want)+1 < len(fields) {
+				next = strings.Trim(fields[i+len(want)+1], "`'\",")
+			}
+			return strings.TrimPrefix(next, "=")
+		}
+	}
+	return ""
+}
+
+// parseColumnDef parses a column definition such as
+// "`price` decimal(10,2) unsigned NOT NULL DEFAULT '0.00'". It reports
+// whether the definition declares an inline PRIMARY KEY.
+func parseColumnDef(def string) (col *columnModel, inlinePK bool, ok bool) {
+	name, rest, _ := readIdent(def)
+	if name == "" {
+		return nil, false, false
+	}
+	rest = strings.TrimSpace(rest)
+	i := 0
+	for i < len(rest) && isIdentChar(rune(rest[i])) {
+		i++
+	}
+	if i == 0 {
+		return nil, false, false
+	}
+	base := strings.ToUpper(rest[:i])
+	rest = strings.TrimSpace(rest[i:])
+
+	var args []string
+	var enumValues []string
+	if strings.HasPrefix(rest, "(") {
+		end := matchingParen(rest, 0)
+		if end < 0 {
+			return nil, false, false
+		}
+		for _, a := range splitTopLevel(rest[1:end], ',') {
+			args = append(args, strings.TrimSpace(a))
+		}
+		enumValues = parseEnumValues(base + rest[:end+1])
+		rest = rest[end+1:]
+	}
+
+	info := cdc.ColumnInfo{Type: base, Nullable: true, EnumValues: enumValues}
+	if base != "ENUM" && base != "SET" {
+		if len(args) > 0 {
+			if n, err := strconv.Atoi(args[0]); err == nil {
+				info.Length = &n
+			}
+		}
+		if len(args) > 1 {
+			if n, err := strconv.Atoi(args[1]); err == nil {
+				info.Scale = &n
+			}
+		}
+	}
+	if containsWords(rest, "UNSIGNED") {
+		info.Type += " UNSIGNED"
+	}
+	if cs := wordAfter(rest, "CHARACTER SET"); cs != "" {
+		info.Charset = cs
+	} else if cs := wordAfter(rest, "CHARSET"); cs != "" {
+		info.Charset = cs
+	}
+	if containsWords(rest, "NOT NULL") {
+		info.Nullable = false
+	}
+	if containsWords(rest, "PRIMARY KEY") {
+		info.Nullable = false
+		inlinePK = true
+	}
+	return &columnModel{name: name, info: info}, inlinePK, true
+}
+
+// parseCreateTable parses a CREATE TABLE statement. For CREATE TABLE ... LIKE
+// the returned likeDB/likeTable name the source table and t has no columns.
+func parseCreateTable(stmt, defaultDB string) (t *tableModel, likeDB, likeTable string, ok bool) {
+	rest, ok := consumeWords(stmt, "CREATE")
+	if !ok {
+		return nil, "", "", false
+	}
+	rest = consumeOptional(rest, "TEMPORARY")
+	if rest, ok = consumeWords(rest, "TABLE"); !ok {
+		return nil, "", "", false
+	}
+	rest = consumeOptional(rest, "IF", "NOT", "EXISTS")
+	db, name, rest := readQualifiedName(rest)
+	if name == "" {
+		return nil, "", "", false
+	}
+	if db == "" {
+		db = defaultDB
+	}
+	t = &tableModel{db: db, name: name}
+
+	rest = strings.TrimSpace(rest)
+	if after, like := consumeWords(rest, "LIKE"); like {
+		ldb, lname, _ := readQualifiedName(after)
+		if ldb == "" {
+			ldb = defaultDB
+		}
+		return t, ldb, lname, true
+	}
+	if strings.HasPrefix(rest, "(") {
+		if inner, like := consumeWords(rest[1:], "LIKE"); like {
+			ldb, lname, _ := readQualifiedName(inner)
+			if ldb == "" {
+				ldb = defaultDB
+			}
+			return t, ldb, lname, true
+		}
+	}
+	if !strings.HasPrefix(rest, "(") {
+		return nil, "", "", false
+	}
+	end := matchingParen(rest, 0)
+	if end < 0 {
+		return nil, "", "", false
+	}
+	for _, def := range splitTopLevel(rest[1:end], ',') {
+		def = strings.TrimSpace(def)
+		if def == "" {
+			continue
+		}
+		if !strings.HasPrefix(def, "`") && !strings.HasPrefix(def, `"`) {
+			word, _, _ := readIdent(def)
+			if tableConstraintWords[strings.ToUpper(word)] {
+				if pk := primaryKeyFromConstraint(def); pk != nil {
+					t.pk = pk
+				}
+				continue
+			}
+		}
+		col, inlinePK, ok := parseColumnDef(def)
+		if !ok {
+			continue
+		}
+		t.columns = append(t.columns, col)
+		if inlinePK {
+			t.pk = []string{col.name}
+		}
+	}
+	t.renumber()
+	return t, "", "", true
+}
+
+// primaryKeyFromConstraint returns the columns of a "[CONSTRAINT x] PRIMARY
+// KEY (...)" definition, or nil for any other constraint.
+func primaryKeyFromConstraint(def string) []string {
+	upper := strings.ToUpper(maskQuoted(def))
+	idx := strings.Index(upper, "PRIMARY KEY")
+	if idx < 0 {
+		return nil
+	}
+	rest := def[idx+len("PRIMARY KEY"):]
+	// skip an optional index type, e.g. PRIMARY KEY USING BTREE (id)
+	if open := strings.IndexByte(rest, '('); open >= 0 {
+		return parseColumnList(rest[open:])
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package debezium
+
+import "strings"
+
+// parseEnumValues returns the permitted values of an ENUM or SET column type
+// in schema history DDL, such as "enum('small','medium','large')", in
+// declaration order, or nil if columnType is neither.
+func parseEnumValues(columnType string) []string {
+	t := strings.TrimSpace(columnType)
+	open := strings.Index(t, "(")
+	if open < 0 {
+		return nil
+	}
+	if base := strings.ToLower(strings.TrimSpace(t[:open])); base != "enum" && base != "set" {
+		return nil
+	}
+	var values []string
+	var cur strings.Builder
+	inQuote := false
+	for i := open + 1; i < len(t); i++ {
+		c := t[i]
+		switch {
+		case inQuote && c == '\\' && i+1 < len(t):
+			i++
+			cur.WriteByte(t[i])
+		case inQuote && c == '\'' && i+1 < len(t) && t[i+1] == '\'':
+			i++
+			cur.WriteByte('\'')
+		case c == '\'':
+			inQuote = !inQuote
+			if !inQuote {
+				values = append(values, cur.String())
+				cur.Reset()
+			}
+		case inQuote:
+			cur.WriteByte(c)
+		case c == ')':
+			return values
+		}
+	}
+	return values
+}
+
+// unquoteValue strips the single quotes around an SQL string literal and
+// undoes doubled-quote and backslash escapes. Unquoted values are returned
+// unchanged.
+func unquoteValue(v string) string {
+	v = strings.TrimSpace(v)
+	if len(v) < 2 || v[0] != '\'' || v[len(v)-1] != '\'' {
+		return v
+	}
+	if values := parseEnumValues("enum(" + v + ")"); len(values) == 1 {
+		return values[0]
+	}
+	return v
+}
@@ -0,0 +1,25 @@
+package debezium
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEnumValues(t *testing.T) {
+	cases := map[string][]string{
+		"enum('small','medium','large')":     {"small", "medium", "large"},
+		"set('a,b','it''s','back\\\\slash')": {"a,b", "it's", "back\\slash"},
+		"ENUM('')":                           {""},
+		"varchar(10)":                        nil,
+		"settings('x')":                      nil,
+		"enumerated(1)":                      nil,
+	}
+	for in, want := range cases {
+		if got := parseEnumValues(in); !reflect.DeepEqual(got, want) {
+			t.Errorf("parseEnumValues(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := unquoteValue("'it''s'"); got != "it's" {
+		t.Errorf("unquoteValue = %q", got)
+	}
+}
@@ -0,0 +1,306 @@
+package debezium
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+// captureFilter evaluates a connector's database or schema and table
+// include/exclude lists the way Debezium does: each list is a
+// comma-separated set of case-insensitive regular expressions that must
+// match the whole database or schema name, or the whole "db.table" name.
+type captureFilter struct {
+	dbInclude     []*regexp.Regexp
+	dbExclude     []*regexp.Regexp
+	schemaInclude []*regexp.Regexp
+	schemaExclude []*regexp.Regexp
+	tableInclude  []*regexp.Regexp
+	tableExclude  []*regexp.Regexp
+	// postgres is set for Postgres connectors, whose tables are qualified by
+	// schema and filtered by the schema lists; MySQL connectors use the
+	// database lists.
+	postgres bool
+	// literals are table.include.list entries naming a single table, kept so
+	// a named table missing from the source can still be reported.
+	literals [][2]string
+}
+
+// newCaptureFilter builds the filter from a connector's settings. Postgres
+// connectors filter on schema.include.list and schema.exclude.list where
+// MySQL connectors use the database lists; each connector ignores the other
+// kind. Invalid patterns are reported and skipped.
+func newCaptureFilter(connector string, s connectorSettings) (*captureFilter, []string) {
+	f := &captureFilter{postgres: strings.Contains(connectorClass(s.config), "postgresql")}
+	var warnings []string
+	compile := func(key string) []*regexp.Regexp {
+		list, ok := s.get(key)
+		if !ok {
+			return nil
+		}
+		res, errs := compileFilterList(connector, key, list)
+		warnings = append(warnings, errs...)
+		return res
+	}
+	f.dbInclude = compile("database.include.list")
+	f.dbExclude = compile("database.exclude.list")
+	f.schemaInclude = compile("schema.include.list")
+	f.schemaExclude = compile("schema.exclude.list")
+	f.tableInclude = compile("table.include.list")
+	f.tableExclude = compile("table.exclude.list")
+
+	for _, pair := range [][2]string{
+		{"database.include.list", "database.exclude.list"},
+		{"schema.include.list", "schema.exclude.list"},
+		{"table.include.list", "table.exclude.list"},
+	} {
+		_, inc := s.get(pair[0])
+		_, exc := s.get(pair[1])
+		if inc && exc {
+			warnings = append(warnings, fmt.Sprintf("Connector %s sets both configuration keys %s and %s; Debezium rejects this combination", connector, pair[0], pair[1]))
+		}
+	}
+
+	if list, ok := s.get("table.include.list"); ok {
+		for _, entry := range splitFilterList(list) {
+			if db, table, ok := literalTableName(entry); ok {
+				f.literals = append(f.literals, [2]string{db, table})
+			}
+		}
+	}
+	return f, warnings
+}
+
+// splitFilterList splits a Debezium filter list on commas, keeping commas
+// inside regex repetitions such as {1,3}.
+func splitFilterList(list string) []string {
+	var entries []string
+	depth, start := 0, 0
+	for i := 0; i <= len(list); i++ {
+		if i < len(list) {
+			switch list[i] {
+			case '\\':
+				i++
+				continue
+			case '{':
+				depth++
+				continue
+			case '}':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		if entry := strings.TrimSpace(list[start:i]); entry != "" {
+			entries = append(entries, entry)
+		}
+		start = i + 1
+	}
+	return entries
+}
+
+// compileFilterList compiles the entries of a filter list as anchored,
+// case-insensitive regular expressions, reporting invalid entries.
+func compileFilterList(connector, key, list string) ([]*regexp.Regexp, []string) {
+	var res []*regexp.Regexp
+	var warnings []string
+	for _, entry := range splitFilterList(list) {
+		re, err := regexp.Compile("(?i)^(?:" + entry + ")$")
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Connector %s configuration key %s has invalid pattern %q: %v", connector, key, entry, err))
+			continue
+		}
+		res = append(res, re)
+	}
+	return res, warnings
+}
+
+// literalTableName reports whether a table.include.list entry names exactly
+// one table, treating unescaped dots as the db.table separator.
+func literalTableName(entry string) (db, table string, ok bool) {
+	name := strings.ReplaceAll(entry, `\.`, ".")
+	for _, r := range name {
+		if r != '.' && !isIdentChar(r) {
+			return "", "", false
+		}
+	}
+	parts := strings.Split(name, ".")
+	switch len(parts) {
+	case 1:
+		return "", parts[0], parts[0] != ""
+	case 2:
+		return parts[0], parts[1], parts[0] != "" && parts[1] != ""
+	default:
+		return "", "", false
+	}
+}
+
+func matchesAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// captures reports whether the connector captures db.table, where db is the
+// schema for Postgres connectors.
+func (f *captureFilter) captures(db, table string) bool {
+	include, exclude := f.dbInclude, f.dbExclude
+	if f.postgres {
+		include, exclude = f.schemaInclude, f.schemaExclude
+	}
+	if len(include) > 0 && !matchesAny(include, db) {
+		return false
+	}
+	if matchesAny(exclude, db) {
+		return false
+	}
+	qualified := db + "." + table
+	if len(f.tableInclude) > 0 && !matchesAny(f.tableInclude, qualified) {
+		return false
+	}
+	return !matchesAny(f.tableExclude, qualified)
+}
+
+// capturedTables returns the tables of db the connector captures, plus the
+// literal table.include.list entries for db that are not among tables, so
+// that they are reported as captured but missing.
+func (f *captureFilter) capturedTables(db string, tables []string) []string {
+	var captured []string
+	seen := map[string]bool{}
+	for _, t := range tables {
+		if f.captures(db, t) {
+			captured = append(captured, t)
+			seen[strings.ToLower(t)] = true
+		}
+	}
+	for _, lit := range f.literals {
+		if !strings.EqualFold(lit[0], db) || seen[strings.ToLower(lit[1])] {
+			continue
+		}
+		if f.captures(db, lit[1]) {
+			captured = append(captured, lit[1])
+			seen[strings.ToLower(lit[1])] = true
+		}
+	}
+	return captured
+}
+
+// literalTables returns the bare names of the literal table.include.list
+// entries, for when the source table list is not known.
+func (f *captureFilter) literalTables() []string {
+	var names []string
+	for _, lit := range f.literals {
+		names = append(names, lit[1])
+	}
+	return names
+}
+
+var (
+	maskCharsKey = regexp.MustCompile(`^column\.mask\.with\.[0-9]+\.chars$`)
+	maskHashKey  = regexp.MustCompile(`^column\.mask\.hash\.[^.]+\.with\.salt\..+$`)
+	truncateKey  = regexp.MustCompile(`^column\.truncate\.to\.[0-9]+\.chars$`)
+)
+
+// columnRule applies a policy to the columns matching any of its patterns.
+type columnRule struct {
+	policy   cdc.ColumnPolicy
+	patterns []*regexp.Regexp
+}
+
+// columnFilter evaluates a connector's column.include.list,
+// column.exclude.list, column.mask.* and column.truncate.* settings. Like
+// the table lists, their entries are regular expressions matched against the
+// whole "db.table.column" name.
+type columnFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+	rules   []columnRule // in precedence order
+}
+
+func newColumnFilter(connector string, s connectorSettings) (*columnFilter, []string) {
+	f := &columnFilter{}
+	var warnings []string
+	compile := func(key, list string) []*regexp.Regexp {
+		res, errs := compileFilterList(connector, key, list)
+		warnings = append(warnings, errs...)
+		return res
+	}
+	if list, ok := s.get("column.include.list"); ok {
+		f.include = compile("column.include.list", list)
+	}
+	if list, ok := s.get("column.exclude.list"); ok {
+		f.exclude = compile("column.exclude.list", list)
+	}
+	_, inc := s.get("column.include.list")
+	_, exc := s.get("column.exclude.list")
+	if inc && exc {
+		warnings = append(warnings, fmt.Sprintf("Connector %s sets both configuration keys column.include.list and column.exclude.list; Debezium rejects this combination", connector))
+	}
+
+	keys := make([]string, 0, len(s.config))
+	for k := range s.config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, policy := range []struct {
+		policy cdc.ColumnPolicy
+		key    *regexp.Regexp
+	}{
+		{cdc.ColumnHashed, maskHashKey},
+		{cdc.ColumnMasked, maskCharsKey},
+		{cdc.ColumnTruncated, truncateKey},
+	} {
+		for _, k := range keys {
+			if !policy.key.MatchString(k) {
+				continue
+			}
+			if list, ok := s.lookup(k); ok {
+				f.rules = append(f.rules, columnRule{policy: policy.policy, patterns: compile(k, list)})
+			}
+		}
+	}
+	return f, warnings
+}
+
+// policy returns how the connector alters db.table.column, or "" when it
+// emits the column unchanged.
+func (f *columnFilter) policy(db, table, column string) cdc.ColumnPolicy {
+	name := db + "." + table + "." + column
+	if len(f.include) > 0 && !matchesAny(f.include, name) {
+		return cdc.ColumnExcluded
+	}
+	if matchesAny(f.exclude, name) {
+		return cdc.ColumnExcluded
+	}
+	for _, r := range f.rules {
+		if matchesAny(r.patterns, name) {
+			return r.policy
+		}
+	}
+	return ""
+}
+
+// policies returns the altered columns among columns, or nil if none are.
+func (f *columnFilter) policies(db, table string, columns []string) map[string]cdc.ColumnPolicy {
+	var out map[string]cdc.ColumnPolicy
+	for _, c := range columns {
+		if p := f.policy(db, table, c); p != "" {
+			if out == nil {
+				out = map[string]cdc.ColumnPolicy{}
+			}
+			out[c] = p
+		}
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package debezium
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+func TestCaptureFilter(t *testing.T) {
+	source := []string{"orders", "order_items", "orders_archive", "users", "audit_2024"}
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		want   []string
+	}{
+		{"no filters captures everything", map[string]interface{}{}, source},
+		{"regex include", map[string]interface{}{"table.include.list": `shop\.order.*`}, []string{"orders", "order_items", "orders_archive"}},
+		{"include with exclude db", map[string]interface{}{"database.exclude.list": "shop"}, nil},
+		{"database include", map[string]interface{}{"database.include.list": "SHOP"}, source},
+		{"table exclude", map[string]interface{}{"table.exclude.list": `shop\.orders_archive,shop\.audit_[0-9]{4}`}, []string{"orders", "order_items", "users"}},
+		{"include must match whole name", map[string]interface{}{"table.include.list": "shop.order"}, []string{"order"}},
+		{"literal missing from source", map[string]interface{}{"table.include.list": "shop.users,shop.invoices,other.users"}, []string{"users", "invoices"}},
+		{"schema lists ignored by mysql", map[string]interface{}{"schema.include.list": "public"}, source},
+		{"schema lists", map[string]interface{}{"connector.class": "io.debezium.connector.postgresql.PostgresConnector", "schema.include.list": "public"}, nil},
+		{"schema include", map[string]interface{}{"connector.class": "io.debezium.connector.postgresql.PostgresConnector", "schema.include.list": "shop", "database.exclude.list": "shop"}, source},
+	}
+	for _, tt := range tests {
+		f, warnings := newCaptureFilter("c", newConnectorSettings(tt.config, ""))
+		if len(warnings) != 0 {
+			t.Errorf("%s: unexpected warnings %v", tt.name, warnings)
+		}
+		if got := f.capturedTables("shop", source); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestCaptureFilterWarnings(t *testing.T) {
+	_, warnings := newCaptureFilter("c", newConnectorSettings(map[string]interface{}{
+		"table.include.list": "shop.(orders",
+		"table.exclude.list": "shop.tmp_.*",
+	}, ""))
+	if len(warnings) != 2 || !strings.Contains(warnings[0], `invalid pattern "shop.(orders"`) ||
+		!strings.Contains(warnings[1], "both configuration keys table.include.list and table.exclude.list") {
+		t.Fatalf("unexpected warnings %q", warnings)
+	}
+}
+
+func TestSplitFilterList(t *testing.T) {
+	got := splitFilterList(`shop\.a{1,3}, shop\.b\,c ,,shop.d`)
+	want := []string{`shop\.a{1,3}`, `shop\.b\,c`, "shop.d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestColumnFilter(t *testing.T) {
+	f, warnings := newColumnFilter("c", newConnectorSettings(map[string]interface{}{
+		"column.exclude.list":                           `shop\.users\.ssn,shop\..*\.internal_.*`,
+		"column.mask.with.8.chars":                      "shop.users.email",
+		"column.mask.hash.SHA-256.with.salt.CzQMA0cB5K": "shop.users.phone",
+		"column.truncate.to.20.chars":                   "shop.orders.note,shop.users.email",
+	}, ""))
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+	got := f.policies("shop", "users", []string{"id", "ssn", "email", "phone", "internal_flags"})
+	want := map[string]cdc.ColumnPolicy{
+		"ssn":            cdc.ColumnExcluded,
+		"email":          cdc.ColumnMasked,
+		"phone":          cdc.ColumnHashed,
+		"internal_flags": cdc.ColumnExcluded,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if p := f.policy("shop", "orders", "note"); p != cdc.ColumnTruncated {
+		t.Fatalf("expected orders.note truncated, got %q", p)
+	}
+
+	include, _ := newColumnFilter("c", newConnectorSettings(map[string]interface{}{"column.include.list": `shop\.users\.(id|email)`}, ""))
+	if got := include.policies("shop", "users", []string{"id", "email", "ssn"}); !reflect.DeepEqual(got, map[string]cdc.ColumnPolicy{"ssn": cdc.ColumnExcluded}) {
+		t.Fatalf("expected columns outside column.include.list to be excluded, got %v", got)
+	}
+}
@@ -0,0 +1,149 @@
+package debezium
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+// tailRecords is how many records are read from the end of each partition
+// to find the newest event that is not a tombstone.
+const tailRecords = 8
+
+// topicEvent is the newest change event of a topic.
+type topicEvent struct {
+	// Written is when the event was written to Kafka: the record timestamp,
+	// or the envelope's ts_ms when the record has none.
+	Written time.Time
+	// Changed is when the change was made in the source: the envelope's
+	// source.ts_ms, or its ts_ms, or Written when neither can be decoded.
+	Changed time.Time
+	// Fields are the Connect schemas of the row fields, when the event
+	// carries its schema
+	Fields map[string]cdc.FieldSchema
+}
+
+// readLastEvent returns the newest event across the partitions of a topic,
+// or nil when the topic holds no events.
+func readLastEvent(ctx context.Context, topic historyTopic, opts historyOptions) (*topicEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	parts, err := topic.Partitions(ctx)
+	if err == nil {
+		// the newest event may be in any partition
+		err = unreachable(parts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var newest *topicEvent
+	for _, p := range parts {
+		if p.Last <= p.First {
+			continue
+		}
+		e, err := readPartitionTail(ctx, topic, p, opts)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil && (newest == nil || e.Written.After(newest.Written)) {
+			newest = e
+		}
+	}
+	return newest, nil
+}
+
+// readPartitionTail returns the last event in a partition that is not a
+// tombstone, reading at most tailRecords records.
+func readPartitionTail(ctx context.Context, topic historyTopic, p partitionRange, opts historyOptions) (*topicEvent, error) {
+	start := max(p.First, p.Last-tailRecords)
+	r := topic.Open(p.ID, start)
+	defer r.Close()
+
+	var last *topicEvent
+	for next := start; next < p.Last; {
+		msgCtx, cancel := context.WithTimeout(ctx, opts.IdleTimeout)
+		m, err := r.ReadMessage(msgCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				err = fmt.Errorf("timed out after %s", opts.Timeout)
+			}
+			return nil, fmt.Errorf("partition %d stopped at offset %d of %d: %w", p.ID, next, p.Last, err)
+		}
+		next = m.Offset + 1
+		if m.Value == nil {
+			continue
+		}
+		processed, changed := eventTimes(m.Value)
+		e := &topicEvent{Written: m.Time, Changed: changed, Fields: eventFields(m.Value)}
+		if e.Written.IsZero() {
+			e.Written = processed
+		}
+		if e.Changed.IsZero() {
+			e.Changed = processed
+		}
+		if e.Changed.IsZero() {
+			e.Changed = e.Written
+		}
+		last = e
+	}
+	return last, nil
+}
+
+// eventFields returns the Connect schemas of a JSON change event's row fields:
+// those of the envelope's after struct, or of a row flattened by
+// ExtractNewRecordState. Events without the schema envelope have none.
+func eventFields(value []byte) map[string]cdc.FieldSchema {
+	_, schema, ok := decodeStruct(value)
+	if !ok || schema == nil {
+		return nil
+	}
+	if after := schema.field("after"); after != nil {
+		return after.fieldSchemas()
+	}
+	return schema.fieldSchemas()
+}
+
+// eventTimes decodes ts_ms, when Debezium processed the change, and
+// source.ts_ms, when the change was made in the source, from a JSON change
+// event, with or without the schema envelope. Events flattened by
+// ExtractNewRecordState carry them as __ts_ms and __source_ts_ms when
+// add.fields includes them. Times that cannot be decoded are zero.
+func eventTimes(value []byte) (processed, changed time.Time) {
+	var event map[string]json.RawMessage
+	if json.Unmarshal(value, &event) != nil {
+		return time.Time{}, time.Time{}
+	}
+	if payload, ok := event["payload"]; ok {
+		var inner map[string]json.RawMessage
+		if json.Unmarshal(payload, &inner) == nil {
+			event = inner
+		}
+	}
+	processed = millisField(event["ts_ms"])
+	if processed.IsZero() {
+		processed = millisField(event["__ts_ms"])
+	}
+	var src map[string]json.RawMessage
+	if json.Unmarshal(event["source"], &src) == nil {
+		changed = millisField(src["ts_ms"])
+	}
+	if changed.IsZero() {
+		changed = millisField(event["__source_ts_ms"])
+	}
+	return processed, changed
+}
+
+// millisField parses a JSON number of epoch milliseconds.
+func millisField(raw json.RawMessage) time.Time {
+	ms, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
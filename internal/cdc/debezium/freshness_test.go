@@ -0,0 +1,62 @@
+package debezium
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReadLastEvent(t *testing.T) {
+	topic := &fakeHistoryTopic{
+		first: 0,
+		last:  12,
+	}
+	for n := 0; n < 10; n++ {
+		topic.messages = append(topic.messages, []byte(`{"payload":{"op":"c","ts_ms":1760000000000,"source":{"ts_ms":1759999990000}}}`))
+	}
+	topic.messages = append(topic.messages,
+		[]byte(`{"schema":{},"payload":{"op":"d","ts_ms":1760000100500,"source":{"ts_ms":1760000098000}}}`),
+		nil, // tombstone after the delete
+	)
+	e, err := readLastEvent(context.Background(), topic, historyOptions{Timeout: 5 * time.Second, IdleTimeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// fakeReader stamps each record with its offset as the Kafka timestamp
+	if e == nil || !e.Written.Equal(time.Unix(10, 0)) {
+		t.Fatalf("expected the delete at offset 10 to be the newest event, got %+v", e)
+	}
+	if e.Changed.UnixMilli() != 1760000098000 {
+		t.Fatalf("expected the change time from source.ts_ms, got %s", e.Changed)
+	}
+}
+
+func TestEventTimes(t *testing.T) {
+	processed, changed := eventTimes([]byte(`{"payload":{"op":"c","ts_ms":1760000001000,"source":{"ts_ms":1760000000000}}}`))
+	if processed.UnixMilli() != 1760000001000 || changed.UnixMilli() != 1760000000000 {
+		t.Fatalf("expected the envelope ts_ms and source.ts_ms, got %s and %s", processed, changed)
+	}
+	processed, changed = eventTimes([]byte(`{"id":7,"__ts_ms":1760000001000,"__source_ts_ms":1760000000000}`))
+	if processed.UnixMilli() != 1760000001000 || changed.UnixMilli() != 1760000000000 {
+		t.Fatalf("expected the flattened event times, got %s and %s", processed, changed)
+	}
+	if processed, changed := eventTimes([]byte{0x00, 0x01}); !processed.IsZero() || !changed.IsZero() {
+		t.Fatal("expected no times from a non-JSON value")
+	}
+}
+
+func TestEventFields(t *testing.T) {
+	enum := `{"type":"string","optional":true,"name":"io.debezium.data.Enum","parameters":{"allowed":"small,medium,large"},"field":"size"}`
+	envelope := `{"schema":{"type":"struct","fields":[{"type":"struct","fields":[` + enum + `],"field":"before"},{"type":"struct","fields":[` + enum + `],"field":"after"},{"type":"string","field":"op"}]},"payload":{"op":"c","after":{"size":"small"}}}`
+	fields := eventFields([]byte(envelope))
+	if f := fields["size"]; f.Name != "io.debezium.data.Enum" || f.Parameters["allowed"] != "small,medium,large" {
+		t.Fatalf("expected the after struct's size field, got %+v", fields)
+	}
+	flattened := `{"schema":{"type":"struct","fields":[` + enum + `]},"payload":{"size":"small"}}`
+	if fields := eventFields([]byte(flattened)); fields["size"].Name != "io.debezium.data.Enum" {
+		t.Fatalf("expected the flattened row's size field, got %+v", fields)
+	}
+	if fields := eventFields([]byte(`{"op":"c","after":{"size":"small"}}`)); fields != nil {
+		t.Fatalf("expected no fields without a schema, got %+v", fields)
+	}
+}
@@ -0,0 +1,51 @@
+package debezium
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+// defaultHeartbeatPrefix is the heartbeat topic prefix Debezium uses unless
+// topic.heartbeat.prefix (heartbeat.topics.prefix in 1.x) overrides it.
+const defaultHeartbeatPrefix = "__debezium-heartbeat"
+
+// heartbeatSettings returns a connector's heartbeat interval and, when
+// heartbeats are enabled, the topic they are written to:
+// <prefix>.<topic.prefix>.
+func heartbeatSettings(s connectorSettings) *cdc.Heartbeat {
+	hb := &cdc.Heartbeat{}
+	interval, _ := s.get("heartbeat.interval.ms")
+	if ms, err := strconv.ParseInt(interval, 10, 64); err == nil && ms > 0 {
+		hb.IntervalMs = ms
+	}
+	if hb.IntervalMs == 0 {
+		return hb
+	}
+	prefix, ok := s.get("topic.heartbeat.prefix")
+	if !ok || prefix == "" {
+		prefix = defaultHeartbeatPrefix
+	}
+	server, _ := s.get("topic.prefix")
+	hb.Topic = prefix + "." + server
+	return hb
+}
+
+// readHeartbeat reads the newest record of a connector's heartbeat topic
+// into hb.
+func (i *Inspector) readHeartbeat(ctx context.Context, brokersCSV string, hb *cdc.Heartbeat) error {
+	kt, err := newKafkaHistoryTopic(brokersCSV, hb.Topic)
+	if err != nil {
+		return err
+	}
+	e, err := readLastEvent(ctx, kt, historyOptions{Timeout: i.cfg.HistoryTimeout(), IdleTimeout: i.cfg.HistoryIdleTimeout()})
+	if err != nil {
+		return err
+	}
+	hb.Read = true
+	if e != nil {
+		hb.Last = e.Written
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package debezium
+
+import "testing"
+
+func TestHeartbeatSettings(t *testing.T) {
+	hb := heartbeatSettings(newConnectorSettings(map[string]interface{}{"topic.prefix": "dbserver1"}, "2.5.0.Final"))
+	if hb.IntervalMs != 0 || hb.Topic != "" {
+		t.Fatalf("expected heartbeats to be disabled, got %+v", hb)
+	}
+	hb = heartbeatSettings(newConnectorSettings(map[string]interface{}{"topic.prefix": "dbserver1", "heartbeat.interval.ms": "10000"}, "2.5.0.Final"))
+	if hb.IntervalMs != 10000 || hb.Topic != "__debezium-heartbeat.dbserver1" {
+		t.Fatalf("expected the default heartbeat topic, got %+v", hb)
+	}
+	hb = heartbeatSettings(newConnectorSettings(map[string]interface{}{
+		"database.server.name":    "dbserver1",
+		"heartbeat.interval.ms":   "5000",
+		"heartbeat.topics.prefix": "hb",
+	}, "1.9.7.Final"))
+	if hb.Topic != "hb.dbserver1" {
+		t.Fatalf("expected the 1.x heartbeat topic, got %+v", hb)
+	}
+}
@@ -0,0 +1,240 @@
+package debezium
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+// historyRecord is a single Debezium schema history record. Debezium 1.x and
+// 2.x share this shape; 2.x adds ts_ms, table and column comments, default
+// value expressions and enum values. Very old records carry only ddl.
+type historyRecord struct {
+	Source       map[string]any `json:"source"`
+	Position     map[string]any `json:"position"`
+	TsMs         *int64         `json:"ts_ms"`
+	DatabaseName string         `json:"databaseName"`
+	SchemaName   string         `json:"schemaName"`
+	DDL          string         `json:"ddl"`
+	// TableChanges is nil when the record has no tableChanges key, and empty
+	// when the DDL did not change any table (e.g. SET or CREATE DATABASE).
+	TableChanges []historyTableChange `json:"tableChanges"`
+}
+
+type historyTableChange struct {
+	Type  string        `json:"type"` // CREATE, ALTER or DROP
+	ID    string        `json:"id"`   // e.g. "inventory"."customers"
+	Table *historyTable `json:"table"`
+}
+
+type historyTable struct {
+	DefaultCharsetName    string          `json:"defaultCharsetName"`
+	PrimaryKeyColumnNames []string        `json:"primaryKeyColumnNames"`
+	Columns               []historyColumn `json:"columns"`
+}
+
+type historyColumn struct {
+	Name                   string   `json:"name"`
+	JdbcType               int      `json:"jdbcType"`
+	TypeName               string   `json:"typeName"`
+	TypeExpression         string   `json:"typeExpression"`
+	CharsetName            *string  `json:"charsetName"`
+	Length                 *int     `json:"length"`
+	Scale                  *int     `json:"scale"`
+	Position               int      `json:"position"`
+	Optional               bool     `json:"optional"`
+	AutoIncremented        bool     `json:"autoIncremented"`
+	Generated              bool     `json:"generated"`
+	HasDefaultValue        bool     `json:"hasDefaultValue"`
+	DefaultValueExpression *string  `json:"defaultValueExpression"`
+	EnumValues             []string `json:"enumValues"`
+}
+
+// parseHistoryRecord decodes a schema history message value.
+func parseHistoryRecord(value []byte) (*historyRecord, error) {
+	var rec historyRecord
+	if err := json.Unmarshal(value, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// timestamp returns the record's ts_ms when present (Debezium 2.x), falling
+// back to the Kafka message timestamp.
+func (r *historyRecord) timestamp(messageTime time.Time) time.Time {
+	if r.TsMs != nil && *r.TsMs > 0 {
+		return time.UnixMilli(*r.TsMs).UTC()
+	}
+	return messageTime
+}
+
+// defaultDatabase is the database unqualified table names in the DDL refer to.
+func (r *historyRecord) defaultDatabase() string {
+	if r.DatabaseName != "" {
+		return r.DatabaseName
+	}
+	return r.SchemaName
+}
+
+type columnModel struct {
+	name string
+	info cdc.ColumnInfo
+}
+
+type tableModel struct {
+	db      string
+	name    string
+	columns []*columnModel
+	pk      []string
+}
+
+// renumber sets each column's position from its index.
+func (t *tableModel) renumber() {
+	for i, c := range t.columns {
+		c.info.Position = i + 1
+	}
+}
+
+// schemaModel is the in-memory schema built by applying history records in order.
+type schemaModel struct {
+	tables map[string]*tableModel // keyed by tableKey(db, name)
+	times  map[string]time.Time   // last change time, same keys as tables
+}
+
+func newSchemaModel() *schemaModel {
+	return &schemaModel{tables: map[string]*tableModel{}, times: map[string]time.Time{}}
+}
+
+// tableKey identifies a table case-insensitively, as MySQL does on most platforms.
+func tableKey(db, name string) string {
+	return strings.ToLower(db) + "." + strings.ToLower(name)
+}
+
+func (m *schemaModel) put(t *tableModel, ts time.Time) {
+	key := tableKey(t.db, t.name)
+	m.tables[key] = t
+	if prev, ok := m.times[key]; !ok || ts.After(prev) {
+		m.times[key] = ts
+	}
+}
+
+func (m *schemaModel) get(db, name string) *tableModel {
+	return m.tables[tableKey(db, name)]
+}
+
+// apply updates the model with one history record. Structured tableChanges
+// are authoritative; the DDL text is replayed when they are absent, and is
+// otherwise only consulted for table renames.
+func (m *schemaModel) apply(rec *historyRecord, messageTime time.Time) {
+	ts := rec.timestamp(messageTime)
+	if rec.TableChanges == nil {
+		m.applyDDL(rec.DDL, rec.defaultDatabase(), ts)
+		return
+	}
+	applied := map[string]bool{}
+	for _, tc := range rec.TableChanges {
+		db, name := parseTableID(tc.ID)
+		if name == "" {
+			continue
+		}
+		applied[tableKey(db, name)] = true
+		switch strings.ToUpper(tc.Type) {
+		case "DROP":
+			m.drop(db, name)
+		default:
+			if tc.Table != nil {
+				m.put(tableFromHistory(db, name, tc.Table), ts)
+			}
+		}
+	}
+	// A rename is recorded as a change to the new table only, so drop the
+	// old name using the DDL text.
+	for _, old := range renamedTables(rec.DDL, rec.defaultDatabase()) {
+		if !applied[tableKey(old[0], old[1])] {
+			m.drop(old[0], old[1])
+		}
+	}
+}
+
+func (m *schemaModel) drop(db, name string) {
+	delete(m.tables, tableKey(db, name))
+	delete(m.times, tableKey(db, name))
+}
+
+// tableFromHistory converts a structured tableChanges table into the model.
+func tableFromHistory(db, name string, ht *historyTable) *tableModel {
+	t := &tableModel{db: db, name: name, pk: append([]string(nil), ht.PrimaryKeyColumnNames...)}
+	for _, hc := range ht.Columns {
+		info := cdc.ColumnInfo{
+			Type:     strings.ToUpper(strings.TrimSpace(hc.TypeName)),
+			Nullable: hc.Optional,
+			Length:   hc.Length,
+			Scale:    hc.Scale,
+			Position: hc.Position,
+		}
+		if hc.CharsetName != nil {
+			info.Charset = *hc.CharsetName
+		}
+		if hc.HasDefaultValue && hc.DefaultValueExpression != nil {
+			info.Default = hc.DefaultValueExpression
+		}
+		// Debezium records enum values as quoted literals
+		for _, v := range hc.EnumValues {
+			info.EnumValues = append(info.EnumValues, unquoteValue(v))
+		}
+		t.columns = append(t.columns, &columnModel{name: hc.Name, info: info})
+	}
+	return t
+}
+
+// parseTableID splits a Debezium table id such as "inventory"."customers"
+// into its database (or schema) and table name.
+func parseTableID(id string) (db, table string) {
+	var parts []string
+	rest := id
+	for strings.TrimSpace(rest) != "" {
+		part, r, _ := readIdent(rest)
+		if part == "" {
+			break
+		}
+		parts = append(parts, part)
+		rest = strings.TrimPrefix(strings.TrimSpace(r), ".")
+	}
+	switch len(parts) {
+	case 0:
+		return "", ""
+	case 1:
+		return "", parts[0]
+	default:
+		return parts[len(parts)-2], parts[len(parts)-1]
+	}
+}
+
+// schemas returns the current model as CDC table schemas and last change
+// times, keyed by "db.table" so tables of the same name in different
+// databases stay apart.
+func (m *schemaModel) schemas() (map[string]cdc.TableSchema, map[string]time.Time) {
+	return m.filteredSchemas(nil)
+}
+
+// filteredSchemas is schemas restricted to the tables keep accepts; a nil
+// keep accepts every table.
+func (m *schemaModel) filteredSchemas(keep func(db, name string) bool) (map[string]cdc.TableSchema, map[string]time.Time) {
+	schemas := map[string]cdc.TableSchema{}
+	times := map[string]time.Time{}
+	for key, t := range m.tables {
+		if len(t.columns) == 0 || (keep != nil && !keep(t.db, t.name)) {
+			continue
+		}
+		cols := map[string]cdc.ColumnInfo{}
+		for _, c := range t.columns {
+			cols[c.name] = c.info
+		}
+		name := t.db + "." + t.name
+		schemas[name] = cdc.TableSchema{Columns: cols, PrimaryKey: t.pk}
+		times[name] = m.times[key]
+	}
+	return schemas, times
+}
@@ -0,0 +1,201 @@
+package debezium
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+// replayFixture applies every record in a JSON-lines history fixture to a new
+// model. Messages are stamped one minute apart starting at base.
+func replayFixture(t *testing.T, path string, base time.Time) *schemaModel {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	model := newSchemaModel()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for n := 0; sc.Scan(); n++ {
+		rec, err := parseHistoryRecord(sc.Bytes())
+		if err != nil {
+			t.Fatalf("%s line %d: %v", path, n+1, err)
+		}
+		model.apply(rec, base.Add(time.Duration(n)*time.Minute))
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+func intPtr(n int) *int { return &n }
+
+func TestHistoryTableChangesV1(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	schemas, times := replayFixture(t, "testdata/history_v1.jsonl", base).schemas()
+	if len(schemas) != 2 {
+		t.Fatalf("expected customers and orders, got %v", schemas)
+	}
+	customers := schemas["inventory.customers"]
+	if !reflect.DeepEqual(customers.PrimaryKey, []string{"id"}) {
+		t.Fatalf("expected primary key [id], got %v", customers.PrimaryKey)
+	}
+	want := cdc.ColumnInfo{Type: "DECIMAL", Nullable: true, Length: intPtr(10), Scale: intPtr(2), Position: 4}
+	if got := customers.Columns["balance"]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected balance %+v, got %+v", want, got)
+	}
+	if got := customers.Columns["email"]; got.Charset != "latin1" || got.Nullable {
+		t.Fatalf("unexpected email column %+v", got)
+	}
+	// 1.x records carry no ts_ms, so the message timestamp is used
+	if !times["inventory.orders"].Equal(base.Add(3 * time.Minute)) {
+		t.Fatalf("expected orders timestamp from message time, got %v", times["inventory.orders"])
+	}
+}
+
+func TestHistoryTableChangesV2(t *testing.T) {
+	schemas, times := replayFixture(t, "testdata/history_v2.jsonl", time.Now()).schemas()
+	if _, ok := schemas["inventory.tmp_import"]; ok {
+		t.Fatalf("expected dropped table tmp_import to be removed")
+	}
+	products, ok := schemas["inventory.products"]
+	if !ok {
+		t.Fatalf("expected products schema, got %v", schemas)
+	}
+	if len(products.Columns) != 5 {
+		t.Fatalf("expected ALTER to leave 5 columns, got %v", products.Columns)
+	}
+	if got := products.Columns["id"].Type; got != "BIGINT UNSIGNED" {
+		t.Fatalf("expected BIGINT UNSIGNED id, got %s", got)
+	}
+	if got := products.Columns["sku"]; !got.Nullable || got.Length == nil || *got.Length != 32 {
+		t.Fatalf("unexpected sku column %+v", got)
+	}
+	if got := products.Columns["size"].EnumValues; !reflect.DeepEqual(got, []string{"small", "medium", "large"}) {
+		t.Fatalf("expected unquoted enum values, got %q", got)
+	}
+	// 2.x records carry ts_ms, which takes precedence over the message time
+	if want := time.UnixMilli(1700001000000).UTC(); !times["inventory.products"].Equal(want) {
+		t.Fatalf("expected products timestamp %v, got %v", want, times["inventory.products"])
+	}
+}
+
+func TestHistoryDDLFallback(t *testing.T) {
+	schemas, _ := replayFixture(t, "testdata/history_ddl.jsonl", time.Now()).schemas()
+	items, ok := schemas["shop.line_items"]
+	if !ok {
+		t.Fatalf("expected line_items schema, got %v", schemas)
+	}
+	if !reflect.DeepEqual(items.PrimaryKey, []string{"order_id", "line_no"}) {
+		t.Fatalf("expected composite primary key, got %v", items.PrimaryKey)
+	}
+	wantCols := map[string]cdc.ColumnInfo{
+		"order_id": {Type: "INT", Nullable: false, Length: intPtr(11), Position: 1},
+		"line_no":  {Type: "SMALLINT UNSIGNED", Nullable: false, Position: 2},
+		"price":    {Type: "DECIMAL", Nullable: false, Length: intPtr(10), Scale: intPtr(2), Position: 3},
+		"status":   {Type: "ENUM", Nullable: true, Position: 4, Charset: "utf8mb4", EnumValues: []string{"new", "paid", "shipped"}},
+		"note":     {Type: "TEXT", Nullable: true, Position: 5},
+	}
+	if !reflect.DeepEqual(items.Columns, wantCols) {
+		t.Fatalf("expected columns %+v, got %+v", wantCols, items.Columns)
+	}
+
+	tags := schemas["shop.tags"]
+	if !reflect.DeepEqual(tags.PrimaryKey, []string{"id"}) || tags.Columns["id"].Nullable {
+		t.Fatalf("expected inline primary key on tags.id, got %+v", tags)
+	}
+}
+
+func TestHistoryDDLReplay(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	schemas, times := replayFixture(t, "testdata/history_replay.jsonl", base).schemas()
+	for _, gone := range []string{"audit", "scratch", "scratch_old"} {
+		if _, ok := schemas["shop."+gone]; ok {
+			t.Fatalf("expected %s to be renamed or dropped, got %v", gone, schemas["shop."+gone])
+		}
+	}
+
+	accounts, ok := schemas["shop.accounts"]
+	if !ok {
+		t.Fatalf("expected accounts schema, got %v", schemas)
+	}
+	wantCols := map[string]cdc.ColumnInfo{
+		"account_id": {Type: "INT", Nullable: false, Position: 1},
+		"created_at": {Type: "DATETIME", Nullable: false, Position: 2},
+		"email":      {Type: "VARCHAR", Nullable: false, Length: intPtr(255), Position: 3},
+		"full_name":  {Type: "VARCHAR", Nullable: true, Length: intPtr(80), Position: 4},
+	}
+	if !reflect.DeepEqual(accounts.Columns, wantCols) {
+		t.Fatalf("expected columns %+v, got %+v", wantCols, accounts.Columns)
+	}
+	if !reflect.DeepEqual(accounts.PrimaryKey, []string{"account_id"}) {
+		t.Fatalf("expected renamed primary key column, got %v", accounts.PrimaryKey)
+	}
+	// TRUNCATE does not change the schema, so the last change is the column rename
+	if !times["shop.accounts"].Equal(base.Add(3 * time.Minute)) {
+		t.Fatalf("expected accounts timestamp from the RENAME COLUMN record, got %v", times["shop.accounts"])
+	}
+
+	auditLog, ok := schemas["shop.audit_log"]
+	if !ok || len(auditLog.Columns) != 2 || !reflect.DeepEqual(auditLog.PrimaryKey, []string{"id"}) {
+		t.Fatalf("expected audit renamed to audit_log with its columns, got %+v", auditLog)
+	}
+}
+
+func TestHistoryStructuredRename(t *testing.T) {
+	schemas, _ := replayFixture(t, "testdata/history_rename.jsonl", time.Now()).schemas()
+	if _, ok := schemas["inventory.products"]; ok {
+		t.Fatalf("expected products to be dropped after rename")
+	}
+	if _, ok := schemas["inventory.catalog_products"]; !ok {
+		t.Fatalf("expected catalog_products schema, got %v", schemas)
+	}
+}
+
+func TestSplitTopLevel(t *testing.T) {
+	got := splitTopLevel("a decimal(10,2), b enum('x,y','z'), c int", ',')
+	want := []string{"a decimal(10,2)", " b enum('x,y','z')", " c int"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestHistorySchemasKeyedByDatabase(t *testing.T) {
+	m := newSchemaModel()
+	m.applyDDL("CREATE TABLE shop.orders (id INT PRIMARY KEY)", "", time.Now())
+	m.applyDDL("CREATE TABLE archive.orders (id INT PRIMARY KEY, closed_at DATETIME)", "", time.Now())
+	schemas, _ := m.schemas()
+	if len(schemas["shop.orders"].Columns) != 1 || len(schemas["archive.orders"].Columns) != 2 {
+		t.Fatalf("expected both orders tables, got %v", schemas)
+	}
+}
+
+func TestColumnPosition(t *testing.T) {
+	cases := []struct {
+		def   string
+		first bool
+		after string
+	}{
+		{"`c` INT FIRST", true, ""},
+		{"c INT NOT NULL after `b`", false, "b"},
+		{"c INT COMMENT 'goes AFTER b'", false, ""},
+		{"c INT DEFAULT 'first'", false, ""},
+		{"c INT AS (first + 1) VIRTUAL", false, ""},
+		{"c INT AS (a AFTER b)", false, ""},
+		{"`first` INT", false, ""},
+		{"c INT COMMENT 'x' AFTER a;", false, "a"},
+	}
+	for _, tc := range cases {
+		first, after := columnPosition(tc.def)
+		if first != tc.first || after != tc.after {
+			t.Errorf("%q: expected (%v, %q), got (%v, %q)", tc.def, tc.first, tc.after, first, after)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package debezium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// partitionRange is the span of offsets currently stored in one partition.
+// Last is the high-water mark: the offset the next message will get. Err is
+// set, and the span empty, when the partition's offsets could not be read.
+type partitionRange struct {
+	ID    int
+	First int64
+	Last  int64
+	Err   error
+}
+
+// unreachable joins the errors of the partitions whose offsets could not be
+// read, for reads that need every partition.
+func unreachable(parts []partitionRange) error {
+	var errs []error
+	for _, p := range parts {
+		if p.Err != nil {
+			errs = append(errs, p.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// messageReader reads messages from one partition in offset order.
+type messageReader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+	Close() error
+}
+
+// historyTopic is the access to a schema history topic needed to replay it.
+type historyTopic interface {
+	Partitions(ctx context.Context) ([]partitionRange, error)
+	Open(partition int, offset int64) messageReader
+}
+
+// historyOptions bound a schema history read.
+type historyOptions struct {
+	Timeout     time.Duration // whole read
+	IdleTimeout time.Duration // wait for each message
+	Progress    io.Writer     // optional
+}
+
+// progressEvery is how many records are read between progress lines.
+const progressEvery = 5000
+
+// historyRead is the outcome of replaying a schema history topic.
+type historyRead struct {
+	Model      *schemaModel
+	Partitions int
+	Records    int64 // records read
+	Expected   int64 // records between the first offsets and the high-water marks
+	// Truncated explains why the model may be missing records, one entry per
+	// cause; empty when every record up to the high-water mark was replayed.
+	Truncated []string
+}
+
+// readHistory replays every partition of a schema history topic from its
+// first offset up to the high-water mark observed at the start of the read.
+// Records produced after that point are left for the next run, so the result
+// is deterministic for a given topic state.
+func readHistory(ctx context.Context, topic historyTopic, name string, opts historyOptions) (*historyRead, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	parts, err := topic.Partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	read := &historyRead{Model: newSchemaModel(), Partitions: len(parts)}
+	for _, p := range parts {
+		if p.Err != nil {
+			read.Truncated = append(read.Truncated, p.Err.Error())
+			continue
+		}
+		read.Expected += p.Last - p.First
+		if p.First > 0 {
+			// Debezium requires infinite retention on this topic; anything
+			// before the first offset has been deleted.
+			read.Truncated = append(read.Truncated, fmt.Sprintf("partition %d starts at offset %d; earlier records were deleted by retention", p.ID, p.First))
+		}
+	}
+
+	for _, p := range parts {
+		if p.Last <= p.First {
+			continue
+		}
+		if reason := read.readPartition(ctx, topic, name, p, opts); reason != "" {
+			read.Truncated = append(read.Truncated, reason)
+		}
+	}
+	if opts.Progress != nil && read.Expected > 0 {
+		fmt.Fprintf(opts.Progress, "schema history %s: read %d/%d records\n", name, read.Records, read.Expected)
+	}
+	return read, nil
+}
+
+// readPartition applies the records of one partition to the model. It
+// returns why the read stopped short of the high-water mark, if it did.
+func (h *historyRead) readPartition(ctx context.Context, topic historyTopic, name string, p partitionRange, opts historyOptions) string {
+	r := topic.Open(p.ID, p.First)
+	defer r.Close()
+
+	next := p.First
+	for next < p.Last {
+		msgCtx, cancel := context.WithTimeout(ctx, opts.IdleTimeout)
+		m, err := r.ReadMessage(msgCtx)
+		cancel()
+		if err != nil {
+			switch {
+			case ctx.Err() != nil:
+				err = fmt.Errorf("timed out after %s", opts.Timeout)
+			case errors.Is(err, context.DeadlineExceeded):
+				err = fmt.Errorf("no record within %s", opts.IdleTimeout)
+			}
+			return fmt.Sprintf("partition %d stopped at offset %d of %d: %v", p.ID, next, p.Last, err)
+		}
+		next = m.Offset + 1
+		h.Records++
+		if opts.Progress != nil && h.Records%progressEvery == 0 {
+			fmt.Fprintf(opts.Progress, "schema history %s: read %d/%d records\n", name, h.Records, h.Expected)
+		}
+		rec, err := parseHistoryRecord(m.Value)
+		if err != nil {
+			// skip non-json messages
+			continue
+		}
+		h.Model.apply(rec, m.Time)
+	}
+	return ""
+}
+
+// kafkaHistoryTopic reads a schema history topic from a Kafka cluster.
+type kafkaHistoryTopic struct {
+	brokers []string
+	topic   string
+}
+
+func newKafkaHistoryTopic(brokersCSV, topic string) (*kafkaHistoryTopic, error) {
+	var brokers []string
+	for _, b := range strings.Split(brokersCSV, ",") {
+		b = strings.TrimSpace(b)
+		if b != "" {
+			brokers = append(brokers, b)
+		}
+	}
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("no kafka brokers provided")
+	}
+	return &kafkaHistoryTopic{brokers: brokers, topic: topic}, nil
+}
+
+// Partitions looks up the topic's partitions and their offsets, trying each
+// broker in turn. A partition whose leader cannot be reached is returned
+// with its error, so the others can still be read; an error is returned
+// when none can.
+func (k *kafkaHistoryTopic) Partitions(ctx context.Context) ([]partitionRange, error) {
+	var lastErr error
+	for _, broker := range k.brokers {
+		parts, err := kafka.LookupPartitions(ctx, "tcp", broker, k.topic)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if len(parts) == 0 {
+			return nil, fmt.Errorf("kafka topic %s has no partitions", k.topic)
+		}
+		var ranges []partitionRange
+		failed := 0
+		for _, p := range parts {
+			first, last, err := k.readOffsets(ctx, broker, p.ID)
+			if err != nil {
+				failed++
+				ranges = append(ranges, partitionRange{ID: p.ID, Err: fmt.Errorf("partition %d unreachable: %w", p.ID, err)})
+				continue
+			}
+			ranges = append(ranges, partitionRange{ID: p.ID, First: first, Last: last})
+		}
+		if failed == len(ranges) {
+			return nil, fmt.Errorf("kafka topic %s: %w", k.topic, unreachable(ranges))
+		}
+		return ranges, nil
+	}
+	return nil, lastErr
+}
+
+// readOffsets reads the first offset and high-water mark of a partition from
+// its leader.
+func (k *kafkaHistoryTopic) readOffsets(ctx context.Context, broker string, partition int) (int64, int64, error) {
+	conn, err := kafka.DialLeader(ctx, "tcp", broker, k.topic, partition)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer conn.Close()
+	return conn.ReadOffsets()
+}
+
+func (k *kafkaHistoryTopic) Open(partition int, offset int64) messageReader {
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   k.brokers,
+		Topic:     k.topic,
+		Partition: partition,
+		MinBytes:  1,
+		MaxBytes:  10e6, // 10MB
+	})
+	// SetOffset only fails once the reader is closed or in a consumer group
+	_ = r.SetOffset(offset)
+	return r
+}
+
+// historyWarnings reports a history read that may not reflect the current
+// schema: a truncated read, or a topic with more than one partition, whose
+// records Debezium cannot order.
+func historyWarnings(connector, topic string, read *historyRead) []string {
+	var warnings []string
+	if read.Partitions > 1 {
+		warnings = append(warnings, fmt.Sprintf("Connector %s schema history topic %s has %d partitions; Debezium requires a single partition, so DDL may be replayed out of order", connector, topic, read.Partitions))
+	}
+	if len(read.Truncated) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Connector %s schema history topic %s truncated: read %d of %d records (%s); CDC schemas may be incomplete", connector, topic, read.Records, read.Expected, strings.Join(read.Truncated, "; ")))
+	}
+	return warnings
+}
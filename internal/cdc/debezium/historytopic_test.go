@@ -0,0 +1,154 @@
+package debezium
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// fakeHistoryTopic serves one partition from a slice of messages whose
+// offsets start at first. Reads past the end block until the context ends,
+// as a Kafka reader waiting for new records does.
+type fakeHistoryTopic struct {
+	first    int64
+	last     int64 // high-water mark reported by Partitions
+	messages [][]byte
+	keys     [][]byte // optional, parallel to messages
+	down     error    // optional, reported for an unreachable partition 1
+}
+
+func (f *fakeHistoryTopic) Partitions(ctx context.Context) ([]partitionRange, error) {
+	parts := []partitionRange{{ID: 0, First: f.first, Last: f.last}}
+	if f.down != nil {
+		parts = append(parts, partitionRange{ID: 1, Err: f.down})
+	}
+	return parts, nil
+}
+
+func (f *fakeHistoryTopic) Open(partition int, offset int64) messageReader {
+	return &fakeReader{topic: f, next: offset}
+}
+
+type fakeReader struct {
+	topic *fakeHistoryTopic
+	next  int64
+}
+
+func (r *fakeReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	idx := r.next - r.topic.first
+	if idx >= int64(len(r.topic.messages)) {
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	m := kafka.Message{Offset: r.next, Value: r.topic.messages[idx], Time: time.Unix(r.next, 0)}
+	if r.topic.keys != nil {
+		m.Key = r.topic.keys[idx]
+	}
+	r.next++
+	return m, nil
+}
+
+func (r *fakeReader) Close() error { return nil }
+
+func fixtureMessages(t *testing.T, path string) [][]byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs [][]byte
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		msgs = append(msgs, []byte(line))
+	}
+	return msgs
+}
+
+func TestReadHistoryToHighWaterMark(t *testing.T) {
+	msgs := fixtureMessages(t, "testdata/history_replay.jsonl")
+	topic := &fakeHistoryTopic{last: int64(len(msgs)), messages: msgs}
+	var progress bytes.Buffer
+	read, err := readHistory(context.Background(), topic, "schema-changes.shop", historyOptions{
+		Timeout:     5 * time.Second,
+		IdleTimeout: time.Second,
+		Progress:    &progress,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read.Truncated) != 0 || read.Records != int64(len(msgs)) {
+		t.Fatalf("expected a complete read of %d records, got %d (%v)", len(msgs), read.Records, read.Truncated)
+	}
+	if schemas, _ := read.Model.schemas(); len(schemas) != 2 {
+		t.Fatalf("expected accounts and audit_log, got %v", schemas)
+	}
+	if !strings.Contains(progress.String(), "read 10/10 records") {
+		t.Fatalf("expected a final progress line, got %q", progress.String())
+	}
+	if w := historyWarnings("inventory-connector", "schema-changes.shop", read); len(w) != 0 {
+		t.Fatalf("expected no warnings, got %v", w)
+	}
+}
+
+func TestReadHistoryTruncated(t *testing.T) {
+	msgs := fixtureMessages(t, "testdata/history_replay.jsonl")
+	// The high-water mark is past the records the reader can deliver, so the
+	// read stalls and the idle timeout ends it.
+	topic := &fakeHistoryTopic{last: int64(len(msgs)) + 5, messages: msgs}
+	read, err := readHistory(context.Background(), topic, "schema-changes.shop", historyOptions{
+		Timeout:     5 * time.Second,
+		IdleTimeout: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read.Truncated) != 1 || !strings.Contains(read.Truncated[0], "stopped at offset 10 of 15") {
+		t.Fatalf("expected an idle truncation, got %v", read.Truncated)
+	}
+	w := historyWarnings("inventory-connector", "schema-changes.shop", read)
+	if len(w) != 1 || !strings.Contains(w[0], "truncated: read 10 of 15 records") {
+		t.Fatalf("expected a truncation warning, got %v", w)
+	}
+}
+
+func TestReadHistoryRetentionGap(t *testing.T) {
+	msgs := fixtureMessages(t, "testdata/history_replay.jsonl")[3:]
+	topic := &fakeHistoryTopic{first: 3, last: 10, messages: msgs}
+	read, err := readHistory(context.Background(), topic, "schema-changes.shop", historyOptions{
+		Timeout:     5 * time.Second,
+		IdleTimeout: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read.Truncated) != 1 || !strings.Contains(read.Truncated[0], "deleted by retention") {
+		t.Fatalf("expected a retention truncation, got %v", read.Truncated)
+	}
+}
+
+func TestReadHistoryUnreachablePartition(t *testing.T) {
+	msgs := fixtureMessages(t, "testdata/history_replay.jsonl")
+	down := errors.New("partition 1 unreachable: leader not available")
+	topic := &fakeHistoryTopic{last: int64(len(msgs)), messages: msgs, down: down}
+	read, err := readHistory(context.Background(), topic, "schema-changes.shop", historyOptions{
+		Timeout:     5 * time.Second,
+		IdleTimeout: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The reachable partition is still replayed
+	if read.Records != int64(len(msgs)) || len(read.Truncated) != 1 || !strings.Contains(read.Truncated[0], "leader not available") {
+		t.Fatalf("expected partition 0 read and partition 1 reported, got %d records (%v)", read.Records, read.Truncated)
+	}
+
+	// Data topic reads need every partition
+	if _, _, err := countEvents(context.Background(), topic, historyOptions{Timeout: time.Second}); !errors.Is(err, down) {
+		t.Fatalf("expected the unreachable partition to fail the count, got %v", err)
+	}
+}
@@ -4,19 +4,29 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
-	"regexp"
+	"sort"
 	"strings"
 	"time"
 
-	kafka "github.com/segmentio/kafka-go"
-
 	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
 	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
 )
 
 type Inspector struct {
-	cfg config.CDCConfig
+	cfg      config.CDCConfig
+	progress io.Writer
+	// sourceDB and sourceTables are the inspected source tables, when known;
+	// connector filters are evaluated against them. otherTables are the
+	// names of the source tables that are not inspected.
+	sourceDB     string
+	sourceTables []source.TableInfo
+	otherTables  []string
+	// topicBrokers are the brokers of each data topic found by
+	// InspectConnectors, for ReplayTopic.
+	topicBrokers map[string]string
 }
 
 type ConnectorConfig struct {
@@ -31,6 +41,38 @@ func (i *Inspector) Name() string {
 	return "debezium"
 }
 
+// SetSource sets the source database and tables that connector table and
+// column filters are evaluated against. Of otherTables only the names that
+// connectors capture are reported.
+func (i *Inspector) SetSource(database string, tables []source.TableInfo, otherTables []string) {
+	i.sourceDB = database
+	i.sourceTables = append([]source.TableInfo{}, tables...)
+	i.otherTables = append([]string{}, otherTables...)
+}
+
+// sourceTableNames returns the names of the source tables.
+func (i *Inspector) sourceTableNames() []string {
+	names := make([]string, 0, len(i.sourceTables))
+	for _, t := range i.sourceTables {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// warningsOf returns messages as warnings of the given kind.
+func warningsOf(kind cdc.WarningKind, messages []string) []cdc.Warning {
+	var out []cdc.Warning
+	for _, m := range messages {
+		out = append(out, cdc.Warning{Kind: kind, Message: m})
+	}
+	return out
+}
+
+// SetProgress reports schema history replay progress to w.
+func (i *Inspector) SetProgress(w io.Writer) {
+	i.progress = w
+}
+
 func (i *Inspector) Inspect(ctx context.Context) (*cdc.Result, error) {
 	// Delegate to InspectConnectors and aggregate for backward compatibility
 	crs, err := i.InspectConnectors(ctx)
@@ -38,15 +80,24 @@ func (i *Inspector) Inspect(ctx context.Context) (*cdc.Result, error) {
 		return nil, err
 	}
 	// Aggregate
-	var capturedTables []string
+	var capturedTables, capturedOther []string
 	tableSchemas := map[string]cdc.TableSchema{}
 	schemaTimes := map[string]time.Time{}
-	var warnings []string
+	eventCounts := map[string]int64{}
+	keyCounts := map[string]int64{}
+	dataTopics := map[string]string{}
+	lastEvents := map[string]time.Time{}
+	lagSeconds := map[string]float64{}
+	keyColumns := map[string][]string{}
+	columnPolicies := map[string]map[string]cdc.ColumnPolicy{}
+	emitSettings := map[string]map[string]string{}
+	var warnings []cdc.Warning
 	reachable := false
 	for _, cr := range crs {
 		if cr.Result != nil {
 			reachable = reachable || cr.Result.ConnectorReachable
 			capturedTables = append(capturedTables, cr.Result.CapturedTables...)
+			capturedOther = append(capturedOther, cr.Result.CapturedOther...)
 			if cr.Result.TableSchemas != nil {
 				for k, v := range cr.Result.TableSchemas {
 					tableSchemas[k] = v
@@ -57,18 +108,66 @@ func (i *Inspector) Inspect(ctx context.Context) (*cdc.Result, error) {
 					schemaTimes[k] = v
 				}
 			}
+			for k, v := range cr.Result.EventCounts {
+				eventCounts[k] = v
+			}
+			for k, v := range cr.Result.KeyCounts {
+				keyCounts[k] = v
+			}
+			for k, v := range cr.Result.DataTopics {
+				dataTopics[k] = v
+			}
+			for k, v := range cr.Result.LastEventTimes {
+				lastEvents[k] = v
+			}
+			for k, v := range cr.Result.LagSeconds {
+				lagSeconds[k] = v
+			}
+			for k, v := range cr.Result.KeyColumns {
+				keyColumns[k] = v
+			}
+			for k, v := range cr.Result.ColumnPolicies {
+				columnPolicies[k] = v
+			}
+			for k, v := range cr.Result.EmitSettings {
+				emitSettings[k] = v
+			}
 			if len(cr.Result.Warnings) > 0 {
 				warnings = append(warnings, cr.Result.Warnings...)
 			}
 		}
 	}
-	res := &cdc.Result{ConnectorReachable: reachable, CapturedTables: capturedTables}
+	res := &cdc.Result{ConnectorReachable: reachable, CapturedTables: capturedTables, CapturedOther: capturedOther}
 	if len(tableSchemas) > 0 {
 		res.TableSchemas = tableSchemas
 	}
 	if len(schemaTimes) > 0 {
 		res.SchemaTimestamps = schemaTimes
 	}
+	if len(eventCounts) > 0 {
+		res.EventCounts = eventCounts
+	}
+	if len(keyCounts) > 0 {
+		res.KeyCounts = keyCounts
+	}
+	if len(dataTopics) > 0 {
+		res.DataTopics = dataTopics
+	}
+	if len(lastEvents) > 0 {
+		res.LastEventTimes = lastEvents
+	}
+	if len(lagSeconds) > 0 {
+		res.LagSeconds = lagSeconds
+	}
+	if len(keyColumns) > 0 {
+		res.KeyColumns = keyColumns
+	}
+	if len(columnPolicies) > 0 {
+		res.ColumnPolicies = columnPolicies
+	}
+	if len(emitSettings) > 0 {
+		res.EmitSettings = emitSettings
+	}
 	if len(warnings) > 0 {
 		res.Warnings = warnings
 	}
@@ -85,7 +184,7 @@ func (i *Inspector) InspectConnectors(ctx context.Context) ([]*cdc.ConnectorResu
 	resp, err := client.Do(req)
 	if err != nil {
 		// Return a single entry indicating unreachable
-		return []*cdc.ConnectorResult{{Name: "", Result: &cdc.Result{ConnectorReachable: false, Warnings: []string{err.Error()}}}}, nil
+		return []*cdc.ConnectorResult{{Name: "", Result: &cdc.Result{ConnectorReachable: false, Warnings: []cdc.Warning{{Kind: cdc.WarningConnector, Message: err.Error()}}}}}, nil
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
@@ -97,6 +196,10 @@ func (i *Inspector) InspectConnectors(ctx context.Context) ([]*cdc.ConnectorResu
 		return nil, err
 	}
 
+	// Plugin versions tell which generation of config keys each connector reads
+	pluginVersions := fetchPluginVersions(ctx, client, i.cfg.ConnectURL)
+	offsets := &offsetReader{i: i, client: client}
+
 	var results []*cdc.ConnectorResult
 	for _, connector := range connectors {
 		cr := &cdc.ConnectorResult{Name: connector, Result: &cdc.Result{ConnectorReachable: true}}
@@ -121,19 +224,33 @@ func (i *Inspector) InspectConnectors(ctx context.Context) ([]*cdc.ConnectorResu
 			continue
 		}
 
-		// Extract table.include.list from config
-		if tableList, ok := connConfig.Config["table.include.list"]; ok {
-			if tableListStr, ok := tableList.(string); ok {
-				tables := strings.Split(tableListStr, ",")
-				for _, table := range tables {
-					table = strings.TrimSpace(table)
-					if parts := strings.Split(table, "."); len(parts) == 2 {
-						cr.Result.CapturedTables = append(cr.Result.CapturedTables, parts[1])
-					} else {
-						cr.Result.CapturedTables = append(cr.Result.CapturedTables, table)
-					}
+		settings := newConnectorSettings(connConfig.Config, pluginVersions[connectorClass(connConfig.Config)])
+		cr.Result.Warnings = append(cr.Result.Warnings, warningsOf(cdc.WarningConfig, configKeyWarnings(connector, settings))...)
+		cr.Result.DatabaseUser, _ = settings.get("database.user")
+
+		// Evaluate the include/exclude lists against the source tables when
+		// they are known; otherwise only literal table.include.list entries
+		// can be resolved.
+		filter, filterWarnings := newCaptureFilter(connector, settings)
+		cr.Result.Warnings = append(cr.Result.Warnings, warningsOf(cdc.WarningConfig, filterWarnings)...)
+		qualified := map[string]string{}
+		if i.sourceTables != nil {
+			cr.Result.CapturedTables = filter.capturedTables(i.sourceDB, i.sourceTableNames())
+			for _, t := range cr.Result.CapturedTables {
+				qualified[t] = i.sourceDB + "." + t
+			}
+			for _, t := range i.otherTables {
+				if filter.captures(i.sourceDB, t) {
+					cr.Result.CapturedOther = append(cr.Result.CapturedOther, t)
+				}
+			}
+		} else {
+			for _, lit := range filter.literals {
+				if lit[0] != "" {
+					qualified[lit[1]] = lit[0] + "." + lit[1]
 				}
 			}
+			cr.Result.CapturedTables = filter.literalTables()
 		}
 
 		// Validate snapshot.mode for this connector and warn if disabled or schema-only
@@ -141,7 +258,7 @@ func (i *Inspector) InspectConnectors(ctx context.Context) ([]*cdc.ConnectorResu
 			if smStr, ok := sm.(string); ok {
 				smVal := strings.ToLower(strings.TrimSpace(smStr))
 				if smVal == "never" || smVal == "none" || smVal == "schema_only" || smVal == "schema_only_recovery" || smVal == "off" {
-					cr.Result.Warnings = append(cr.Result.Warnings, fmt.Sprintf("Connector %s has snapshot.mode=%s; snapshots disabled or schema-only (CDC may miss initial data). This check will not attempt to trigger snapshots.", connector, smVal))
+					cr.Result.Warnings = append(cr.Result.Warnings, cdc.Warning{Kind: cdc.WarningSnapshot, Message: fmt.Sprintf("Connector %s has snapshot.mode=%s; snapshots disabled or schema-only (CDC may miss initial data). This check will not attempt to trigger snapshots.", connector, smVal)})
 				}
 			}
 		}
@@ -174,14 +291,14 @@ func (i *Inspector) InspectConnectors(ctx context.Context) ([]*cdc.ConnectorResu
 							}
 						}
 						healthMsg := fmt.Sprintf("Connector %s health: connector=%s tasks=[%s]", connector, status.Connector.State, strings.Join(taskSummaries, ","))
-						cr.Result.Warnings = append(cr.Result.Warnings, healthMsg)
+						cr.Result.Warnings = append(cr.Result.Warnings, cdc.Warning{Kind: cdc.WarningConnector, Message: healthMsg})
 						if strings.ToUpper(status.Connector.State) != "RUNNING" {
-							cr.Result.Warnings = append(cr.Result.Warnings, fmt.Sprintf("Connector %s state=%s", connector, status.Connector.State))
+							cr.Result.Warnings = append(cr.Result.Warnings, cdc.Warning{Kind: cdc.WarningConnector, Message: fmt.Sprintf("Connector %s state=%s", connector, status.Connector.State)})
 						}
 						if len(failedTasks) > 0 {
-							cr.Result.Warnings = append(cr.Result.Warnings, fmt.Sprintf("Connector %s has failed task(s): %v", connector, failedTasks))
+							cr.Result.Warnings = append(cr.Result.Warnings, cdc.Warning{Kind: cdc.WarningConnector, Message: fmt.Sprintf("Connector %s has failed task(s): %v", connector, failedTasks)})
 							if strings.ToUpper(status.Connector.State) == "RUNNING" {
-								cr.Result.Warnings = append(cr.Result.Warnings, fmt.Sprintf("Connector %s may be in restart loop: connector RUNNING but tasks failing", connector))
+								cr.Result.Warnings = append(cr.Result.Warnings, cdc.Warning{Kind: cdc.WarningConnector, Message: fmt.Sprintf("Connector %s may be in restart loop: connector RUNNING but tasks failing", connector)})
 							}
 						}
 					}
@@ -189,125 +306,217 @@ func (i *Inspector) InspectConnectors(ctx context.Context) ([]*cdc.ConnectorResu
 			}
 		}
 
-		// Kafka history parsing
-		if topic, ok := connConfig.Config["database.history.kafka.topic"]; ok {
-			if topicStr, ok := topic.(string); ok {
-				if brokers, ok := connConfig.Config["database.history.kafka.bootstrap.servers"]; ok {
-					if brokersStr, ok := brokers.(string); ok {
-						schemas, times, err := fetchSchemasFromKafka(ctx, brokersStr, topicStr)
-						if err == nil {
-							if cr.Result.TableSchemas == nil {
-								cr.Result.TableSchemas = map[string]cdc.TableSchema{}
-							}
-							if cr.Result.SchemaTimestamps == nil {
-								cr.Result.SchemaTimestamps = map[string]time.Time{}
-							}
-							for t, cols := range schemas {
-								cr.Result.CapturedTables = append(cr.Result.CapturedTables, t)
-								cr.Result.TableSchemas[t] = cdc.TableSchema{Columns: cols}
-								if ts, ok := times[t]; ok {
-									cr.Result.SchemaTimestamps[t] = ts
-								}
-							}
-						}
+		// Committed binlog position, compared with the binlogs still on the source
+		if offset, err := offsets.read(ctx, connector); err != nil {
+			cr.Result.Warnings = append(cr.Result.Warnings, cdc.Warning{Kind: cdc.WarningConnector, Message: fmt.Sprintf("Connector %s offsets could not be read: %v", connector, err)})
+		} else if offset != nil {
+			cr.Result.Offset = binlogOffset(offset)
+		}
+
+		// Kafka history parsing; Debezium 2.x reads schema.history.internal.*
+		// and 1.x database.history.*
+		topicStr, hasTopic := settings.get("schema.history.internal.kafka.topic")
+		brokersStr, hasBrokers := settings.get("schema.history.internal.kafka.bootstrap.servers")
+		if hasTopic && hasBrokers {
+			read, err := i.readSchemaHistory(ctx, brokersStr, topicStr)
+			if err != nil {
+				cr.Result.Warnings = append(cr.Result.Warnings, cdc.Warning{Kind: cdc.WarningHistory, Message: fmt.Sprintf("Connector %s schema history topic %s could not be read: %v", connector, topicStr, err)})
+			} else {
+				cr.Result.Warnings = append(cr.Result.Warnings, warningsOf(cdc.WarningHistory, historyWarnings(connector, topicStr, read))...)
+				schemas, times := read.Model.filteredSchemas(func(db, name string) bool {
+					if i.sourceTables != nil && !strings.EqualFold(db, i.sourceDB) {
+						return false
+					}
+					return filter.captures(db, name)
+				})
+				if len(schemas) > 0 && cr.Result.TableSchemas == nil {
+					cr.Result.TableSchemas = map[string]cdc.TableSchema{}
+				}
+				if len(schemas) > 0 && cr.Result.SchemaTimestamps == nil {
+					cr.Result.SchemaTimestamps = map[string]time.Time{}
+				}
+				// Results are keyed by bare table name; without the source
+				// database, a name captured in several databases is ambiguous
+				names := make([]string, 0, len(schemas))
+				for name := range schemas {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				owner := map[string]string{}
+				for _, name := range names {
+					db, t, _ := strings.Cut(name, ".")
+					if prev, ok := owner[strings.ToLower(t)]; ok {
+						cr.Result.Warnings = append(cr.Result.Warnings, cdc.Warning{Kind: cdc.WarningHistory, Message: fmt.Sprintf("Connector %s schema history has table %s in databases %s and %s; only %s.%s is compared", connector, t, prev, db, prev, t)})
+						continue
+					}
+					owner[strings.ToLower(t)] = db
+					if !containsFold(cr.Result.CapturedTables, t) {
+						cr.Result.CapturedTables = append(cr.Result.CapturedTables, t)
+					}
+					if _, ok := qualified[t]; !ok {
+						qualified[t] = name
+					}
+					cr.Result.TableSchemas[t] = schemas[name]
+					if ts, ok := times[name]; ok {
+						cr.Result.SchemaTimestamps[t] = ts
 					}
 				}
 			}
 		}
 
-		results = append(results, cr)
-	}
+		// Columns the connector deliberately drops, masks, hashes or truncates
+		colFilter, colWarnings := newColumnFilter(connector, settings)
+		cr.Result.Warnings = append(cr.Result.Warnings, warningsOf(cdc.WarningConfig, colWarnings)...)
+		cr.Result.ColumnPolicies = i.columnPolicies(cr.Result, colFilter, qualified)
 
-	return results, nil
-}
+		// Settings that decide the Connect types of decimals, temporals and
+		// unsigned bigints, so types are compared with what is actually emitted
+		if emit := emitSettings(settings); emit != nil {
+			cr.Result.EmitSettings = map[string]map[string]string{}
+			for _, t := range cr.Result.CapturedTables {
+				cr.Result.EmitSettings[t] = emit
+			}
+		}
 
-// fetchSchemasFromKafka attempts to read recent messages from the given Kafka topic and
-// parse CREATE TABLE DDL statements to extract column names, types and nullability.
-// This is a best-effort approach and will skip messages that can't be parsed.
-func fetchSchemasFromKafka(ctx context.Context, brokersCSV, topic string) (map[string]map[string]cdc.ColumnInfo, map[string]time.Time, error) {
-	brokers := []string{}
-	for _, b := range strings.Split(brokersCSV, ",") {
-		b = strings.TrimSpace(b)
-		if b != "" {
-			brokers = append(brokers, b)
+		// Resolve the message key per table: message.key.columns overrides take
+		// precedence over the primary key recorded in the schema history.
+		var keyOverrides []keyOverride
+		if mkc, ok := connConfig.Config["message.key.columns"].(string); ok {
+			keyOverrides, err = parseMessageKeyColumns(mkc)
+			if err != nil {
+				cr.Result.Warnings = append(cr.Result.Warnings, cdc.Warning{Kind: cdc.WarningConfig, Message: fmt.Sprintf("Connector %s has invalid message.key.columns: %v", connector, err)})
+			}
 		}
-	}
-	if len(brokers) == 0 {
-		return nil, nil, fmt.Errorf("no kafka brokers provided")
-	}
+		cr.Result.KeyColumns = resolveKeyColumns(cr.Result, keyOverrides, qualified)
 
-	r := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:  brokers,
-		Topic:    topic,
-		MinBytes: 1,
-		MaxBytes: 10e6, // 10MB
-	})
-	defer r.Close()
+		// Change event topics of the captured tables, counted when a Kafka
+		// cluster is known: cdc.brokers, or the schema history brokers
+		router, routeWarnings := newTopicRouter(connector, settings)
+		cr.Result.Warnings = append(cr.Result.Warnings, warningsOf(cdc.WarningConfig, routeWarnings)...)
+		topics, topicWarnings := router.dataTopics(connector, qualified)
+		cr.Result.Warnings = append(cr.Result.Warnings, warningsOf(cdc.WarningConfig, topicWarnings)...)
+		if len(topics) > 0 {
+			cr.Result.DataTopics = topics
+		}
+		dataBrokers := strings.Join(i.cfg.Brokers, ",")
+		if dataBrokers == "" && hasBrokers {
+			dataBrokers = brokersStr
+		}
+		if dataBrokers != "" {
+			if i.topicBrokers == nil {
+				i.topicBrokers = map[string]string{}
+			}
+			for _, topic := range topics {
+				i.topicBrokers[topic] = dataBrokers
+			}
+			i.readDataTopics(ctx, connector, dataBrokers, cr.Result)
+		}
 
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
+		// Heartbeats tell an idle table from a stalled connector
+		cr.Result.Heartbeat = heartbeatSettings(settings)
+		if hb := cr.Result.Heartbeat; hb.Topic != "" && dataBrokers != "" {
+			if err := i.readHeartbeat(ctx, dataBrokers, hb); err != nil {
+				cr.Result.Warnings = append(cr.Result.Warnings, cdc.Warning{Kind: cdc.WarningConnector, Message: fmt.Sprintf("Connector %s heartbeat topic %s could not be read: %v", connector, hb.Topic, err)})
+			}
+		}
 
-	schemas := map[string]map[string]cdc.ColumnInfo{}
-	// simple regexp to capture CREATE TABLE `table_name` (...)
-	reCreate := regexp.MustCompile(`(?is)CREATE\s+TABLE\s+` + "`?" + `([^\s` + "`" + `\.]+)` + "`?" + `\s*\((.*?)\)`) // captures table and contents
-	// regexp for column lines: `name` TYPE ... (NULL|NOT NULL)?
-	reCol := regexp.MustCompile("`([^`]+)`\\s+([A-Za-z0-9()_,]+).*?(NOT NULL|NULL)?")
+		results = append(results, cr)
+	}
 
-	// schemaTimes will hold the latest Kafka message timestamp observed per table
-	schemaTimes := map[string]time.Time{}
+	return results, nil
+}
 
-	count := 0
-	for count < 500 {
-		m, err := r.ReadMessage(ctx)
-		if err != nil {
-			break
+// columnPolicies evaluates the column filter for each captured table, over
+// the columns known from the source and from the schema history.
+func (i *Inspector) columnPolicies(res *cdc.Result, f *columnFilter, qualified map[string]string) map[string]map[string]cdc.ColumnPolicy {
+	out := map[string]map[string]cdc.ColumnPolicy{}
+	for _, t := range res.CapturedTables {
+		db := i.sourceDB
+		if db == "" {
+			db, _, _ = strings.Cut(qualified[t], ".")
 		}
-		count++
-		var j any
-		if err := json.Unmarshal(m.Value, &j); err != nil {
-			// skip non-json messages
+		if db == "" {
 			continue
 		}
-		// stringify to search for DDL
-		s := string(m.Value)
-		if strings.Contains(strings.ToUpper(s), "CREATE TABLE") {
-			matches := reCreate.FindAllStringSubmatch(s, -1)
-			for _, mm := range matches {
-				if len(mm) < 3 {
-					continue
-				}
-				table := strings.Trim(mm[1], " `")
-				colsBlock := mm[2]
-				cols := map[string]cdc.ColumnInfo{}
-				for _, line := range strings.Split(colsBlock, ",") {
-					line = strings.TrimSpace(line)
-					if line == "" {
-						continue
-					}
-					cm := reCol.FindStringSubmatch(line)
-					if len(cm) >= 3 {
-						colName := cm[1]
-						typeStr := strings.TrimSpace(cm[2])
-						nullStr := "NOT NULL"
-						if len(cm) >= 4 && strings.Contains(strings.ToUpper(cm[3]), "NULL") {
-							nullStr = strings.ToUpper(strings.TrimSpace(cm[3]))
-						}
-						cols[colName] = cdc.ColumnInfo{Type: strings.ToUpper(typeStr), Nullable: (nullStr == "NULL")}
-					}
-				}
-				if len(cols) > 0 {
-					schemas[table] = cols
-					// record the message timestamp as the last-seen DDL timestamp for this table
-					if _, ok := schemaTimes[table]; !ok || m.Time.After(schemaTimes[table]) {
-						schemaTimes[table] = m.Time
-					}
+		var columns []string
+		for _, st := range i.sourceTables {
+			if strings.EqualFold(st.Name, t) {
+				for _, c := range st.Columns {
+					columns = append(columns, c.Name)
 				}
 			}
 		}
+		for c := range res.TableSchemas[t].Columns {
+			if !containsFold(columns, c) {
+				columns = append(columns, c)
+			}
+		}
+		if p := f.policies(db, t, columns); p != nil {
+			out[t] = p
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// fetchPluginVersions returns the installed connector plugin versions keyed
+// by class. Errors yield an empty map, leaving versions to be inferred from
+// the connector configs.
+func fetchPluginVersions(ctx context.Context, client *http.Client, connectURL string) map[string]string {
+	versions := map[string]string{}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/connector-plugins", connectURL), nil)
+	if err != nil {
+		return versions
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return versions
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return versions
 	}
+	var plugins []struct {
+		Class   string `json:"class"`
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&plugins); err != nil {
+		return versions
+	}
+	for _, p := range plugins {
+		versions[p.Class] = p.Version
+	}
+	return versions
+}
 
-	if len(schemas) == 0 {
-		return nil, nil, fmt.Errorf("no schemas found in kafka topic %s", topic)
+func connectorClass(config map[string]interface{}) string {
+	class, _ := config["connector.class"].(string)
+	return class
+}
+
+// readSchemaHistory replays a connector's schema history topic into an
+// in-memory schema model. Records are parsed from their structured
+// tableChanges when present and from the DDL text otherwise; messages that
+// cannot be parsed are skipped.
+func (i *Inspector) readSchemaHistory(ctx context.Context, brokersCSV, topic string) (*historyRead, error) {
+	ht, err := newKafkaHistoryTopic(brokersCSV, topic)
+	if err != nil {
+		return nil, err
+	}
+	return readHistory(ctx, ht, topic, historyOptions{
+		Timeout:     i.cfg.HistoryTimeout(),
+		IdleTimeout: i.cfg.HistoryIdleTimeout(),
+		Progress:    i.progress,
+	})
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
 	}
-	return schemas, schemaTimes, nil
+	return false
 }
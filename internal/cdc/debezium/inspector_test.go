@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
 	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
 )
 
 func TestConnectorHealthDetection(t *testing.T) {
@@ -37,18 +40,18 @@ func TestConnectorHealthDetection(t *testing.T) {
 		t.Fatalf("inspect error: %v", err)
 	}
 	// Expect exact warnings about snapshot.mode, health, failed task, and restart loop
-	expected := []string{
-		"Connector foo has snapshot.mode=never; snapshots disabled or schema-only (CDC may miss initial data). This check will not attempt to trigger snapshots.",
-		"Connector foo health: connector=RUNNING tasks=[0:FAILED]",
-		"Connector foo has failed task(s): [0]",
-		"Connector foo may be in restart loop: connector RUNNING but tasks failing",
+	expected := []cdc.Warning{
+		{Kind: cdc.WarningSnapshot, Message: "Connector foo has snapshot.mode=never; snapshots disabled or schema-only (CDC may miss initial data). This check will not attempt to trigger snapshots."},
+		{Kind: cdc.WarningConnector, Message: "Connector foo health: connector=RUNNING tasks=[0:FAILED]"},
+		{Kind: cdc.WarningConnector, Message: "Connector foo has failed task(s): [0]"},
+		{Kind: cdc.WarningConnector, Message: "Connector foo may be in restart loop: connector RUNNING but tasks failing"},
 	}
 	if len(res.Warnings) != len(expected) {
 		t.Fatalf("expected %d warnings, got %d: %v", len(expected), len(res.Warnings), res.Warnings)
 	}
 	for i, w := range expected {
 		if res.Warnings[i] != w {
-			t.Errorf("warning %d: expected %q, got %q", i, w, res.Warnings[i])
+			t.Errorf("warning %d: expected %v, got %v", i, w, res.Warnings[i])
 		}
 	}
 	// Fail if any mismatch was found
@@ -58,3 +61,90 @@ func TestConnectorHealthDetection(t *testing.T) {
 		}
 	}
 }
+
+func TestInspectDebezium2Keys(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/connector-plugins":
+			json.NewEncoder(w).Encode([]map[string]string{{"class": "io.debezium.connector.mysql.MySqlConnector", "type": "source", "version": "2.5.0.Final"}})
+		case "/connectors/":
+			json.NewEncoder(w).Encode([]string{"inventory"})
+		case "/connectors/inventory":
+			json.NewEncoder(w).Encode(map[string]map[string]string{"config": {
+				"connector.class":      "io.debezium.connector.mysql.MySqlConnector",
+				"topic.prefix":         "dbserver1",
+				"table.include.list":   "shop.orders",
+				"database.server.name": "dbserver1",
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	crs, err := New(config.CDCConfig{ConnectURL: ts.URL}).InspectConnectors(context.Background())
+	if err != nil {
+		t.Fatalf("inspect error: %v", err)
+	}
+	res := crs[0].Result
+	if len(res.CapturedTables) != 1 || res.CapturedTables[0] != "orders" {
+		t.Fatalf("expected orders to be captured, got %v", res.CapturedTables)
+	}
+	want := []cdc.Warning{
+		{Kind: cdc.WarningConfig, Message: "Connector inventory mixes Debezium 1.x and 2.x configuration keys (database.server.name with topic.prefix); each version reads only one of them"},
+		{Kind: cdc.WarningConfig, Message: "Connector inventory sets configuration key database.server.name, which Debezium 2.x ignores; use topic.prefix"},
+	}
+	if len(res.Warnings) != len(want) {
+		t.Fatalf("expected warnings %v, got %v", want, res.Warnings)
+	}
+	for i := range want {
+		if res.Warnings[i] != want[i] {
+			t.Fatalf("expected warnings %v, got %v", want, res.Warnings)
+		}
+	}
+}
+
+func TestInspectEvaluatesFiltersAgainstSource(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/connectors/":
+			json.NewEncoder(w).Encode([]string{"shop"})
+		case "/connectors/shop":
+			json.NewEncoder(w).Encode(map[string]map[string]string{"config": {
+				"database.include.list": "shop",
+				"table.include.list":    `shop\.orders.*,shop.refunds`,
+				"message.key.columns":   "shop.orders:order_no",
+				"column.exclude.list":   `shop\.orders\.card_number`,
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	i := New(config.CDCConfig{ConnectURL: ts.URL})
+	i.SetSource("shop", []source.TableInfo{
+		{Name: "orders", Columns: []source.ColumnInfo{{Name: "order_no"}, {Name: "card_number"}}},
+		{Name: "orders_2025"},
+		{Name: "users"},
+	}, []string{"orders_archive", "sessions"})
+	crs, err := i.InspectConnectors(context.Background())
+	if err != nil {
+		t.Fatalf("inspect error: %v", err)
+	}
+	res := crs[0].Result
+	want := []string{"orders", "orders_2025", "refunds"}
+	if !reflect.DeepEqual(res.CapturedTables, want) {
+		t.Fatalf("expected captured tables %v, got %v", want, res.CapturedTables)
+	}
+	if !reflect.DeepEqual(res.CapturedOther, []string{"orders_archive"}) {
+		t.Fatalf("expected orders_archive to be captured outside the inspected tables, got %v", res.CapturedOther)
+	}
+	if got := res.KeyColumns["orders"]; !reflect.DeepEqual(got, []string{"order_no"}) {
+		t.Fatalf("expected message key override for orders, got %v", res.KeyColumns)
+	}
+	wantPolicies := map[string]map[string]cdc.ColumnPolicy{"orders": {"card_number": cdc.ColumnExcluded}}
+	if !reflect.DeepEqual(res.ColumnPolicies, wantPolicies) {
+		t.Fatalf("expected column policies %v, got %v", wantPolicies, res.ColumnPolicies)
+	}
+}
@@ -0,0 +1,77 @@
+package debezium
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+// keyOverride is a single message.key.columns entry: a fully-qualified table
+// regular expression and the columns used as the message key for matching tables.
+type keyOverride struct {
+	table   *regexp.Regexp
+	columns []string
+}
+
+// parseMessageKeyColumns parses Debezium's message.key.columns setting, e.g.
+// "inventory.customers:pk1,pk2;(.*).purchaseorders:pk3".
+func parseMessageKeyColumns(v string) ([]keyOverride, error) {
+	var overrides []keyOverride
+	for _, entry := range strings.Split(v, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		idx := strings.LastIndex(entry, ":")
+		if idx <= 0 || idx == len(entry)-1 {
+			return overrides, fmt.Errorf("entry %q must be <table>:<column>[,<column>...]", entry)
+		}
+		re, err := regexp.Compile("^(?:" + strings.TrimSpace(entry[:idx]) + ")$")
+		if err != nil {
+			return overrides, fmt.Errorf("entry %q: %w", entry, err)
+		}
+		var cols []string
+		for _, c := range strings.Split(entry[idx+1:], ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				cols = append(cols, c)
+			}
+		}
+		overrides = append(overrides, keyOverride{table: re, columns: cols})
+	}
+	return overrides, nil
+}
+
+// resolveKeyColumns returns the message key columns for each captured table.
+// An override is matched against the table's fully-qualified name when known
+// (from qualified), otherwise against the bare table name. Tables without an
+// override fall back to the primary key recorded in the schema history, and
+// are omitted when neither is available.
+func resolveKeyColumns(res *cdc.Result, overrides []keyOverride, qualified map[string]string) map[string][]string {
+	keys := map[string][]string{}
+	for _, t := range res.CapturedTables {
+		name := t
+		if q, ok := qualified[t]; ok {
+			name = q
+		}
+		matched := false
+		for _, o := range overrides {
+			if o.table.MatchString(name) {
+				keys[t] = o.columns
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if schema, ok := res.TableSchemas[t]; ok && len(schema.PrimaryKey) > 0 {
+			keys[t] = schema.PrimaryKey
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return keys
+}
@@ -0,0 +1,38 @@
+package debezium
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+func TestResolveKeyColumns(t *testing.T) {
+	overrides, err := parseMessageKeyColumns("inventory.customers:email;(.*).orders:tenant_id,id")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	res := &cdc.Result{
+		CapturedTables: []string{"customers", "orders", "products", "notes"},
+		TableSchemas: map[string]cdc.TableSchema{
+			"customers": {PrimaryKey: []string{"id"}},
+			"products":  {PrimaryKey: []string{"sku"}},
+		},
+	}
+	qualified := map[string]string{"customers": "inventory.customers", "orders": "inventory.orders"}
+	got := resolveKeyColumns(res, overrides, qualified)
+	want := map[string][]string{
+		"customers": {"email"},
+		"orders":    {"tenant_id", "id"},
+		"products":  {"sku"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParseMessageKeyColumnsInvalid(t *testing.T) {
+	if _, err := parseMessageKeyColumns("inventory.customers"); err == nil {
+		t.Fatalf("expected error for entry without columns")
+	}
+}
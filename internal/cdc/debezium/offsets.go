@@ -0,0 +1,197 @@
+package debezium
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+// errOffsetsUnsupported is returned by fetchOffsets when the Connect worker
+// predates the offsets endpoint (Kafka 3.5).
+var errOffsetsUnsupported = errors.New("connect REST API does not report offsets")
+
+// fetchOffsets reads a connector's committed source offsets from
+// GET /connectors/{name}/offsets. A connector without committed offsets
+// yields nil.
+func fetchOffsets(ctx context.Context, client *http.Client, connectURL, connector string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/connectors/%s/offsets", connectURL, connector), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return nil, errOffsetsUnsupported
+	default:
+		return nil, fmt.Errorf("offsets request returned status: %d", resp.StatusCode)
+	}
+	var body struct {
+		Offsets []struct {
+			Partition map[string]interface{} `json:"partition"`
+			Offset    map[string]interface{} `json:"offset"`
+		} `json:"offsets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	// A MySQL connector has a single source partition, its server name
+	for _, o := range body.Offsets {
+		if o.Offset != nil {
+			return o.Offset, nil
+		}
+	}
+	return nil, nil
+}
+
+// offsetsRead is a replay of a Connect offsets topic.
+type offsetsRead struct {
+	// Offsets is the latest offset committed by each connector
+	Offsets map[string]map[string]interface{}
+	// Unreached joins the errors of the partitions that could not be read;
+	// offsets committed to them are missing from Offsets
+	Unreached error
+}
+
+// readOffsetsTopic replays a Connect offsets topic up to its high-water marks
+// and returns the latest offset committed by each connector. Keys are JSON
+// arrays of the connector name and its source partition; a null value
+// deletes the connector's offset. Connect keys records by connector, so each
+// connector's offsets are all in one partition and those found in the
+// reachable partitions are complete. Unreachable partitions are skipped and
+// recorded in Unreached, so that a connector without an offset can be
+// reported as not read rather than as having none.
+func readOffsetsTopic(ctx context.Context, topic historyTopic, opts historyOptions) (*offsetsRead, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	parts, err := topic.Partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	read := &offsetsRead{Offsets: map[string]map[string]interface{}{}, Unreached: unreachable(parts)}
+	for _, p := range parts {
+		if p.Err != nil || p.Last <= p.First {
+			continue
+		}
+		if err := readOffsetsPartition(ctx, topic, p, opts, read.Offsets); err != nil {
+			return nil, err
+		}
+	}
+	return read, nil
+}
+
+func readOffsetsPartition(ctx context.Context, topic historyTopic, p partitionRange, opts historyOptions, offsets map[string]map[string]interface{}) error {
+	r := topic.Open(p.ID, p.First)
+	defer r.Close()
+
+	for next := p.First; next < p.Last; {
+		msgCtx, cancel := context.WithTimeout(ctx, opts.IdleTimeout)
+		m, err := r.ReadMessage(msgCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				err = fmt.Errorf("timed out after %s", opts.Timeout)
+			}
+			return fmt.Errorf("partition %d stopped at offset %d of %d: %w", p.ID, next, p.Last, err)
+		}
+		next = m.Offset + 1
+		var key []json.RawMessage
+		if err := json.Unmarshal(m.Key, &key); err != nil || len(key) == 0 {
+			continue
+		}
+		var connector string
+		if err := json.Unmarshal(key[0], &connector); err != nil {
+			continue
+		}
+		if m.Value == nil {
+			delete(offsets, connector)
+			continue
+		}
+		var offset map[string]interface{}
+		if err := json.Unmarshal(m.Value, &offset); err != nil {
+			continue
+		}
+		offsets[connector] = offset
+	}
+	return nil
+}
+
+// binlogOffset extracts the binlog position from a Debezium MySQL offset.
+// Offsets of other connectors, which have no binlog file, yield nil.
+func binlogOffset(offset map[string]interface{}) *cdc.SourceOffset {
+	file, _ := offset["file"].(string)
+	if file == "" {
+		return nil
+	}
+	out := &cdc.SourceOffset{BinlogFile: file}
+	if pos, ok := offset["pos"].(float64); ok {
+		out.BinlogPosition = int64(pos)
+	}
+	if gtids, ok := offset["gtids"].(string); ok {
+		out.GTIDSet = strings.TrimSpace(gtids)
+	}
+	if ts, ok := offset["ts_sec"].(float64); ok && ts > 0 {
+		out.Timestamp = time.Unix(int64(ts), 0).UTC()
+	}
+	return out
+}
+
+// offsetReader looks up committed connector offsets, from the REST API when
+// the Connect worker supports it and from the offsets topic otherwise. The
+// topic is read at most once per inspection.
+type offsetReader struct {
+	i         *Inspector
+	client    *http.Client
+	topicRead bool
+	topic     *offsetsRead
+	topicErr  error
+}
+
+// read returns the connector's committed offset, or nil when it has none or
+// its offsets cannot be looked up without brokers. A connector without an
+// offset in the reachable partitions of the offsets topic gets an error
+// naming the unreachable ones, which is recorded as a warning.
+func (r *offsetReader) read(ctx context.Context, connector string) (map[string]interface{}, error) {
+	offset, err := fetchOffsets(ctx, r.client, r.i.cfg.ConnectURL, connector)
+	if !errors.Is(err, errOffsetsUnsupported) {
+		return offset, err
+	}
+	if len(r.i.cfg.Brokers) == 0 {
+		return nil, nil
+	}
+	if !r.topicRead {
+		r.topicRead = true
+		topic := r.i.cfg.OffsetsTopicName()
+		var kt *kafkaHistoryTopic
+		if kt, r.topicErr = newKafkaHistoryTopic(strings.Join(r.i.cfg.Brokers, ","), topic); r.topicErr == nil {
+			r.topic, r.topicErr = readOffsetsTopic(ctx, kt, historyOptions{
+				Timeout:     r.i.cfg.HistoryTimeout(),
+				IdleTimeout: r.i.cfg.HistoryIdleTimeout(),
+			})
+		}
+		if r.topicErr != nil {
+			r.topicErr = fmt.Errorf("offsets topic %s: %w", topic, r.topicErr)
+		}
+	}
+	if r.topicErr != nil {
+		return nil, r.topicErr
+	}
+	if offset, ok := r.topic.Offsets[connector]; ok {
+		return offset, nil
+	}
+	if r.topic.Unreached != nil {
+		return nil, fmt.Errorf("offsets topic %s: %w", r.i.cfg.OffsetsTopicName(), r.topic.Unreached)
+	}
+	return nil, nil
+}
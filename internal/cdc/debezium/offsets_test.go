@@ -0,0 +1,96 @@
+package debezium
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+)
+
+func TestReadOffsetsTopic(t *testing.T) {
+	topic := &fakeHistoryTopic{
+		last: 4,
+		keys: [][]byte{
+			[]byte(`["inventory",{"server":"dbserver1"}]`),
+			[]byte(`["billing",{"server":"dbserver2"}]`),
+			[]byte(`["inventory",{"server":"dbserver1"}]`),
+			[]byte(`["billing",{"server":"dbserver2"}]`),
+		},
+		messages: [][]byte{
+			[]byte(`{"file":"mysql-bin.000003","pos":154}`),
+			[]byte(`{"file":"mysql-bin.000001","pos":4}`),
+			[]byte(`{"file":"mysql-bin.000004","pos":890,"gtids":"a:1-20","ts_sec":1760000000}`),
+			nil, // tombstone: billing's offsets were reset
+		},
+	}
+	read, err := readOffsetsTopic(context.Background(), topic, historyOptions{Timeout: 5 * time.Second, IdleTimeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	offsets := read.Offsets
+	if _, ok := offsets["billing"]; ok || len(offsets) != 1 {
+		t.Fatalf("expected only inventory's offset, got %v", offsets)
+	}
+	got := binlogOffset(offsets["inventory"])
+	want := &cdc.SourceOffset{BinlogFile: "mysql-bin.000004", BinlogPosition: 890, GTIDSet: "a:1-20", Timestamp: time.Unix(1760000000, 0).UTC()}
+	if *got != *want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if off := binlogOffset(map[string]interface{}{"lsn": 123.0}); off != nil {
+		t.Fatalf("expected no binlog offset for a Postgres offset, got %+v", off)
+	}
+}
+
+func TestReadOffsetsTopicSkipsUnreachablePartitions(t *testing.T) {
+	topic := &fakeHistoryTopic{
+		last:     1,
+		keys:     [][]byte{[]byte(`["inventory",{"server":"dbserver1"}]`)},
+		messages: [][]byte{[]byte(`{"file":"mysql-bin.000003","pos":154}`)},
+		down:     errors.New("partition 1 has no leader"),
+	}
+	read, err := readOffsetsTopic(context.Background(), topic, historyOptions{Timeout: 5 * time.Second, IdleTimeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := read.Offsets["inventory"]; !ok {
+		t.Fatalf("expected inventory's offset from the reachable partition, got %v", read.Offsets)
+	}
+	if read.Unreached == nil || !strings.Contains(read.Unreached.Error(), "no leader") {
+		t.Fatalf("expected the unreachable partition to be recorded, got %v", read.Unreached)
+	}
+}
+
+func TestInspectReadsOffsetsFromRESTAPI(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/connectors/":
+			json.NewEncoder(w).Encode([]string{"inventory"})
+		case "/connectors/inventory":
+			json.NewEncoder(w).Encode(map[string]map[string]string{"config": {"table.include.list": "shop.orders"}})
+		case "/connectors/inventory/offsets":
+			json.NewEncoder(w).Encode(map[string]any{"offsets": []map[string]any{{
+				"partition": map[string]string{"server": "dbserver1"},
+				"offset":    map[string]any{"file": "mysql-bin.000007", "pos": 1042, "gtids": "a:1-99"},
+			}}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	crs, err := New(config.CDCConfig{ConnectURL: ts.URL}).InspectConnectors(context.Background())
+	if err != nil {
+		t.Fatalf("inspect error: %v", err)
+	}
+	want := cdc.SourceOffset{BinlogFile: "mysql-bin.000007", BinlogPosition: 1042, GTIDSet: "a:1-99"}
+	if got := crs[0].Result.Offset; got == nil || *got != want {
+		t.Fatalf("expected offset %+v, got %+v", want, got)
+	}
+}
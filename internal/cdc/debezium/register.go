@@ -0,0 +1,12 @@
+package debezium
+
+import (
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+)
+
+func init() {
+	cdc.Register("debezium", func(cfg *config.Config) (cdc.Inspector, error) {
+		return New(cfg.CDC), nil
+	})
+}
@@ -0,0 +1,388 @@
+package debezium
+
+import (
+	"strings"
+	"time"
+)
+
+// applyDDL applies the statements in a DDL string to the model, in order.
+// Statements that do not change a table's columns (indexes, engine options,
+// TRUNCATE, ...) are ignored.
+func (m *schemaModel) applyDDL(ddl, defaultDB string, ts time.Time) {
+	for _, stmt := range splitStatements(ddl) {
+		if rest, ok := consumeWords(stmt, "USE"); ok {
+			if db, _, _ := readIdent(rest); db != "" {
+				defaultDB = db
+			}
+			continue
+		}
+		if rest, ok := consumeWords(stmt, "CREATE"); ok {
+			if _, ok := consumeWords(consumeOptional(rest, "TEMPORARY"), "TABLE"); ok {
+				m.applyCreate(stmt, defaultDB, ts)
+			}
+			continue
+		}
+		if rest, ok := consumeWords(stmt, "ALTER"); ok {
+			rest = consumeOptional(consumeOptional(rest, "ONLINE"), "IGNORE")
+			if rest, ok := consumeWords(rest, "TABLE"); ok {
+				m.applyAlter(rest, defaultDB, ts)
+			}
+			continue
+		}
+		if rest, ok := consumeWords(stmt, "DROP"); ok {
+			if rest, ok := consumeWords(consumeOptional(rest, "TEMPORARY"), "TABLE"); ok {
+				rest = consumeOptional(rest, "IF", "EXISTS")
+				for _, name := range splitTopLevel(rest, ',') {
+					db, table, _ := readQualifiedName(name)
+					if db == "" {
+						db = defaultDB
+					}
+					m.drop(db, table)
+				}
+			} else if rest, ok := consumeWords(rest, "DATABASE"); ok {
+				m.dropDatabase(consumeOptional(rest, "IF", "EXISTS"))
+			} else if rest, ok := consumeWords(rest, "SCHEMA"); ok {
+				m.dropDatabase(consumeOptional(rest, "IF", "EXISTS"))
+			}
+			continue
+		}
+		if _, ok := consumeWords(stmt, "RENAME", "TABLE"); ok {
+			for _, pair := range parseRenamePairs(stmt, defaultDB) {
+				m.rename(pair[0], pair[1], pair[2], pair[3], ts)
+			}
+		}
+	}
+}
+
+func (m *schemaModel) applyCreate(stmt, defaultDB string, ts time.Time) {
+	t, likeDB, likeTable, ok := parseCreateTable(stmt, defaultDB)
+	if !ok {
+		return
+	}
+	if likeTable != "" {
+		src := m.get(likeDB, likeTable)
+		if src == nil {
+			return
+		}
+		t.columns = cloneColumns(src.columns)
+		t.pk = append([]string(nil), src.pk...)
+	}
+	m.put(t, ts)
+}
+
+func (m *schemaModel) dropDatabase(s string) {
+	db, _, _ := readIdent(s)
+	for key, t := range m.tables {
+		if strings.EqualFold(t.db, db) {
+			delete(m.tables, key)
+			delete(m.times, key)
+		}
+	}
+}
+
+// rename moves a table to a new name, keeping its columns.
+func (m *schemaModel) rename(fromDB, from, toDB, to string, ts time.Time) {
+	t := m.get(fromDB, from)
+	if t == nil {
+		return
+	}
+	m.drop(fromDB, from)
+	t.db, t.name = toDB, to
+	m.put(t, ts)
+}
+
+// applyAlter applies an ALTER TABLE statement, starting after "ALTER TABLE".
+// Tables the model has never seen are ignored, since their starting columns
+// are unknown.
+func (m *schemaModel) applyAlter(s, defaultDB string, ts time.Time) {
+	db, name, rest := readQualifiedName(s)
+	if db == "" {
+		db = defaultDB
+	}
+	t := m.get(db, name)
+	if t == nil {
+		return
+	}
+	renameTo := [2]string{}
+	for _, spec := range splitTopLevel(rest, ',') {
+		spec = strings.TrimSpace(spec)
+		switch {
+		case hasWords(spec, "ADD"):
+			t.applyAdd(consumeOptional(spec, "ADD"))
+		case hasWords(spec, "DROP", "PRIMARY", "KEY"):
+			t.pk = nil
+		case hasWords(spec, "DROP"):
+			t.applyDrop(consumeOptional(spec, "DROP"))
+		case hasWords(spec, "MODIFY"):
+			def := consumeOptional(consumeOptional(spec, "MODIFY"), "COLUMN")
+			if col, inlinePK, ok := parseColumnDef(def); ok {
+				t.replaceColumn(col.name, col, def, inlinePK)
+			}
+		case hasWords(spec, "CHANGE"):
+			def := consumeOptional(consumeOptional(spec, "CHANGE"), "COLUMN")
+			old, def, _ := readIdent(def)
+			if col, inlinePK, ok := parseColumnDef(def); ok {
+				t.replaceColumn(old, col, def, inlinePK)
+			}
+		case hasWords(spec, "RENAME", "COLUMN"):
+			r := consumeOptional(spec, "RENAME", "COLUMN")
+			old, r, _ := readIdent(r)
+			if r, ok := consumeWords(r, "TO"); ok {
+				if to, _, _ := readIdent(r); to != "" {
+					t.renameColumn(old, to)
+				}
+			}
+		case hasWords(spec, "RENAME", "INDEX"), hasWords(spec, "RENAME", "KEY"):
+			// index renames do not affect columns
+		case hasWords(spec, "RENAME"):
+			r := consumeOptional(consumeOptional(consumeOptional(spec, "RENAME"), "TO"), "AS")
+			toDB, to, _ := readQualifiedName(r)
+			if toDB == "" {
+				toDB = t.db
+			}
+			renameTo = [2]string{toDB, to}
+		}
+	}
+	t.renumber()
+	m.put(t, ts)
+	if renameTo[1] != "" {
+		m.rename(t.db, t.name, renameTo[0], renameTo[1], ts)
+	}
+}
+
+// hasWords reports whether s starts with the given keywords.
+func hasWords(s string, words ...string) bool {
+	_, ok := consumeWords(s, words...)
+	return ok
+}
+
+// applyAdd handles the part of an ADD specification after "ADD".
+func (t *tableModel) applyAdd(spec string) {
+	if !strings.HasPrefix(spec, "`") && !strings.HasPrefix(spec, `"`) {
+		word, _, _ := readIdent(spec)
+		if tableConstraintWords[strings.ToUpper(word)] {
+			if pk := primaryKeyFromConstraint(spec); pk != nil {
+				t.pk = pk
+			}
+			return
+		}
+	}
+	spec = consumeOptional(spec, "COLUMN")
+	if strings.HasPrefix(spec, "(") {
+		// ADD COLUMN (a INT, b INT)
+		end := matchingParen(spec, 0)
+		if end < 0 {
+			return
+		}
+		for _, def := range splitTopLevel(spec[1:end], ',') {
+			if col, inlinePK, ok := parseColumnDef(def); ok {
+				t.columns = append(t.columns, col)
+				if inlinePK {
+					t.pk = []string{col.name}
+				}
+			}
+		}
+		return
+	}
+	col, inlinePK, ok := parseColumnDef(spec)
+	if !ok {
+		return
+	}
+	t.insertColumn(col, spec)
+	if inlinePK {
+		t.pk = []string{col.name}
+	}
+}
+
+// applyDrop handles the part of a DROP specification after "DROP".
+func (t *tableModel) applyDrop(spec string) {
+	for _, w := range []string{"INDEX", "KEY", "FOREIGN", "CONSTRAINT", "CHECK"} {
+		if hasWords(spec, w) {
+			return
+		}
+	}
+	name, _, _ := readIdent(consumeOptional(spec, "COLUMN"))
+	idx := t.columnIndex(name)
+	if idx < 0 {
+		return
+	}
+	t.columns = append(t.columns[:idx], t.columns[idx+1:]...)
+	var pk []string
+	for _, c := range t.pk {
+		if !strings.EqualFold(c, name) {
+			pk = append(pk, c)
+		}
+	}
+	t.pk = pk
+}
+
+func (t *tableModel) columnIndex(name string) int {
+	for i, c := range t.columns {
+		if strings.EqualFold(c.name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertColumn adds col honouring a trailing FIRST or AFTER clause in def.
+func (t *tableModel) insertColumn(col *columnModel, def string) {
+	pos := len(t.columns)
+	if first, after := columnPosition(def); first {
+		pos = 0
+	} else if after != "" {
+		if idx := t.columnIndex(after); idx >= 0 {
+			pos = idx + 1
+		}
+	}
+	t.columns = append(t.columns, nil)
+	copy(t.columns[pos+1:], t.columns[pos:])
+	t.columns[pos] = col
+}
+
+// replaceColumn swaps the definition of column old for col, moving it when
+// def has a FIRST or AFTER clause.
+func (t *tableModel) replaceColumn(old string, col *columnModel, def string, inlinePK bool) {
+	idx := t.columnIndex(old)
+	if idx < 0 {
+		return
+	}
+	if first, after := columnPosition(def); first || after != "" {
+		t.columns = append(t.columns[:idx], t.columns[idx+1:]...)
+		t.insertColumn(col, def)
+	} else {
+		t.columns[idx] = col
+	}
+	if !strings.EqualFold(old, col.name) {
+		for i, c := range t.pk {
+			if strings.EqualFold(c, old) {
+				t.pk[i] = col.name
+			}
+		}
+	}
+	if inlinePK {
+		t.pk = []string{col.name}
+	}
+}
+
+// columnPosition reads the FIRST or AFTER <column> clause ending a column
+// definition. The words must be bare and outside parentheses, so a DEFAULT,
+// COMMENT or generated column expression mentioning them is not a clause.
+func columnPosition(def string) (first bool, after string) {
+	var words []string
+	depth := 0
+	for i := 0; i < len(def); {
+		c := def[i]
+		end := i + 1
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end = skipQuoted(def, i)
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case isIdentChar(rune(c)):
+			for end < len(def) && isIdentChar(rune(def[end])) {
+				end++
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ';':
+			i = end
+			continue
+		}
+		if depth == 0 && c != ')' {
+			words = append(words, def[i:end])
+		}
+		i = end
+	}
+	n := len(words)
+	if n > 0 && strings.EqualFold(words[n-1], "FIRST") {
+		return true, ""
+	}
+	if n > 1 && strings.EqualFold(words[n-2], "AFTER") {
+		if last := words[n-1]; last[0] == '`' || isIdentChar(rune(last[0])) {
+			name, _, _ := readIdent(last)
+			return false, name
+		}
+	}
+	return false, ""
+}
+
+func (t *tableModel) renameColumn(old, to string) {
+	if idx := t.columnIndex(old); idx >= 0 {
+		cp := *t.columns[idx]
+		cp.name = to
+		t.columns[idx] = &cp
+	}
+	for i, c := range t.pk {
+		if strings.EqualFold(c, old) {
+			t.pk[i] = to
+		}
+	}
+}
+
+// parseRenamePairs parses "RENAME TABLE a TO b, c TO d" into
+// [fromDB, from, toDB, to] tuples.
+func parseRenamePairs(stmt, defaultDB string) [][4]string {
+	rest, ok := consumeWords(stmt, "RENAME", "TABLE")
+	if !ok {
+		return nil
+	}
+	var pairs [][4]string
+	for _, part := range splitTopLevel(rest, ',') {
+		fromDB, from, r := readQualifiedName(part)
+		r, ok := consumeWords(r, "TO")
+		if !ok || from == "" {
+			continue
+		}
+		toDB, to, _ := readQualifiedName(r)
+		if fromDB == "" {
+			fromDB = defaultDB
+		}
+		if toDB == "" {
+			toDB = defaultDB
+		}
+		pairs = append(pairs, [4]string{fromDB, from, toDB, to})
+	}
+	return pairs
+}
+
+// renamedTables returns the [db, table] names a DDL string renames away from,
+// for both RENAME TABLE and ALTER TABLE ... RENAME.
+func renamedTables(ddl, defaultDB string) [][2]string {
+	var old [][2]string
+	for _, stmt := range splitStatements(ddl) {
+		for _, pair := range parseRenamePairs(stmt, defaultDB) {
+			old = append(old, [2]string{pair[0], pair[1]})
+		}
+		rest, ok := consumeWords(stmt, "ALTER")
+		if !ok {
+			continue
+		}
+		rest = consumeOptional(consumeOptional(rest, "ONLINE"), "IGNORE")
+		if rest, ok = consumeWords(rest, "TABLE"); !ok {
+			continue
+		}
+		db, name, specs := readQualifiedName(rest)
+		if db == "" {
+			db = defaultDB
+		}
+		for _, spec := range splitTopLevel(specs, ',') {
+			spec = strings.TrimSpace(spec)
+			if hasWords(spec, "RENAME") && !hasWords(spec, "RENAME", "COLUMN") && !hasWords(spec, "RENAME", "INDEX") && !hasWords(spec, "RENAME", "KEY") {
+				old = append(old, [2]string{db, name})
+			}
+		}
+	}
+	return old
+}
+
+// cloneColumns deep-copies columns so CREATE TABLE ... LIKE does not share
+// state with the source table.
+func cloneColumns(cols []*columnModel) []*columnModel {
+	out := make([]*columnModel, len(cols))
+	for i, c := range cols {
+		cp := *c
+		out[i] = &cp
+	}
+	return out
+}
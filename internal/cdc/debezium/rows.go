@@ -0,0 +1,182 @@
+package debezium
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+// ReplayTopic reads a data topic up to its high-water marks and calls fn with
+// each decoded change event. Records whose key is not a JSON struct are
+// skipped. The whole read is bounded by ctx; each message by the history
+// idle timeout.
+func (i *Inspector) ReplayTopic(ctx context.Context, topic string, fn func(cdc.RowEvent) error) error {
+	brokers := i.topicBrokers[topic]
+	if brokers == "" {
+		brokers = strings.Join(i.cfg.Brokers, ",")
+	}
+	if brokers == "" {
+		return fmt.Errorf("no Kafka brokers known for topic %s; set cdc.brokers", topic)
+	}
+	kt, err := newKafkaHistoryTopic(brokers, topic)
+	if err != nil {
+		return err
+	}
+	return replayRowEvents(ctx, kt, historyOptions{IdleTimeout: i.cfg.HistoryIdleTimeout()}, fn)
+}
+
+// replayRowEvents reads every partition of a data topic from its first
+// retained record and calls fn with each decoded event.
+func replayRowEvents(ctx context.Context, topic historyTopic, opts historyOptions, fn func(cdc.RowEvent) error) error {
+	parts, err := topic.Partitions(ctx)
+	if err == nil {
+		// a key's events are all in one partition, which must not be skipped
+		err = unreachable(parts)
+	}
+	if err != nil {
+		return err
+	}
+	for _, p := range parts {
+		if p.Last <= p.First {
+			continue
+		}
+		if err := replayPartition(ctx, topic, p, opts, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func replayPartition(ctx context.Context, topic historyTopic, p partitionRange, opts historyOptions, fn func(cdc.RowEvent) error) error {
+	r := topic.Open(p.ID, p.First)
+	defer r.Close()
+
+	for next := p.First; next < p.Last; {
+		msgCtx, cancel := context.WithTimeout(ctx, opts.IdleTimeout)
+		m, err := r.ReadMessage(msgCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			return fmt.Errorf("partition %d stopped at offset %d of %d: %w", p.ID, next, p.Last, err)
+		}
+		next = m.Offset + 1
+		ev, ok := decodeRowEvent(m.Key, m.Value)
+		if !ok {
+			continue
+		}
+		if err := fn(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connectSchema is a Kafka Connect schema as the JSON converter writes it.
+type connectSchema struct {
+	Type       string            `json:"type"`
+	Name       string            `json:"name"`
+	Field      string            `json:"field"`
+	Parameters map[string]string `json:"parameters"`
+	Fields     []connectSchema   `json:"fields"`
+}
+
+// fieldSchemas returns the schemas of a struct schema's fields by name.
+func (s *connectSchema) fieldSchemas() map[string]cdc.FieldSchema {
+	if s == nil || len(s.Fields) == 0 {
+		return nil
+	}
+	out := make(map[string]cdc.FieldSchema, len(s.Fields))
+	for _, f := range s.Fields {
+		out[f.Field] = cdc.FieldSchema{Type: f.Type, Name: f.Name, Parameters: f.Parameters}
+	}
+	return out
+}
+
+// field returns the schema of the named field, or nil.
+func (s *connectSchema) field(name string) *connectSchema {
+	if s == nil {
+		return nil
+	}
+	for n := range s.Fields {
+		if s.Fields[n].Field == name {
+			return &s.Fields[n]
+		}
+	}
+	return nil
+}
+
+// decodeStruct decodes a JSON struct with or without the schema envelope,
+// keeping numbers as json.Number.
+func decodeStruct(data []byte) (map[string]interface{}, *connectSchema, bool) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v map[string]interface{}
+	if err := dec.Decode(&v); err != nil || v == nil {
+		return nil, nil, false
+	}
+	payload, hasPayload := v["payload"]
+	rawSchema, hasSchema := v["schema"]
+	if !hasPayload || !hasSchema || len(v) != 2 {
+		return v, nil, true
+	}
+	inner, ok := payload.(map[string]interface{})
+	if !ok {
+		return nil, nil, false
+	}
+	var schema *connectSchema
+	if b, err := json.Marshal(rawSchema); err == nil {
+		var s connectSchema
+		if json.Unmarshal(b, &s) == nil {
+			schema = &s
+		}
+	}
+	return inner, schema, true
+}
+
+// decodeRowEvent decodes a JSON change event and its key. Events flattened
+// by ExtractNewRecordState are the row itself; their __deleted field marks
+// rewritten deletes, and other fields added with a __ prefix are dropped.
+func decodeRowEvent(key, value []byte) (cdc.RowEvent, bool) {
+	var ev cdc.RowEvent
+	k, keySchema, ok := decodeStruct(key)
+	if !ok {
+		return ev, false
+	}
+	ev.Key = k
+	if value == nil {
+		ev.Fields = keySchema.fieldSchemas()
+		return ev, true
+	}
+	v, schema, ok := decodeStruct(value)
+	if !ok {
+		return ev, false
+	}
+	_, hasOp := v["op"]
+	_, hasAfter := v["after"]
+	if hasOp || hasAfter {
+		if op, _ := v["op"].(string); op != "d" {
+			ev.After, _ = v["after"].(map[string]interface{})
+		}
+		ev.Fields = schema.field("after").fieldSchemas()
+	} else {
+		if deleted, _ := v["__deleted"].(string); deleted != "true" {
+			ev.After = map[string]interface{}{}
+			for f, fv := range v {
+				if !strings.HasPrefix(f, "__") {
+					ev.After[f] = fv
+				}
+			}
+		}
+		ev.Fields = schema.fieldSchemas()
+	}
+	if ev.Fields == nil {
+		ev.Fields = keySchema.fieldSchemas()
+	}
+	return ev, true
+}
@@ -0,0 +1,81 @@
+package debezium
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+func TestDecodeRowEvent(t *testing.T) {
+	withSchema := `{"schema":{"type":"struct","fields":[
+		{"field":"before","type":"struct","optional":true,"fields":[]},
+		{"field":"after","type":"struct","optional":true,"fields":[
+			{"field":"id","type":"int64"},
+			{"field":"total","type":"bytes","name":"org.apache.kafka.connect.data.Decimal","parameters":{"scale":"2"}}]},
+		{"field":"op","type":"string"}]},
+		"payload":{"before":null,"after":{"id":12345678901234567,"total":"BNI="},"op":"c"}}`
+	ev, ok := decodeRowEvent([]byte(`{"schema":{"type":"struct","fields":[{"field":"id","type":"int64"}]},"payload":{"id":12345678901234567}}`), []byte(withSchema))
+	if !ok {
+		t.Fatal("expected the event to decode")
+	}
+	if ev.Key["id"] != json.Number("12345678901234567") || ev.After["id"] != json.Number("12345678901234567") {
+		t.Errorf("expected ids as exact json.Number, got key %v after %v", ev.Key, ev.After)
+	}
+	if f := ev.Fields["total"]; f.Name != "org.apache.kafka.connect.data.Decimal" || f.Parameters["scale"] != "2" {
+		t.Errorf("expected the Decimal schema of total, got %+v", f)
+	}
+
+	ev, ok = decodeRowEvent([]byte(`{"id":1}`), []byte(`{"before":{"id":1},"after":null,"op":"d"}`))
+	if !ok || ev.After != nil {
+		t.Errorf("expected a delete without a row image, got %+v", ev)
+	}
+
+	ev, ok = decodeRowEvent([]byte(`{"id":1}`), nil)
+	if !ok || ev.After != nil || ev.Key["id"] != json.Number("1") {
+		t.Errorf("expected a tombstone with its key, got %+v", ev)
+	}
+
+	// Flattened by ExtractNewRecordState with delete.handling.mode=rewrite
+	ev, ok = decodeRowEvent([]byte(`{"id":2}`), []byte(`{"id":2,"name":"b","__deleted":"false","__op":"u"}`))
+	if !ok || len(ev.After) != 2 || ev.After["name"] != "b" {
+		t.Errorf("expected the flattened row without __ fields, got %+v", ev.After)
+	}
+	ev, ok = decodeRowEvent([]byte(`{"id":2}`), []byte(`{"id":2,"name":"b","__deleted":"true"}`))
+	if !ok || ev.After != nil {
+		t.Errorf("expected a rewritten delete, got %+v", ev.After)
+	}
+
+	if _, ok := decodeRowEvent([]byte("\x00\x00\x00\x00\x01"), []byte(`{}`)); ok {
+		t.Error("expected a non-JSON key to be skipped")
+	}
+}
+
+func TestReplayRowEvents(t *testing.T) {
+	topic := &fakeHistoryTopic{
+		last: 4,
+		keys: [][]byte{[]byte(`{"id":1}`), []byte("avro"), []byte(`{"id":1}`), []byte(`{"id":1}`)},
+		messages: [][]byte{
+			[]byte(`{"after":{"id":1,"name":"a"},"op":"c"}`),
+			[]byte(`{}`),
+			[]byte(`{"before":{"id":1},"after":null,"op":"d"}`),
+			nil,
+		},
+	}
+	var events []cdc.RowEvent
+	err := replayRowEvents(context.Background(), topic, historyOptions{IdleTimeout: time.Second}, func(ev cdc.RowEvent) error {
+		events = append(events, ev)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, skipping the undecodable key, got %d", len(events))
+	}
+	if events[0].After["name"] != "a" || events[1].After != nil || events[2].After != nil {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
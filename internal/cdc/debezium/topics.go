@@ -0,0 +1,350 @@
+package debezium
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	kafka "github.com/segmentio/kafka-go"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+const (
+	regexRouterClass          = "org.apache.kafka.connect.transforms.RegexRouter"
+	byLogicalTableRouterClass = "io.debezium.transforms.ByLogicalTableRouter"
+)
+
+// topicRoute is a transform that renames the topic of every record whose
+// topic matches re, as RegexRouter and ByLogicalTableRouter do.
+type topicRoute struct {
+	name        string
+	re          *regexp.Regexp
+	replacement string // in Go regexp.Expand syntax
+}
+
+// topicRouter maps a captured table to the Kafka topic its change events are
+// written to: <topic.prefix>.<db>.<table>, renamed by any routing transforms
+// in the order the connector applies them.
+type topicRouter struct {
+	prefix string
+	routes []topicRoute
+}
+
+// newTopicRouter reads the topic prefix and routing transforms from a
+// connector's settings. Transforms that do not route are ignored; routes
+// with invalid patterns are reported and skipped.
+func newTopicRouter(connector string, s connectorSettings) (*topicRouter, []string) {
+	r := &topicRouter{}
+	r.prefix, _ = s.get("topic.prefix")
+	var warnings []string
+	list, _ := s.get("transforms")
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		key := func(k string) string {
+			v, _ := s.get("transforms." + name + "." + k)
+			return v
+		}
+		var pattern, replacement string
+		switch key("type") {
+		case regexRouterClass:
+			pattern, replacement = key("regex"), key("replacement")
+		case byLogicalTableRouterClass:
+			pattern, replacement = key("topic.regex"), key("topic.replacement")
+		default:
+			continue
+		}
+		if key("predicate") != "" {
+			warnings = append(warnings, fmt.Sprintf("Connector %s transform %s is conditional on predicate %s; topic names assume it always applies", connector, name, key("predicate")))
+		}
+		// Both transforms require the pattern to match the whole topic name
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Connector %s transform %s has invalid regex %q: %v", connector, name, pattern, err))
+			continue
+		}
+		r.routes = append(r.routes, topicRoute{name: name, re: re, replacement: javaReplacement(replacement)})
+	}
+	return r, warnings
+}
+
+// javaGroupRef matches a Java replacement group reference such as $1.
+var javaGroupRef = regexp.MustCompile(`\$(\d+)`)
+
+// javaReplacement converts a java.util.regex replacement string to Go
+// regexp.Expand syntax: $1 becomes ${1}, so a following letter or digit is
+// not read as part of the group name, and \$ becomes a literal $.
+func javaReplacement(s string) string {
+	const escaped = "\x00"
+	s = strings.ReplaceAll(s, `\$`, escaped)
+	s = javaGroupRef.ReplaceAllString(s, "$${$1}")
+	return strings.ReplaceAll(s, escaped, "$$")
+}
+
+// topic returns the topic the change events of db.table are written to.
+func (r *topicRouter) topic(db, table string) string {
+	topic := db + "." + table
+	if r.prefix != "" {
+		topic = r.prefix + "." + topic
+	}
+	for _, route := range r.routes {
+		if m := route.re.FindStringSubmatchIndex(topic); m != nil {
+			topic = string(route.re.ExpandString(nil, route.replacement, topic, m))
+		}
+	}
+	return topic
+}
+
+// dataTopics returns the topic of each captured table, keyed by table, and
+// reports topics shared by several tables, which ByLogicalTableRouter uses
+// to merge sharded tables; their counts cannot be attributed to one table
+// and are left out.
+func (r *topicRouter) dataTopics(connector string, qualified map[string]string) (map[string]string, []string) {
+	byTopic := map[string][]string{}
+	for table, q := range qualified {
+		db, _, _ := strings.Cut(q, ".")
+		t := r.topic(db, table)
+		byTopic[t] = append(byTopic[t], table)
+	}
+	topics := map[string]string{}
+	var warnings []string
+	for t, tables := range byTopic {
+		if len(tables) > 1 {
+			sort.Strings(tables)
+			warnings = append(warnings, fmt.Sprintf("Connector %s routes tables %s to the shared topic %s; their CDC event counts are not compared", connector, strings.Join(tables, ", "), t))
+			continue
+		}
+		topics[tables[0]] = t
+	}
+	sort.Strings(warnings)
+	return topics, warnings
+}
+
+// countEvents sums the retained records of a data topic from its partition
+// offsets, returning the partitions too.
+func countEvents(ctx context.Context, topic historyTopic, opts historyOptions) (int64, []partitionRange, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	parts, err := topic.Partitions(ctx)
+	if err == nil {
+		// a partial count would look like missing rows
+		err = unreachable(parts)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	var events int64
+	for _, p := range parts {
+		events += p.Last - p.First
+	}
+	return events, parts, nil
+}
+
+// countKeys reads a compacted data topic up to its high-water marks to count
+// the distinct keys whose latest record is not a delete, which approximates
+// the source row count.
+func countKeys(ctx context.Context, topic historyTopic, parts []partitionRange, opts historyOptions) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	// Records with the same key always land in the same partition
+	var keys int64
+	for _, p := range parts {
+		if p.Last <= p.First {
+			continue
+		}
+		n, err := countLiveKeys(ctx, topic, p, opts)
+		if err != nil {
+			return 0, err
+		}
+		keys += n
+	}
+	return keys, nil
+}
+
+// countLiveKeys counts the keys of one partition whose latest record is not
+// a tombstone or a Debezium delete event. Keys are hashed to bound memory.
+func countLiveKeys(ctx context.Context, topic historyTopic, p partitionRange, opts historyOptions) (int64, error) {
+	r := topic.Open(p.ID, p.First)
+	defer r.Close()
+
+	live := map[uint64]struct{}{}
+	for next := p.First; next < p.Last; {
+		msgCtx, cancel := context.WithTimeout(ctx, opts.IdleTimeout)
+		m, err := r.ReadMessage(msgCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				err = fmt.Errorf("timed out after %s", opts.Timeout)
+			}
+			return 0, fmt.Errorf("partition %d stopped at offset %d of %d: %w", p.ID, next, p.Last, err)
+		}
+		next = m.Offset + 1
+		h := fnv.New64a()
+		h.Write(m.Key)
+		key := h.Sum64()
+		if m.Value == nil || isDeleteEvent(m.Value) {
+			delete(live, key)
+			continue
+		}
+		live[key] = struct{}{}
+	}
+	return int64(len(live)), nil
+}
+
+// isDeleteEvent reports whether a JSON change event, with or without the
+// schema envelope, is a delete. Values in other formats are not deletes;
+// Debezium follows each delete with a tombstone by default.
+func isDeleteEvent(value []byte) bool {
+	var event struct {
+		Op      string `json:"op"`
+		Payload *struct {
+			Op string `json:"op"`
+		} `json:"payload"`
+	}
+	if json.Unmarshal(value, &event) != nil {
+		return false
+	}
+	if event.Payload != nil {
+		return event.Payload.Op == "d"
+	}
+	return event.Op == "d"
+}
+
+// CleanupPolicy returns the topic's cleanup.policy, e.g. "delete" or
+// "compact", trying each broker in turn.
+func (k *kafkaHistoryTopic) CleanupPolicy(ctx context.Context) (string, error) {
+	var lastErr error
+	for _, broker := range k.brokers {
+		client := &kafka.Client{Addr: kafka.TCP(broker)}
+		resp, err := client.DescribeConfigs(ctx, &kafka.DescribeConfigsRequest{
+			Resources: []kafka.DescribeConfigRequestResource{{
+				ResourceType: kafka.ResourceTypeTopic,
+				ResourceName: k.topic,
+				ConfigNames:  []string{"cleanup.policy"},
+			}},
+		})
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, res := range resp.Resources {
+			if res.Error != nil {
+				return "", res.Error
+			}
+			for _, e := range res.ConfigEntries {
+				if e.ConfigName == "cleanup.policy" {
+					return e.ConfigValue, nil
+				}
+			}
+		}
+		return "", errors.New("cleanup.policy not reported")
+	}
+	return "", lastErr
+}
+
+// isCompacted reports whether a cleanup.policy compacts the topic, including
+// "compact,delete".
+func isCompacted(policy string) bool {
+	for _, p := range strings.Split(policy, ",") {
+		if strings.TrimSpace(p) == "compact" {
+			return true
+		}
+	}
+	return false
+}
+
+// readDataTopics counts the records of each table's data topic into res,
+// and reads each topic's newest event for the table's last event time, lag
+// and event schema. The lag is how long ago the newest event's change was
+// made in the source. With
+// cdc.countKeys set, compacted topics are also replayed to count their live
+// keys, each within cdc.countKeysTimeoutSeconds and all of the connector's
+// within cdc.countKeysMaxRecords records.
+func (i *Inspector) readDataTopics(ctx context.Context, connector, brokersCSV string, res *cdc.Result) {
+	tables := make([]string, 0, len(res.DataTopics))
+	for t := range res.DataTopics {
+		tables = append(tables, t)
+	}
+	sort.Strings(tables)
+	opts := historyOptions{Timeout: i.cfg.HistoryTimeout(), IdleTimeout: i.cfg.HistoryIdleTimeout()}
+	keyOpts := historyOptions{Timeout: i.cfg.CountKeysTimeout(), IdleTimeout: i.cfg.HistoryIdleTimeout()}
+	budget := i.cfg.CountKeysBudget()
+	for _, t := range tables {
+		name := res.DataTopics[t]
+		kt, err := newKafkaHistoryTopic(brokersCSV, name)
+		if err != nil {
+			res.Warnings = append(res.Warnings, cdc.Warning{Kind: cdc.WarningConnector, Message: fmt.Sprintf("Connector %s data topic %s could not be read: %v", connector, name, err)})
+			continue
+		}
+		events, parts, err := countEvents(ctx, kt, opts)
+		if err != nil {
+			res.Warnings = append(res.Warnings, cdc.Warning{Kind: cdc.WarningConnector, Message: fmt.Sprintf("Connector %s data topic %s could not be read: %v", connector, name, err)})
+			continue
+		}
+		if res.EventCounts == nil {
+			res.EventCounts = map[string]int64{}
+		}
+		res.EventCounts[t] = events
+		if i.cfg.CountKeys {
+			i.countDataTopicKeys(ctx, connector, kt, name, t, events, parts, &budget, keyOpts, res)
+		}
+
+		last, err := readLastEvent(ctx, kt, opts)
+		if err != nil {
+			res.Warnings = append(res.Warnings, cdc.Warning{Kind: cdc.WarningConnector, Message: fmt.Sprintf("Connector %s data topic %s could not be read: %v", connector, name, err)})
+			continue
+		}
+		if last == nil {
+			continue
+		}
+		if res.LastEventTimes == nil {
+			res.LastEventTimes = map[string]time.Time{}
+		}
+		res.LastEventTimes[t] = last.Written
+		if res.LagSeconds == nil {
+			res.LagSeconds = map[string]float64{}
+		}
+		res.LagSeconds[t] = max(time.Since(last.Changed).Seconds(), 0)
+		if last.Fields != nil {
+			if res.EventFields == nil {
+				res.EventFields = map[string]map[string]cdc.FieldSchema{}
+			}
+			res.EventFields[t] = last.Fields
+		}
+	}
+}
+
+// countDataTopicKeys counts the live keys of a table's data topic into res
+// when the topic is compacted and its records fit in the remaining budget.
+func (i *Inspector) countDataTopicKeys(ctx context.Context, connector string, kt *kafkaHistoryTopic, name, table string, events int64, parts []partitionRange, budget *int64, opts historyOptions, res *cdc.Result) {
+	policy, err := kt.CleanupPolicy(ctx)
+	if err != nil || !isCompacted(policy) {
+		// Retained records of topics that are not compacted count events
+		return
+	}
+	if events > *budget {
+		res.Warnings = append(res.Warnings, cdc.Warning{Kind: cdc.WarningConnector, Message: fmt.Sprintf("Connector %s data topic %s live keys not counted: %d records exceed the remaining cdc.countKeysMaxRecords budget of %d", connector, name, events, *budget)})
+		return
+	}
+	*budget -= events
+	keys, err := countKeys(ctx, kt, parts, opts)
+	if err != nil {
+		res.Warnings = append(res.Warnings, cdc.Warning{Kind: cdc.WarningConnector, Message: fmt.Sprintf("Connector %s data topic %s live keys not counted: %v", connector, name, err)})
+		return
+	}
+	if res.KeyCounts == nil {
+		res.KeyCounts = map[string]int64{}
+	}
+	res.KeyCounts[table] = keys
+}
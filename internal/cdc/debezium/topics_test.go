@@ -0,0 +1,90 @@
+package debezium
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTopicRouter(t *testing.T) {
+	settings := newConnectorSettings(map[string]interface{}{
+		"topic.prefix":                        "dbserver1",
+		"transforms":                          "unwrap,route,shards",
+		"transforms.unwrap.type":              "io.debezium.transforms.ExtractNewRecordState",
+		"transforms.route.type":               "org.apache.kafka.connect.transforms.RegexRouter",
+		"transforms.route.regex":              `dbserver1\.shop\.(.*)`,
+		"transforms.route.replacement":        "cdc.$1v2",
+		"transforms.shards.type":              "io.debezium.transforms.ByLogicalTableRouter",
+		"transforms.shards.topic.regex":       `cdc\.orders_[0-9]+v2`,
+		"transforms.shards.topic.replacement": "cdc.orders_all",
+	}, "2.5.0.Final")
+	router, warnings := newTopicRouter("shop", settings)
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+	for _, tc := range []struct{ db, table, want string }{
+		{"shop", "users", "cdc.usersv2"},
+		{"shop", "orders_1", "cdc.orders_all"},
+		{"billing", "invoices", "dbserver1.billing.invoices"},
+	} {
+		if got := router.topic(tc.db, tc.table); got != tc.want {
+			t.Errorf("%s.%s: expected topic %s, got %s", tc.db, tc.table, tc.want, got)
+		}
+	}
+
+	topics, warnings := router.dataTopics("shop", map[string]string{
+		"users":    "shop.users",
+		"orders_1": "shop.orders_1",
+		"orders_2": "shop.orders_2",
+	})
+	if want := map[string]string{"users": "cdc.usersv2"}; !reflect.DeepEqual(topics, want) {
+		t.Fatalf("expected topics %v, got %v", want, topics)
+	}
+	if len(warnings) != 1 || warnings[0] != "Connector shop routes tables orders_1, orders_2 to the shared topic cdc.orders_all; their CDC event counts are not compared" {
+		t.Fatalf("expected a shared topic warning, got %v", warnings)
+	}
+
+	_, warnings = newTopicRouter("shop", newConnectorSettings(map[string]interface{}{
+		"transforms":             "route",
+		"transforms.route.type":  "org.apache.kafka.connect.transforms.RegexRouter",
+		"transforms.route.regex": "(unclosed",
+	}, ""))
+	if len(warnings) != 1 {
+		t.Fatalf("expected an invalid regex warning, got %v", warnings)
+	}
+}
+
+func TestCountTopic(t *testing.T) {
+	topic := &fakeHistoryTopic{
+		first: 10,
+		last:  16,
+		keys:  [][]byte{[]byte(`{"id":1}`), []byte(`{"id":2}`), []byte(`{"id":3}`), []byte(`{"id":2}`), []byte(`{"id":2}`), []byte(`{"id":3}`)},
+		messages: [][]byte{
+			[]byte(`{"payload":{"op":"r"}}`),
+			[]byte(`{"payload":{"op":"r"}}`),
+			[]byte(`{"payload":{"op":"c"}}`),
+			[]byte(`{"payload":{"op":"d"}}`),
+			nil,
+			[]byte(`{"op":"u"}`),
+		},
+	}
+	opts := historyOptions{Timeout: 5 * time.Second, IdleTimeout: time.Second}
+	events, parts, err := countEvents(context.Background(), topic, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events != 6 {
+		t.Fatalf("expected 6 events, got %d", events)
+	}
+	keys, err := countKeys(context.Background(), topic, parts, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys != 2 {
+		t.Fatalf("expected ids 1 and 3 to be live, got %d", keys)
+	}
+	if !isCompacted("compact,delete") || isCompacted("delete") {
+		t.Fatal("unexpected cleanup.policy classification")
+	}
+}
@@ -2,24 +2,123 @@ package cdc
 
 import (
 	"context"
+	"io"
 	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
 )
 
 type ColumnInfo struct {
 	Type     string
 	Nullable bool
+	Length   *int    `json:",omitempty"` // character length or numeric precision, when known
+	Scale    *int    `json:",omitempty"` // numeric scale, when known
+	Position int     `json:",omitempty"` // 1-based ordinal position, when known
+	Charset  string  `json:",omitempty"`
+	Default  *string `json:",omitempty"` // default value expression, when known
+	// EnumValues are the permitted values of an ENUM or SET column, in order
+	EnumValues []string `json:",omitempty"`
 }
 
 type TableSchema struct {
-	Columns map[string]ColumnInfo
+	Columns    map[string]ColumnInfo
+	PrimaryKey []string // primary key columns recorded in the CDC schema, in key order
 }
 
 type Result struct {
 	ConnectorReachable bool
 	CapturedTables     []string
-	TableSchemas       map[string]TableSchema // optional, may be empty
-	SchemaTimestamps   map[string]time.Time   // last schema change message timestamp from Kafka history
-	Warnings           []string
+	CapturedOther      []string                           // tables outside the inspected source tables the connector captures, when known
+	TableSchemas       map[string]TableSchema             // optional, may be empty
+	SchemaTimestamps   map[string]time.Time               // last schema change message timestamp from Kafka history
+	EventCounts        map[string]int64                   // optional per-table counts of retained CDC records
+	KeyCounts          map[string]int64                   // optional per-table live key counts of compacted topics, compared to source row counts
+	DataTopics         map[string]string                  // Kafka topic each table's change events are written to, when known
+	LastEventTimes     map[string]time.Time               // when the newest change event of each table was written to Kafka
+	EventFields        map[string]map[string]FieldSchema  // Connect schemas of the row fields of each table's newest change event, when events carry schemas
+	Heartbeat          *Heartbeat                         // the connector's heartbeat settings and newest heartbeat
+	LagSeconds         map[string]float64                 // seconds since the source change of each table's newest change event, when known
+	KeyColumns         map[string][]string                // columns the connector uses as the message key, per table
+	ColumnPolicies     map[string]map[string]ColumnPolicy // columns the connector deliberately drops or rewrites, per table
+	EmitSettings       map[string]map[string]string       // connector settings that change how column values are emitted, per table
+	DatabaseUser       string                             // source account the connector connects as, when known
+	Offset             *SourceOffset                      // committed source position of the connector, when known
+	Lag                *ReplicationLag                    // distance from Offset to the source's current position, when measured
+	Warnings           []Warning
+}
+
+// SourceOffset is the source log position a connector resumes from, as last
+// committed to its offset store.
+type SourceOffset struct {
+	BinlogFile     string `json:",omitempty"`
+	BinlogPosition int64  `json:",omitempty"`
+	GTIDSet        string `json:",omitempty"` // GTIDs the connector has processed, when GTIDs are in use
+	// Timestamp is the source time of the event at the position, when known
+	Timestamp time.Time `json:",omitempty"`
+}
+
+// ColumnPolicy is how a connector deliberately alters a column's data.
+type ColumnPolicy string
+
+const (
+	ColumnExcluded  ColumnPolicy = "excluded"  // not emitted at all
+	ColumnMasked    ColumnPolicy = "masked"    // replaced by a fixed string of asterisks
+	ColumnHashed    ColumnPolicy = "hashed"    // replaced by a salted hash
+	ColumnTruncated ColumnPolicy = "truncated" // values cut to a maximum length
+)
+
+// Heartbeat describes the heartbeat records a connector writes while its
+// captured tables are idle. Each heartbeat commits the connector's current
+// source position, so Result.Offset keeps advancing on an idle source.
+type Heartbeat struct {
+	IntervalMs int64  // heartbeat.interval.ms; 0 when heartbeats are disabled
+	Topic      string `json:",omitempty"`
+	// Read reports whether the topic was read. Last is when the newest
+	// heartbeat was written, zero when the topic holds none.
+	Read bool      `json:",omitempty"`
+	Last time.Time `json:",omitempty"`
+}
+
+// ReplicationLag is how far a connector's committed position trails the
+// source. Each measure is nil when it cannot be determined.
+type ReplicationLag struct {
+	Bytes        *int64   `json:",omitempty"` // binlog bytes not yet read
+	Transactions *int64   `json:",omitempty"` // GTID transactions not yet read
+	Seconds      *float64 `json:",omitempty"` // age of the committed position; 0 when caught up
+}
+
+// WarningKind classifies a Warning by what it concerns.
+type WarningKind string
+
+const (
+	WarningConnector WarningKind = "connector" // connector or task health, and topics or offsets that could not be read
+	WarningSnapshot  WarningKind = "snapshot"  // snapshot settings that may miss initial data
+	WarningHistory   WarningKind = "history"   // schema history that is incomplete or unreadable
+	WarningConfig    WarningKind = "config"    // connector settings that are invalid, ignored or conflicting
+)
+
+// Warning is a problem found while inspecting a connector that is not
+// itself schema drift.
+type Warning struct {
+	Kind    WarningKind
+	Message string
+}
+
+// RowEvent is a change event decoded from a table's data topic.
+type RowEvent struct {
+	Key map[string]interface{} // message key fields
+	// After is the row image after the change; nil for deletes and tombstones
+	After map[string]interface{}
+	// Fields are the Connect schemas of the row's fields, keyed by field
+	// name, when the events carry schemas
+	Fields map[string]FieldSchema
+}
+
+// FieldSchema is the Kafka Connect schema of an event field.
+type FieldSchema struct {
+	Type       string            // Connect type, e.g. "int64" or "bytes"
+	Name       string            // semantic type, e.g. "io.debezium.time.Date"
+	Parameters map[string]string // e.g. the scale of a Decimal
 }
 
 // ConnectorResult pairs a connector name with its inspection Result.
@@ -32,3 +131,34 @@ type Inspector interface {
 	Name() string
 	Inspect(ctx context.Context) (*Result, error)
 }
+
+// ConnectorInspector is implemented by inspectors that can report each
+// connector separately. Callers should prefer it over Inspect when available.
+type ConnectorInspector interface {
+	InspectConnectors(ctx context.Context) ([]*ConnectorResult, error)
+}
+
+// ProgressReporter is implemented by inspectors that can report progress of
+// long-running reads, such as replaying a schema history topic. Progress is
+// written as human-readable lines.
+type ProgressReporter interface {
+	SetProgress(w io.Writer)
+}
+
+// SourceAware is implemented by inspectors that evaluate their capture
+// filters against the live source tables. Callers pass the inspected
+// database (or schema), its inspected tables and the names of its other
+// tables before inspecting.
+type SourceAware interface {
+	SetSource(database string, tables []source.TableInfo, otherTables []string)
+}
+
+// TopicReplayer is implemented by inspectors that can replay the change
+// events of a data topic named in Result.DataTopics. Callers inspect the
+// connectors first, which resolves the brokers of each topic.
+type TopicReplayer interface {
+	// ReplayTopic calls fn with each event retained in topic, in order
+	// within each partition; events with the same key share a partition.
+	// JSON numbers are passed as json.Number.
+	ReplayTopic(ctx context.Context, topic string, fn func(RowEvent) error) error
+}
@@ -0,0 +1,59 @@
+package cdc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+)
+
+// Factory creates an Inspector for the loaded config.
+type Factory func(cfg *config.Config) (Inspector, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+func init() {
+	config.SetKnownTypes("cdc.type", Types)
+}
+
+// Register makes a CDC inspector available under the given cdc.type.
+// It panics if typ is empty, factory is nil or typ is registered twice.
+func Register(typ string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if typ == "" || factory == nil {
+		panic("cdc: Register requires a type and a factory")
+	}
+	if _, dup := registry[typ]; dup {
+		panic("cdc: Register called twice for type " + typ)
+	}
+	registry[typ] = factory
+}
+
+// Types returns the registered CDC types in sorted order.
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	var types []string
+	for t := range registry {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// New creates the inspector registered for cfg.CDC.Type.
+func New(cfg *config.Config) (Inspector, error) {
+	registryMu.RLock()
+	factory, ok := registry[cfg.CDC.Type]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported cdc.type %q (registered: %s)", cfg.CDC.Type, strings.Join(Types(), ", "))
+	}
+	return factory(cfg)
+}
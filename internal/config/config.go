@@ -5,15 +5,25 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Source SourceConfig  `yaml:"source"`
-	CDC    CDCConfig     `yaml:"cdc"`
-	Tables []TableConfig `yaml:"tables"`
+	Source        SourceConfig  `yaml:"source"`
+	CDC           CDCConfig     `yaml:"cdc"`
+	Tables        []TableConfig `yaml:"tables"`
+	ExcludeTables []string      `yaml:"excludeTables"` // names or patterns removed from the tables selection
+	Tolerances    Tolerances    `yaml:"tolerances"`
+	Sink          *SinkConfig   `yaml:"sink"` // optional, for datawatch checksum
+
+	// excludeRegexps are the compiled "re:" entries of ExcludeTables, keyed
+	// by entry and set when the config is validated
+	excludeRegexps map[string]*regexp.Regexp
 }
 
 type SourceConfig struct {
@@ -27,11 +37,191 @@ type CDCConfig struct {
 	ConnectURL  string   `yaml:"connect_url"`
 	Brokers     []string `yaml:"brokers"`
 	TopicPrefix string   `yaml:"topicPrefix"`
+	// HistoryTimeoutSeconds bounds the whole schema history read and
+	// HistoryIdleTimeoutSeconds the wait for each message. Zero uses the
+	// defaults (DefaultHistoryTimeoutSeconds, DefaultHistoryIdleTimeoutSeconds).
+	HistoryTimeoutSeconds     float64 `yaml:"historyTimeoutSeconds"`
+	HistoryIdleTimeoutSeconds float64 `yaml:"historyIdleTimeoutSeconds"`
+	// MaxDowntimeSeconds is the longest the connectors may be stopped, e.g.
+	// the worst outage seen so far. Source log retention must outlast it.
+	// Zero uses DefaultMaxDowntimeSeconds.
+	MaxDowntimeSeconds float64 `yaml:"maxDowntimeSeconds"`
+	// OffsetsTopic is the Connect worker's offset.storage.topic, read from
+	// Brokers when the Connect REST API cannot report connector offsets
+	// (before Kafka 3.5). Empty uses DefaultOffsetsTopic.
+	OffsetsTopic string `yaml:"offsetsTopic"`
+	// HeartbeatMaxAgeSeconds is how old a connector's newest heartbeat may
+	// be. Zero uses three heartbeat intervals, and at least
+	// DefaultHeartbeatMaxAgeSeconds.
+	HeartbeatMaxAgeSeconds float64 `yaml:"heartbeatMaxAgeSeconds"`
+	// CountKeys replays compacted data topics to count their live keys,
+	// which track source row counts better than retained records but read
+	// every record on each check. CountKeysTimeoutSeconds bounds each
+	// topic's read and CountKeysMaxRecords the records read per connector;
+	// zero uses DefaultCountKeysTimeoutSeconds and DefaultCountKeysMaxRecords.
+	CountKeys               bool    `yaml:"countKeys"`
+	CountKeysTimeoutSeconds float64 `yaml:"countKeysTimeoutSeconds"`
+	CountKeysMaxRecords     int64   `yaml:"countKeysMaxRecords"`
+}
+
+const (
+	DefaultHistoryTimeoutSeconds     = 120
+	DefaultHistoryIdleTimeoutSeconds = 10
+	DefaultMaxDowntimeSeconds        = 24 * 60 * 60
+	DefaultOffsetsTopic              = "connect-offsets"
+	DefaultHeartbeatMaxAgeSeconds    = 5 * 60
+	DefaultCountKeysTimeoutSeconds   = 60
+	DefaultCountKeysMaxRecords       = 10_000_000
+)
+
+// HistoryTimeout returns the configured schema history read timeout.
+func (c CDCConfig) HistoryTimeout() time.Duration {
+	return secondsOr(c.HistoryTimeoutSeconds, DefaultHistoryTimeoutSeconds)
+}
+
+// HistoryIdleTimeout returns the configured per-message schema history timeout.
+func (c CDCConfig) HistoryIdleTimeout() time.Duration {
+	return secondsOr(c.HistoryIdleTimeoutSeconds, DefaultHistoryIdleTimeoutSeconds)
 }
 
+// MaxDowntime returns the connector downtime source log retention must cover.
+func (c CDCConfig) MaxDowntime() time.Duration {
+	return secondsOr(c.MaxDowntimeSeconds, DefaultMaxDowntimeSeconds)
+}
+
+// HeartbeatMaxAge returns how old the newest heartbeat of a connector with the
+// given heartbeat interval may be.
+func (c CDCConfig) HeartbeatMaxAge(interval time.Duration) time.Duration {
+	if c.HeartbeatMaxAgeSeconds > 0 {
+		return secondsOr(c.HeartbeatMaxAgeSeconds, DefaultHeartbeatMaxAgeSeconds)
+	}
+	return max(3*interval, DefaultHeartbeatMaxAgeSeconds*time.Second)
+}
+
+// CountKeysTimeout returns the configured timeout for counting one data
+// topic's live keys.
+func (c CDCConfig) CountKeysTimeout() time.Duration {
+	return secondsOr(c.CountKeysTimeoutSeconds, DefaultCountKeysTimeoutSeconds)
+}
+
+// CountKeysBudget returns how many records counting live keys may read per
+// connector.
+func (c CDCConfig) CountKeysBudget() int64 {
+	if c.CountKeysMaxRecords <= 0 {
+		return DefaultCountKeysMaxRecords
+	}
+	return c.CountKeysMaxRecords
+}
+
+// OffsetsTopicName returns the Connect offsets topic to read.
+func (c CDCConfig) OffsetsTopicName() string {
+	if strings.TrimSpace(c.OffsetsTopic) == "" {
+		return DefaultOffsetsTopic
+	}
+	return c.OffsetsTopic
+}
+
+func secondsOr(seconds, def float64) time.Duration {
+	if seconds <= 0 {
+		seconds = def
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// SinkConfig is a database a sink connector replicates the captured tables
+// to. Type names a registered source inspector, which reads the sink tables.
+type SinkConfig struct {
+	Type   string `yaml:"type"`
+	DSN    string `yaml:"dsn"`
+	Schema string `yaml:"schema"`
+	// TableNameFormat names the sink table of a source table, with ${table}
+	// replaced by the source table name. Empty uses the source name.
+	TableNameFormat string `yaml:"tableNameFormat"`
+	// ChunkSize is how many source rows are checksummed at a time. Zero uses
+	// DefaultChunkSize.
+	ChunkSize int `yaml:"chunkSize"`
+	// ChunkPauseSeconds is how long to wait after each chunk. Nil uses
+	// DefaultChunkPauseSeconds; zero does not pause.
+	ChunkPauseSeconds *float64 `yaml:"chunkPauseSeconds"`
+	// MaxRowsPerSecond caps the rows read from each database. Zero is
+	// unlimited.
+	MaxRowsPerSecond float64 `yaml:"maxRowsPerSecond"`
+}
+
+const (
+	DefaultChunkSize         = 1000
+	DefaultChunkPauseSeconds = 0.1
+)
+
+// SourceConfig returns a config for creating the source inspector that reads
+// the sink database.
+func (s SinkConfig) SourceConfig() *Config {
+	return &Config{Source: SourceConfig{Type: s.Type, DSN: s.DSN, Schema: s.Schema}}
+}
+
+// TableName returns the sink table a source table is written to.
+func (s SinkConfig) TableName(table string) string {
+	if s.TableNameFormat == "" {
+		return table
+	}
+	return strings.ReplaceAll(s.TableNameFormat, "${table}", table)
+}
+
+// Chunk returns the configured number of rows per chunk.
+func (s SinkConfig) Chunk() int {
+	if s.ChunkSize <= 0 {
+		return DefaultChunkSize
+	}
+	return s.ChunkSize
+}
+
+// ChunkPause returns how long to wait after each chunk.
+func (s SinkConfig) ChunkPause() time.Duration {
+	if s.ChunkPauseSeconds == nil {
+		return time.Duration(DefaultChunkPauseSeconds * float64(time.Second))
+	}
+	return time.Duration(*s.ChunkPauseSeconds * float64(time.Second))
+}
+
+// TableConfig selects one or more tables for inspection. Name is a literal
+// table name, a glob (e.g. "orders_*") or a regular expression prefixed with
+// "re:" (e.g. "re:^audit_[0-9]{4}$").
 type TableConfig struct {
-	Name       string   `yaml:"name"`
-	PrimaryKey []string `yaml:"primaryKey"`
+	Name       string      `yaml:"name"`
+	PrimaryKey []string    `yaml:"primaryKey"`
+	Tolerances *Tolerances `yaml:"tolerances"` // optional per-table override
+
+	re *regexp.Regexp // compiled "re:" name, set when the config is validated
+}
+
+// Tolerances bound the row count and lag drift checks. A nil field disables
+// the corresponding check.
+type Tolerances struct {
+	RowCountPct   *float64 `yaml:"rowCountPct"`
+	MaxLagSeconds *float64 `yaml:"maxLagSeconds"`
+	// MaxLagBytes and MaxLagTransactions bound how far each connector trails
+	// the source binlog. They are connector-wide, so only the global
+	// tolerances may set them.
+	MaxLagBytes        *int64 `yaml:"maxLagBytes"`
+	MaxLagTransactions *int64 `yaml:"maxLagTransactions"`
+}
+
+// TolerancesFor returns the tolerances that apply to table: the global
+// tolerances with any fields set in the matching table entry's override
+// taking precedence.
+func (c *Config) TolerancesFor(table string) Tolerances {
+	tol := c.Tolerances
+	t := c.TableFor(table)
+	if t == nil || t.Tolerances == nil {
+		return tol
+	}
+	if t.Tolerances.RowCountPct != nil {
+		tol.RowCountPct = t.Tolerances.RowCountPct
+	}
+	if t.Tolerances.MaxLagSeconds != nil {
+		tol.MaxLagSeconds = t.Tolerances.MaxLagSeconds
+	}
+	return tol
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -60,13 +250,47 @@ func LoadConfig(path string) (*Config, error) {
 	return &cfg, nil
 }
 
+// knownTypes lists the registered values of the config's type fields. The
+// source and cdc registries set them, so a program that links inspectors
+// rejects unknown types when the config loads.
+var (
+	knownTypesMu sync.RWMutex
+	knownTypes   = map[string]func() []string{}
+)
+
+// SetKnownTypes makes LoadConfig reject values of field, "source.type" or
+// "cdc.type", that types does not return. sink.type takes source types.
+func SetKnownTypes(field string, types func() []string) {
+	knownTypesMu.Lock()
+	defer knownTypesMu.Unlock()
+	knownTypes[field] = types
+}
+
+// checkType returns an error message when value is not among the known types
+// of field, listed under name, or "" when it is or none are known.
+func checkType(name, field, value string) string {
+	knownTypesMu.RLock()
+	types := knownTypes[field]
+	knownTypesMu.RUnlock()
+	if types == nil {
+		return ""
+	}
+	registered := types()
+	for _, t := range registered {
+		if t == value {
+			return ""
+		}
+	}
+	return fmt.Sprintf("unsupported %s %q (registered: %s)", name, value, strings.Join(registered, ", "))
+}
+
 func (c *Config) validate() error {
 	var errs []string
 
 	if strings.TrimSpace(c.Source.Type) == "" {
-		errs = append(errs, "source.type is required and must be 'mysql'")
-	} else if c.Source.Type != "mysql" {
-		errs = append(errs, "unsupported source.type: only 'mysql' is supported")
+		errs = append(errs, "source.type is required (e.g. 'mysql' or 'postgres')")
+	} else if msg := checkType("source.type", "source.type", c.Source.Type); msg != "" {
+		errs = append(errs, msg)
 	}
 	if strings.TrimSpace(c.Source.DSN) == "" {
 		errs = append(errs, "source.dsn is required")
@@ -77,8 +301,8 @@ func (c *Config) validate() error {
 
 	if strings.TrimSpace(c.CDC.Type) == "" {
 		errs = append(errs, "cdc.type is required (e.g. 'debezium')")
-	} else if c.CDC.Type != "debezium" {
-		errs = append(errs, "unsupported cdc.type: only 'debezium' is supported")
+	} else if msg := checkType("cdc.type", "cdc.type", c.CDC.Type); msg != "" {
+		errs = append(errs, msg)
 	}
 	// Debezium specific checks
 	if c.CDC.Type == "debezium" {
@@ -96,16 +320,40 @@ func (c *Config) validate() error {
 				errs = append(errs, fmt.Sprintf("cdc.brokers contains invalid broker address: %q", b))
 			}
 		}
+		if c.CDC.HistoryTimeoutSeconds < 0 {
+			errs = append(errs, "cdc.historyTimeoutSeconds must be >= 0")
+		}
+		if c.CDC.HistoryIdleTimeoutSeconds < 0 {
+			errs = append(errs, "cdc.historyIdleTimeoutSeconds must be >= 0")
+		}
+	}
+
+	if c.CDC.MaxDowntimeSeconds < 0 {
+		errs = append(errs, "cdc.maxDowntimeSeconds must be >= 0")
+	}
+	if c.CDC.HeartbeatMaxAgeSeconds < 0 {
+		errs = append(errs, "cdc.heartbeatMaxAgeSeconds must be >= 0")
+	}
+	if c.CDC.CountKeysTimeoutSeconds < 0 {
+		errs = append(errs, "cdc.countKeysTimeoutSeconds must be >= 0")
+	}
+	if c.CDC.CountKeysMaxRecords < 0 {
+		errs = append(errs, "cdc.countKeysMaxRecords must be >= 0")
 	}
 
 	if len(c.Tables) == 0 {
 		errs = append(errs, "at least one table is required in tables")
 	}
-	for _, table := range c.Tables {
+	for i, table := range c.Tables {
 		if strings.TrimSpace(table.Name) == "" {
 			errs = append(errs, "table.name is required")
 			continue
 		}
+		re, msg := compileTablePattern(fmt.Sprintf("table %s", table.Name), table.Name)
+		if msg != "" {
+			errs = append(errs, msg)
+		}
+		c.Tables[i].re = re
 		if len(table.PrimaryKey) == 0 {
 			errs = append(errs, fmt.Sprintf("table %s must define primaryKey", table.Name))
 		}
@@ -116,8 +364,86 @@ func (c *Config) validate() error {
 		}
 	}
 
+	c.excludeRegexps = nil
+	for _, ex := range c.ExcludeTables {
+		if strings.TrimSpace(ex) == "" {
+			errs = append(errs, "excludeTables has empty entry")
+			continue
+		}
+		re, msg := compileTablePattern("excludeTables entry", ex)
+		if msg != "" {
+			errs = append(errs, msg)
+		}
+		if re != nil {
+			if c.excludeRegexps == nil {
+				c.excludeRegexps = map[string]*regexp.Regexp{}
+			}
+			c.excludeRegexps[ex] = re
+		}
+	}
+
+	errs = append(errs, c.Tolerances.validate("tolerances")...)
+	for _, table := range c.Tables {
+		if table.Tolerances != nil {
+			prefix := fmt.Sprintf("table %s tolerances", table.Name)
+			errs = append(errs, table.Tolerances.validate(prefix)...)
+			if table.Tolerances.MaxLagBytes != nil || table.Tolerances.MaxLagTransactions != nil {
+				errs = append(errs, fmt.Sprintf("%s: maxLagBytes and maxLagTransactions apply per connector; set them in the global tolerances", prefix))
+			}
+		}
+	}
+
+	if c.Sink != nil {
+		errs = append(errs, c.Sink.validate()...)
+	}
+
 	if len(errs) > 0 {
 		return errors.New("config validation failed:\n  - " + strings.Join(errs, "\n  - "))
 	}
 	return nil
 }
+
+func (t Tolerances) validate(prefix string) []string {
+	var errs []string
+	if t.RowCountPct != nil && *t.RowCountPct < 0 {
+		errs = append(errs, fmt.Sprintf("%s.rowCountPct must be >= 0", prefix))
+	}
+	if t.MaxLagSeconds != nil && *t.MaxLagSeconds < 0 {
+		errs = append(errs, fmt.Sprintf("%s.maxLagSeconds must be >= 0", prefix))
+	}
+	if t.MaxLagBytes != nil && *t.MaxLagBytes < 0 {
+		errs = append(errs, fmt.Sprintf("%s.maxLagBytes must be >= 0", prefix))
+	}
+	if t.MaxLagTransactions != nil && *t.MaxLagTransactions < 0 {
+		errs = append(errs, fmt.Sprintf("%s.maxLagTransactions must be >= 0", prefix))
+	}
+	return errs
+}
+
+func (s *SinkConfig) validate() []string {
+	var errs []string
+	if strings.TrimSpace(s.Type) == "" {
+		errs = append(errs, "sink.type is required (e.g. 'mysql' or 'postgres')")
+	} else if msg := checkType("sink.type", "source.type", s.Type); msg != "" {
+		errs = append(errs, msg)
+	}
+	if strings.TrimSpace(s.DSN) == "" {
+		errs = append(errs, "sink.dsn is required")
+	}
+	if strings.TrimSpace(s.Schema) == "" {
+		errs = append(errs, "sink.schema is required")
+	}
+	if s.TableNameFormat != "" && !strings.Contains(s.TableNameFormat, "${table}") {
+		errs = append(errs, "sink.tableNameFormat must contain ${table}")
+	}
+	if s.ChunkSize < 0 {
+		errs = append(errs, "sink.chunkSize must be >= 0")
+	}
+	if s.ChunkPauseSeconds != nil && *s.ChunkPauseSeconds < 0 {
+		errs = append(errs, "sink.chunkPauseSeconds must be >= 0")
+	}
+	if s.MaxRowsPerSecond < 0 {
+		errs = append(errs, "sink.maxRowsPerSecond must be >= 0")
+	}
+	return errs
+}
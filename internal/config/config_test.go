@@ -2,7 +2,11 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestLoadConfig_Valid(t *testing.T) {
@@ -33,3 +37,265 @@ func TestLoadConfig_Invalid(t *testing.T) {
 		t.Fatalf("expected validation error, got nil")
 	}
 }
+
+func TestLoadConfig_Tolerances(t *testing.T) {
+	f, err := os.CreateTemp("", "cfg-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	n := "source:\n  type: mysql\n  dsn: u:p@tcp(localhost:3306)/db\n  schema: db\ncdc:\n  type: debezium\n  connect_url: http://localhost:8083\ntables:\n  - name: users\n    primaryKey: [id]\n  - name: orders\n    primaryKey: [id]\n    tolerances:\n      maxLagSeconds: 300\ntolerances:\n  rowCountPct: 1.5\n  maxLagSeconds: 60\n"
+	if _, err := f.WriteString(n); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	cfg, err := LoadConfig(f.Name())
+	if err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+	users := cfg.TolerancesFor("users")
+	if users.RowCountPct == nil || *users.RowCountPct != 1.5 || users.MaxLagSeconds == nil || *users.MaxLagSeconds != 60 {
+		t.Fatalf("unexpected users tolerances: %+v", users)
+	}
+	orders := cfg.TolerancesFor("orders")
+	if orders.RowCountPct == nil || *orders.RowCountPct != 1.5 {
+		t.Fatalf("expected orders to inherit rowCountPct, got %+v", orders)
+	}
+	if orders.MaxLagSeconds == nil || *orders.MaxLagSeconds != 300 {
+		t.Fatalf("expected orders maxLagSeconds override of 300, got %+v", orders)
+	}
+}
+
+func TestLoadConfig_NegativeTolerance(t *testing.T) {
+	f, err := os.CreateTemp("", "cfg-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	n := "source:\n  type: mysql\n  dsn: u:p@tcp(localhost:3306)/db\n  schema: db\ncdc:\n  type: debezium\n  connect_url: http://localhost:8083\ntables:\n  - name: users\n    primaryKey: [id]\ntolerances:\n  rowCountPct: -1\n"
+	if _, err := f.WriteString(n); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if _, err := LoadConfig(f.Name()); err == nil {
+		t.Fatalf("expected validation error for negative rowCountPct, got nil")
+	}
+}
+
+func TestTableFor_PatternsAndExcludes(t *testing.T) {
+	cfg := &Config{
+		Tables: []TableConfig{
+			{Name: "users", PrimaryKey: []string{"id"}},
+			{Name: "orders_*", PrimaryKey: []string{"id"}},
+			{Name: `re:audit_[0-9]{4}`, PrimaryKey: []string{"id"}},
+		},
+		ExcludeTables: []string{"orders_archive"},
+	}
+	cases := map[string]bool{
+		"users":          true,
+		"users_old":      false,
+		"orders_2024":    true,
+		"orders_archive": false,
+		"audit_2023":     true,
+		"audit_2023_bak": false,
+		"payments":       false,
+	}
+	for name, want := range cases {
+		if got := cfg.IncludesTable(name); got != want {
+			t.Errorf("IncludesTable(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if tc := cfg.TableFor("orders_2024"); tc == nil || tc.Name != "orders_*" {
+		t.Fatalf("expected orders_2024 to match orders_*, got %+v", tc)
+	}
+}
+
+func TestValidate_CompilesTableRegexps(t *testing.T) {
+	cfg := &Config{
+		Source:        SourceConfig{Type: "mysql", DSN: "u:p@tcp(localhost:3306)/db", Schema: "db"},
+		CDC:           CDCConfig{Type: "debezium", ConnectURL: "http://localhost:8083"},
+		Tables:        []TableConfig{{Name: `re:audit_[0-9]{4}`, PrimaryKey: []string{"id"}}, {Name: "users", PrimaryKey: []string{"id"}}},
+		ExcludeTables: []string{`re:audit_1[0-9]{3}`, "orders_*"},
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Tables[0].re == nil || cfg.Tables[1].re != nil {
+		t.Fatalf("expected only the re: table to be compiled, got %v and %v", cfg.Tables[0].re, cfg.Tables[1].re)
+	}
+	if len(cfg.excludeRegexps) != 1 || cfg.excludeRegexps[`re:audit_1[0-9]{3}`] == nil {
+		t.Fatalf("expected the re: exclude to be compiled, got %v", cfg.excludeRegexps)
+	}
+	if !cfg.IncludesTable("audit_2023") || cfg.IncludesTable("audit_1999") {
+		t.Fatalf("expected audit_2023 included and audit_1999 excluded")
+	}
+}
+
+func TestValidate_InvalidTablePattern(t *testing.T) {
+	cfg := &Config{
+		Source: SourceConfig{Type: "mysql", DSN: "u:p@tcp(localhost:3306)/db", Schema: "db"},
+		CDC:    CDCConfig{Type: "debezium", ConnectURL: "http://localhost:8083"},
+		Tables: []TableConfig{{Name: "re:orders_(", PrimaryKey: []string{"id"}}},
+	}
+	if err := cfg.validate(); err == nil {
+		t.Fatalf("expected validation error for invalid regex, got nil")
+	}
+}
+
+func TestValidate_PostgresSource(t *testing.T) {
+	cfg := &Config{
+		Source: SourceConfig{Type: "postgres", DSN: "postgres://u:p@localhost:5432/db?sslmode=disable", Schema: "public"},
+		CDC:    CDCConfig{Type: "debezium", ConnectURL: "http://localhost:8083"},
+		Tables: []TableConfig{{Name: "users", PrimaryKey: []string{"id"}}},
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("expected postgres source to be valid, got: %v", err)
+	}
+}
+
+func TestCDCConfig_HistoryTimeouts(t *testing.T) {
+	var c CDCConfig
+	if got := c.HistoryTimeout(); got != DefaultHistoryTimeoutSeconds*time.Second {
+		t.Fatalf("expected default history timeout, got %v", got)
+	}
+	c.HistoryIdleTimeoutSeconds = 2.5
+	if got := c.HistoryIdleTimeout(); got != 2500*time.Millisecond {
+		t.Fatalf("expected 2.5s idle timeout, got %v", got)
+	}
+
+	cfg := &Config{
+		Source: SourceConfig{Type: "mysql", DSN: "u:p@tcp(localhost:3306)/db", Schema: "db"},
+		CDC:    CDCConfig{Type: "debezium", ConnectURL: "http://localhost:8083", HistoryTimeoutSeconds: -1},
+		Tables: []TableConfig{{Name: "users", PrimaryKey: []string{"id"}}},
+	}
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), "cdc.historyTimeoutSeconds") {
+		t.Fatalf("expected historyTimeoutSeconds validation error, got %v", err)
+	}
+}
+
+func TestCDCConfig_MaxDowntime(t *testing.T) {
+	var c CDCConfig
+	if got := c.MaxDowntime(); got != 24*time.Hour {
+		t.Fatalf("expected a one-day default, got %v", got)
+	}
+	c.MaxDowntimeSeconds = 3 * 24 * 60 * 60
+	if got := c.MaxDowntime(); got != 72*time.Hour {
+		t.Fatalf("expected 72h, got %v", got)
+	}
+}
+
+func TestLoadConfig_ConnectorLagTolerances(t *testing.T) {
+	base := "source:\n  type: mysql\n  dsn: u:p@tcp(localhost:3306)/db\n  schema: db\ncdc:\n  type: debezium\n  connect_url: http://localhost:8083\ntables:\n  - name: users\n    primaryKey: [id]\n"
+	load := func(yaml string) (*Config, error) {
+		f, err := os.CreateTemp("", "cfg-*.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(f.Name())
+		if _, err := f.WriteString(yaml); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+		return LoadConfig(f.Name())
+	}
+
+	cfg, err := load(base + "tolerances:\n  maxLagBytes: 1048576\n  maxLagTransactions: 500\n")
+	if err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+	if tol := cfg.TolerancesFor("users"); tol.MaxLagBytes == nil || *tol.MaxLagBytes != 1048576 || tol.MaxLagTransactions == nil || *tol.MaxLagTransactions != 500 {
+		t.Fatalf("unexpected tolerances: %+v", tol)
+	}
+	if _, err := load(base + "    tolerances:\n      maxLagBytes: 100\n"); err == nil || !strings.Contains(err.Error(), "apply per connector") {
+		t.Fatalf("expected per-table maxLagBytes to be rejected, got %v", err)
+	}
+}
+
+func TestCDCConfig_HeartbeatMaxAge(t *testing.T) {
+	var c CDCConfig
+	if got := c.HeartbeatMaxAge(10 * time.Second); got != 5*time.Minute {
+		t.Fatalf("expected the 5m default, got %s", got)
+	}
+	if got := c.HeartbeatMaxAge(10 * time.Minute); got != 30*time.Minute {
+		t.Fatalf("expected three heartbeat intervals, got %s", got)
+	}
+	c.HeartbeatMaxAgeSeconds = 60
+	if got := c.HeartbeatMaxAge(10 * time.Minute); got != time.Minute {
+		t.Fatalf("expected the configured 1m, got %s", got)
+	}
+}
+
+func TestCDCConfig_CountKeys(t *testing.T) {
+	var c CDCConfig
+	if c.CountKeys || c.CountKeysTimeout() != time.Minute || c.CountKeysBudget() != DefaultCountKeysMaxRecords {
+		t.Fatalf("expected key counting off with default bounds, got %+v", c)
+	}
+	c.CountKeysTimeoutSeconds, c.CountKeysMaxRecords = 5, 1000
+	if c.CountKeysTimeout() != 5*time.Second || c.CountKeysBudget() != 1000 {
+		t.Fatalf("expected the configured bounds, got %s and %d", c.CountKeysTimeout(), c.CountKeysBudget())
+	}
+}
+
+func TestLoadConfig_KnownTypes(t *testing.T) {
+	SetKnownTypes("source.type", func() []string { return []string{"mysql", "postgres"} })
+	SetKnownTypes("cdc.type", func() []string { return []string{"debezium"} })
+	defer SetKnownTypes("source.type", nil)
+	defer SetKnownTypes("cdc.type", nil)
+
+	base := "source:\n  type: mysql\n  dsn: u:p@tcp(localhost:3306)/db\n  schema: db\ncdc:\n  type: debezium\n  connect_url: http://localhost:8083\ntables:\n  - name: users\n    primaryKey: [id]\n"
+	c := &Config{}
+	if err := yaml.Unmarshal([]byte(base), c); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.validate(); err != nil {
+		t.Fatalf("expected registered types to load, got: %v", err)
+	}
+	c.Source.Type, c.CDC.Type = "oracle", "maxwell"
+	c.Sink = &SinkConfig{Type: "sqlite", DSN: "file.db", Schema: "main"}
+	err := c.validate()
+	for _, want := range []string{`unsupported source.type "oracle" (registered: mysql, postgres)`, `unsupported cdc.type "maxwell" (registered: debezium)`, `unsupported sink.type "sqlite" (registered: mysql, postgres)`} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q, got: %v", want, err)
+		}
+	}
+}
+
+func TestLoadConfig_Sink(t *testing.T) {
+	base := "source:\n  type: mysql\n  dsn: u:p@tcp(localhost:3306)/db\n  schema: db\ncdc:\n  type: debezium\n  connect_url: http://localhost:8083\ntables:\n  - name: users\n    primaryKey: [id]\n"
+	load := func(yaml string) (*Config, error) {
+		f, err := os.CreateTemp("", "cfg-*.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(f.Name())
+		if _, err := f.WriteString(yaml); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+		return LoadConfig(f.Name())
+	}
+
+	cfg, err := load(base + "sink:\n  type: postgres\n  dsn: postgres://u:p@localhost/replica\n  schema: public\n  tableNameFormat: shop_${table}\n")
+	if err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+	s := cfg.Sink
+	if s.TableName("users") != "shop_users" || s.Chunk() != DefaultChunkSize || s.ChunkPause() != 100*time.Millisecond {
+		t.Fatalf("unexpected sink settings: %s, %d, %s", s.TableName("users"), s.Chunk(), s.ChunkPause())
+	}
+	if src := s.SourceConfig().Source; src.Type != "postgres" || src.Schema != "public" {
+		t.Fatalf("unexpected sink source config: %+v", src)
+	}
+	zero := 0.0
+	s.ChunkPauseSeconds = &zero
+	if s.ChunkPause() != 0 {
+		t.Fatalf("expected an explicit zero pause to disable pausing, got %s", s.ChunkPause())
+	}
+
+	_, err = load(base + "sink:\n  type: mysql\n  tableNameFormat: replica\n  chunkSize: -1\n  chunkPauseSeconds: -1\n")
+	for _, want := range []string{"sink.dsn is required", "sink.schema is required", "must contain ${table}", "sink.chunkSize", "sink.chunkPauseSeconds"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got %v", want, err)
+		}
+	}
+}
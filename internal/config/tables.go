@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// regexPrefix marks a table name as a regular expression, e.g. "re:^audit_\d+$".
+const regexPrefix = "re:"
+
+// IsPattern reports whether the table entry is a glob or regular expression
+// rather than a literal table name.
+func (t TableConfig) IsPattern() bool {
+	return isTablePattern(t.Name)
+}
+
+// Matches reports whether name is selected by this table entry. Literal names
+// and globs are matched as written; regular expressions must match the whole
+// table name.
+func (t TableConfig) Matches(name string) bool {
+	return matchTableName(t.Name, name, t.re)
+}
+
+// TableFor returns the first table entry selecting name, or nil when the
+// table is not configured or is listed in excludeTables.
+func (c *Config) TableFor(name string) *TableConfig {
+	for _, ex := range c.ExcludeTables {
+		if matchTableName(ex, name, c.excludeRegexps[ex]) {
+			return nil
+		}
+	}
+	for i := range c.Tables {
+		if c.Tables[i].Matches(name) {
+			return &c.Tables[i]
+		}
+	}
+	return nil
+}
+
+// IncludesTable reports whether name is in the configured inspection scope.
+func (c *Config) IncludesTable(name string) bool {
+	return c.TableFor(name) != nil
+}
+
+func isTablePattern(pattern string) bool {
+	return strings.HasPrefix(pattern, regexPrefix) || strings.ContainsAny(pattern, "*?[")
+}
+
+// matchTableName matches name against a table pattern. re is the pattern's
+// compiled regular expression when the config has been validated; patterns
+// of configs built without validation are compiled on each call.
+func matchTableName(pattern, name string, re *regexp.Regexp) bool {
+	if expr, ok := strings.CutPrefix(pattern, regexPrefix); ok {
+		if re == nil {
+			var err error
+			if re, err = compileTableRegex(expr); err != nil {
+				return false
+			}
+		}
+		return re.MatchString(name)
+	}
+	if strings.ContainsAny(pattern, "*?[") {
+		ok, err := path.Match(pattern, name)
+		return err == nil && ok
+	}
+	return pattern == name
+}
+
+func compileTableRegex(expr string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + expr + ")$")
+}
+
+// compileTablePattern returns the compiled regular expression of a "re:"
+// pattern, nil for other patterns, and a non-empty message when pattern
+// cannot be compiled.
+func compileTablePattern(field, pattern string) (*regexp.Regexp, string) {
+	if expr, ok := strings.CutPrefix(pattern, regexPrefix); ok {
+		re, err := compileTableRegex(expr)
+		if err != nil {
+			return nil, fmt.Sprintf("%s has invalid regular expression %q: %v", field, expr, err)
+		}
+		return re, ""
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Sprintf("%s has invalid glob pattern %q: %v", field, pattern, err)
+	}
+	return nil, ""
+}
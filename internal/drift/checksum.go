@@ -0,0 +1,454 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// ChecksumReader checksums key ranges of a table in its database and reads
+// the rows of ranges whose checksums differ.
+type ChecksumReader interface {
+	source.RangeReader
+	source.RangeChecksummer
+}
+
+// ChecksumOptions configure CompareChecksums.
+type ChecksumOptions struct {
+	ChunkSize int           // source rows per chunk
+	Pause     time.Duration // wait after each chunk
+	// MaxRowsPerSecond caps the rows read from each database; zero is
+	// unlimited
+	MaxRowsPerSecond float64
+}
+
+// ChecksumResult is the outcome of comparing a source table with its sink
+// table.
+type ChecksumResult struct {
+	Table      string
+	SinkTable  string
+	Chunks     int
+	SourceRows int64
+	SinkRows   int64
+	// MismatchedChunks are the chunks whose checksums differed
+	MismatchedChunks []ChunkChecksum `json:",omitempty"`
+	Issues           []Issue
+}
+
+// ChunkChecksum is a key range and the row count and checksum of each side,
+// as source.RangeChecksum reports them.
+type ChunkChecksum struct {
+	After      []string `json:",omitempty"` // exclusive lower key bound; nil for the first chunk
+	UpTo       []string `json:",omitempty"` // inclusive upper key bound; nil for the last chunk
+	SourceRows int64
+	SinkRows   int64
+	SourceSum  string
+	SinkSum    string
+}
+
+// chunkRow is a normalized row of a chunk.
+type chunkRow struct {
+	key    string // joined canonical key
+	values []string
+	pos    int // position in chunk order, for grouping ranges
+	chunk  int
+	label  string // displayed key
+}
+
+// checksummer compares one table.
+type checksummer struct {
+	table     source.TableInfo
+	sinkTable string
+	keyNames  []string
+	keyCols   []source.ColumnInfo
+	sinkKeys  []string // key column names in the sink table
+	opts      ChecksumOptions
+	// columns are the source columns compared, and srcSums and sinkSums how
+	// each database renders them for checksumming
+	columns  []source.ColumnInfo
+	srcSums  []source.ChecksumColumn
+	sinkSums []source.ChecksumColumn
+	res      *ChecksumResult
+}
+
+// CompareChecksums compares a source table with the table a sink connector
+// writes it to. Each database checksums the table in key ranges of ChunkSize
+// source rows, rendering values in canonical forms of the source column
+// types, so only counts and sums cross the network and MySQL and Postgres
+// sinks compare alike. Once every chunk has been compared, the rows of
+// chunks whose checksums differ are read from both databases and compared
+// by key, narrowing them to the key ranges that differ. Rows replicated
+// between the two reads are therefore not reported.
+func CompareChecksums(ctx context.Context, src, dst ChecksumReader, table source.TableInfo, sinkTable string, opts ChecksumOptions) (*ChecksumResult, error) {
+	if len(table.PrimaryKey) == 0 {
+		return nil, fmt.Errorf("table %s has no primary key to chunk by", table.Name)
+	}
+	if opts.ChunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive")
+	}
+	sinkCols, err := dst.TableColumns(ctx, sinkTable)
+	if err != nil {
+		return nil, fmt.Errorf("reading sink table %s: %w", sinkTable, err)
+	}
+	if len(sinkCols) == 0 {
+		return nil, fmt.Errorf("sink table %s not found", sinkTable)
+	}
+	c := &checksummer{
+		table:     table,
+		sinkTable: sinkTable,
+		keyNames:  table.PrimaryKey,
+		opts:      opts,
+		res:       &ChecksumResult{Table: table.Name, SinkTable: sinkTable},
+	}
+	for _, k := range table.PrimaryKey {
+		col, ok := findColumn(table.Columns, k)
+		if !ok {
+			return nil, fmt.Errorf("primary key column %s is not in table %s", k, table.Name)
+		}
+		sinkCol, ok := findColumn(sinkCols, k)
+		if !ok {
+			return nil, fmt.Errorf("primary key column %s is not in sink table %s", k, sinkTable)
+		}
+		c.keyCols = append(c.keyCols, col)
+		c.sinkKeys = append(c.sinkKeys, sinkCol.Name)
+	}
+	c.matchColumns(sinkCols)
+
+	var after []string
+	for {
+		start := time.Now()
+		upTo, err := src.ChunkBound(ctx, table.Name, c.keyNames, after, opts.ChunkSize)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", table.Name, err)
+		}
+		srcSum, err := src.ChecksumRange(ctx, table.Name, c.keyNames, c.srcSums, after, upTo)
+		if err != nil {
+			return nil, fmt.Errorf("checksumming %s: %w", table.Name, err)
+		}
+		sinkSum, err := dst.ChecksumRange(ctx, sinkTable, c.sinkKeys, c.sinkSums, after, upTo)
+		if err != nil {
+			return nil, fmt.Errorf("checksumming sink table %s: %w", sinkTable, err)
+		}
+		c.res.Chunks++
+		c.res.SourceRows += srcSum.Rows
+		c.res.SinkRows += sinkSum.Rows
+		if srcSum != sinkSum {
+			c.res.MismatchedChunks = append(c.res.MismatchedChunks, ChunkChecksum{
+				After: after, UpTo: upTo,
+				SourceRows: srcSum.Rows, SinkRows: sinkSum.Rows,
+				SourceSum: srcSum.Sum, SinkSum: sinkSum.Sum,
+			})
+		}
+		if err := c.throttle(ctx, max(srcSum.Rows, sinkSum.Rows), time.Since(start)); err != nil {
+			return nil, err
+		}
+		if upTo == nil {
+			break
+		}
+		after = upTo
+	}
+
+	if err := c.narrow(ctx, src, dst); err != nil {
+		return nil, err
+	}
+	return c.res, nil
+}
+
+// matchColumns picks the compared columns: the source columns the sink table
+// also has, matched ignoring case. Source columns missing in the sink are
+// reported; geometries have no canonical form and are not compared.
+func (c *checksummer) matchColumns(sinkCols []source.ColumnInfo) {
+	for _, col := range c.table.Columns {
+		form, scale, ok := checksumForm(col)
+		if !ok {
+			continue
+		}
+		sinkCol, ok := findColumn(sinkCols, col.Name)
+		if !ok {
+			c.res.Issues = append(c.res.Issues, Issue{
+				Severity: SeverityForChange("sink_column_missing"),
+				Table:    c.table.Name,
+				Column:   col.Name,
+				Message:  fmt.Sprintf("%s (sink table %s; not compared)", MessageForChange("sink_column_missing", c.table.Name, col.Name, "", ""), c.sinkTable),
+			})
+			continue
+		}
+		c.columns = append(c.columns, col)
+		c.srcSums = append(c.srcSums, source.ChecksumColumn{Column: col, Form: form, Scale: scale})
+		c.sinkSums = append(c.sinkSums, source.ChecksumColumn{Column: sinkCol, Form: form, Scale: scale})
+	}
+}
+
+// checksumForm returns the canonical form of a source column's values, and
+// its scale for decimals.
+func checksumForm(col source.ColumnInfo) (source.ValueForm, int, bool) {
+	t := valueType(col)
+	switch t.family {
+	case famInt, famBool, famBit, famYear:
+		return source.FormInteger, 0, true
+	case famDecimal:
+		return source.FormDecimal, t.scale, true
+	case famFloat:
+		return source.FormFloat, 0, true
+	case famDate:
+		return source.FormDate, 0, true
+	case famDatetime, famTimestamp:
+		return source.FormDatetime, 0, true
+	case famTime:
+		return source.FormTime, 0, true
+	case famBinary:
+		return source.FormBinary, 0, true
+	case famJSON:
+		return source.FormJSON, 0, true
+	case famGeometry:
+		return "", 0, false
+	}
+	return source.FormText, 0, true
+}
+
+// readSink reads the sink rows in a chunk's key range. The last chunk's range
+// is open, so it is read in pages.
+func (c *checksummer) readSink(ctx context.Context, dst source.RangeReader, after, upTo []string) ([]source.Row, error) {
+	if upTo != nil {
+		rows, err := dst.ReadRange(ctx, c.sinkTable, c.sinkKeys, after, upTo, 0)
+		if err != nil {
+			return nil, fmt.Errorf("reading sink table %s: %w", c.sinkTable, err)
+		}
+		return rows, nil
+	}
+	var out []source.Row
+	for {
+		rows, err := dst.ReadRange(ctx, c.sinkTable, c.sinkKeys, after, nil, c.opts.ChunkSize)
+		if err != nil {
+			return nil, fmt.Errorf("reading sink table %s: %w", c.sinkTable, err)
+		}
+		out = append(out, rows...)
+		if len(rows) < c.opts.ChunkSize {
+			return out, nil
+		}
+		after = rawKey(rows[len(rows)-1], c.sinkKeys)
+	}
+}
+
+// normalize renders rows in canonical form.
+func (c *checksummer) normalize(rows []source.Row, value func(source.ColumnInfo, *string) (string, bool), chunk int) []chunkRow {
+	out := make([]chunkRow, 0, len(rows))
+	for pos, row := range rows {
+		cr := chunkRow{pos: pos, chunk: chunk}
+		get := func(col source.ColumnInfo) *string {
+			if name, ok := rowColumn(row, col.Name); ok {
+				return row[name]
+			}
+			return nil
+		}
+		render := func(col source.ColumnInfo) string {
+			v, ok := value(col, get(col))
+			if !ok {
+				// Values that cannot be normalized compare as they are
+				v = raw(get(col))
+			}
+			return v
+		}
+		key := make([]string, len(c.keyCols))
+		labels := make([]string, len(c.keyCols))
+		for n, col := range c.keyCols {
+			key[n] = render(col)
+			labels[n] = col.Name + "=" + displayValue(col, key[n])
+		}
+		cr.key = strings.Join(key, "\x00")
+		cr.label = strings.Join(labels, ",")
+		for _, col := range c.columns {
+			cr.values = append(cr.values, render(col))
+		}
+		out = append(out, cr)
+	}
+	return out
+}
+
+// throttle waits after a chunk: at least the configured pause, and long
+// enough to keep the rows read per second under the limit.
+func (c *checksummer) throttle(ctx context.Context, rows int64, elapsed time.Duration) error {
+	wait := c.opts.Pause
+	if c.opts.MaxRowsPerSecond > 0 {
+		if need := time.Duration(float64(rows)/c.opts.MaxRowsPerSecond*float64(time.Second)) - elapsed; need > wait {
+			wait = need
+		}
+	}
+	if wait <= 0 {
+		return nil
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// narrow reads the mismatched chunks and compares their rows by key. Rows
+// missing from one chunk and extra in another with the same values are rows
+// the sink sorts differently, e.g. under another collation, and are not
+// reported. A key the sink holds more than once is extra.
+func (c *checksummer) narrow(ctx context.Context, src, dst source.RangeReader) error {
+	missing := map[string]chunkRow{}
+	extra := map[string]chunkRow{}
+	var changed, duplicates []chunkRow
+	changedCols := map[string]bool{}
+	for n, sum := range c.res.MismatchedChunks {
+		start := time.Now()
+		srcRows, err := src.ReadRange(ctx, c.table.Name, c.keyNames, sum.After, sum.UpTo, 0)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", c.table.Name, err)
+		}
+		sinkRows, err := c.readSink(ctx, dst, sum.After, sum.UpTo)
+		if err != nil {
+			return err
+		}
+		sinkByKey := map[string]chunkRow{}
+		for _, r := range c.normalize(sinkRows, sinkValue, n) {
+			if _, dup := sinkByKey[r.key]; dup {
+				duplicates = append(duplicates, r)
+				continue
+			}
+			sinkByKey[r.key] = r
+		}
+		for _, r := range c.normalize(srcRows, sourceValue, n) {
+			s, ok := sinkByKey[r.key]
+			delete(sinkByKey, r.key)
+			if !ok {
+				missing[r.key] = r
+				continue
+			}
+			differs := false
+			for i, col := range c.columns {
+				if s.values[i] != r.values[i] {
+					changedCols[col.Name] = true
+					differs = true
+				}
+			}
+			if differs {
+				changed = append(changed, r)
+			}
+		}
+		for k, r := range sinkByKey {
+			extra[k] = r
+		}
+		if err := c.throttle(ctx, int64(max(len(srcRows), len(sinkRows))), time.Since(start)); err != nil {
+			return err
+		}
+	}
+	for k, m := range missing {
+		if e, ok := extra[k]; ok {
+			delete(missing, k)
+			delete(extra, k)
+			if strings.Join(e.values, "\x1f") != strings.Join(m.values, "\x1f") {
+				changed = append(changed, m)
+			}
+		}
+	}
+
+	var cols []string
+	for col := range changedCols {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	colNote := ""
+	if len(cols) > 0 {
+		colNote = ", columns: " + strings.Join(cols, ", ")
+	}
+	c.report("sink_row_mismatch", changed, colNote)
+	c.report("sink_row_missing", rowsOf(missing), "")
+	c.report("sink_row_extra", append(rowsOf(extra), duplicates...), "")
+	return nil
+}
+
+// report adds an issue for rows that differ, listing the key ranges they
+// fall in: runs of adjacent rows of the same chunk.
+func (c *checksummer) report(kind string, rows []chunkRow, note string) {
+	if len(rows) == 0 {
+		return
+	}
+	sort.Slice(rows, func(a, b int) bool {
+		if rows[a].chunk != rows[b].chunk {
+			return rows[a].chunk < rows[b].chunk
+		}
+		return rows[a].pos < rows[b].pos
+	})
+	var ranges []string
+	for start := 0; start < len(rows); {
+		end := start
+		for end+1 < len(rows) && rows[end+1].chunk == rows[start].chunk && rows[end+1].pos == rows[end].pos+1 {
+			end++
+		}
+		r := rows[start].label
+		if end > start {
+			r = fmt.Sprintf("%s..%s (%d rows)", rows[start].label, rows[end].label, end-start+1)
+		}
+		ranges = append(ranges, r)
+		start = end + 1
+	}
+	shown := strings.Join(ranges[:min(len(ranges), maxEvidence)], "; ")
+	if len(ranges) > maxEvidence {
+		shown += fmt.Sprintf("; %d more ranges", len(ranges)-maxEvidence)
+	}
+	c.res.Issues = append(c.res.Issues, Issue{
+		Severity: SeverityForChange(kind),
+		Table:    c.table.Name,
+		Message:  fmt.Sprintf("%s (rows: %d%s; %s)", MessageForChange(kind, c.table.Name, "", "", ""), len(rows), note, shown),
+	})
+}
+
+func rowsOf(m map[string]chunkRow) []chunkRow {
+	out := make([]chunkRow, 0, len(m))
+	for _, r := range m {
+		out = append(out, r)
+	}
+	return out
+}
+
+// rawKey returns a row's key values as the database rendered them, for use as
+// a range bound in the same database.
+func rawKey(row source.Row, keyNames []string) []string {
+	key := make([]string, len(keyNames))
+	for n, k := range keyNames {
+		if name, ok := rowColumn(row, k); ok {
+			key[n] = raw(row[name])
+		}
+	}
+	return key
+}
+
+func raw(v *string) string {
+	if v == nil {
+		return nullValue
+	}
+	return *v
+}
+
+// rowColumn returns the name of a row's column, ignoring case.
+func rowColumn(row source.Row, name string) (string, bool) {
+	if _, ok := row[name]; ok {
+		return name, true
+	}
+	for c := range row {
+		if strings.EqualFold(c, name) {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+func findColumn(cols []source.ColumnInfo, name string) (source.ColumnInfo, bool) {
+	for _, c := range cols {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+	return source.ColumnInfo{}, false
+}
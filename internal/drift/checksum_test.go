@@ -0,0 +1,251 @@
+package drift
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// fakeRange serves rows keyed by an integer id, counting the rows read.
+type fakeRange struct {
+	cols []source.ColumnInfo
+	rows []source.Row
+	read int
+}
+
+func (f *fakeRange) ReadRange(ctx context.Context, table string, keyColumns []string, after, upTo []string, limit int) ([]source.Row, error) {
+	out := f.inRange(after, upTo, limit)
+	f.read += len(out)
+	return out, nil
+}
+
+func (f *fakeRange) TableColumns(ctx context.Context, table string) ([]source.ColumnInfo, error) {
+	return f.cols, nil
+}
+
+func (f *fakeRange) ChunkBound(ctx context.Context, table string, keyColumns, after []string, n int) ([]string, error) {
+	rows := f.inRange(after, nil, n)
+	if len(rows) < n {
+		return nil, nil
+	}
+	return []string{strconv.Itoa(fakeID(rows[n-1]))}, nil
+}
+
+// ChecksumRange renders values in canonical form much as the databases do:
+// booleans as integers and timestamps in UTC.
+func (f *fakeRange) ChecksumRange(ctx context.Context, table string, keyColumns []string, columns []source.ChecksumColumn, after, upTo []string) (source.RangeChecksum, error) {
+	var res source.RangeChecksum
+	var sum uint64
+	for _, r := range f.inRange(after, upTo, 0) {
+		values := make([]string, len(columns))
+		for n, c := range columns {
+			name, _ := rowColumn(r, c.Column.Name)
+			v := raw(r[name])
+			switch c.Form {
+			case source.FormInteger:
+				v = strings.NewReplacer("t", "1", "f", "0").Replace(v)
+			case source.FormDatetime:
+				for _, layout := range []string{"2006-01-02 15:04:05Z07", "2006-01-02 15:04:05"} {
+					if ts, err := time.Parse(layout, v); err == nil {
+						v = ts.UTC().Format("2006-01-02 15:04:05")
+						break
+					}
+				}
+			}
+			values[n] = v
+		}
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(values, "\x1f")))
+		sum += h.Sum64() >> 4
+		res.Rows++
+	}
+	res.Sum = strconv.FormatUint(sum, 10)
+	return res, nil
+}
+
+func (f *fakeRange) inRange(after, upTo []string, limit int) []source.Row {
+	sorted := append([]source.Row(nil), f.rows...)
+	sort.SliceStable(sorted, func(a, b int) bool { return fakeID(sorted[a]) < fakeID(sorted[b]) })
+	var out []source.Row
+	for _, r := range sorted {
+		if after != nil {
+			if n, _ := strconv.Atoi(after[0]); fakeID(r) <= n {
+				continue
+			}
+		}
+		if upTo != nil {
+			if n, _ := strconv.Atoi(upTo[0]); fakeID(r) > n {
+				continue
+			}
+		}
+		if limit > 0 && len(out) == limit {
+			break
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func fakeID(r source.Row) int {
+	for c, v := range r {
+		if strings.EqualFold(c, "id") {
+			n, _ := strconv.Atoi(*v)
+			return n
+		}
+	}
+	return 0
+}
+
+var checksumTable = source.TableInfo{
+	Name: "orders",
+	Columns: []source.ColumnInfo{
+		{Name: "id", Type: "int"},
+		{Name: "paid", Type: "tinyint(1)"},
+		{Name: "created", Type: "timestamp"},
+		{Name: "note", Type: "varchar(20)"},
+	},
+	PrimaryKey: []string{"id"},
+}
+
+// checksumSinkColumns are the columns of the orders table in a Postgres sink.
+var checksumSinkColumns = []source.ColumnInfo{
+	{Name: "ID", Type: "integer"},
+	{Name: "paid", Type: "boolean"},
+	{Name: "created", Type: "timestamp with time zone"},
+	{Name: "note", Type: "text"},
+}
+
+func checksumRowOf(id int, paid, created, note string) source.Row {
+	s := strconv.Itoa(id)
+	return source.Row{"id": &s, "paid": &paid, "created": &created, "note": &note}
+}
+
+// sinkRowOf renders a row as a Postgres sink would: lower case columns,
+// booleans as t and f and timestamps with an offset.
+func sinkRowOf(id int, paid, created, note string) source.Row {
+	s := strconv.Itoa(id)
+	if paid == "1" {
+		paid = "t"
+	} else {
+		paid = "f"
+	}
+	created = strings.Replace(created, " 12:", " 14:", 1) + "+02"
+	return source.Row{"ID": &s, "paid": &paid, "created": &created, "note": &note}
+}
+
+func TestCompareChecksums(t *testing.T) {
+	src, dst := &fakeRange{cols: checksumTable.Columns}, &fakeRange{cols: checksumSinkColumns}
+	for id := 1; id <= 25; id++ {
+		src.rows = append(src.rows, checksumRowOf(id, "1", "2024-03-01 12:30:00", "n"+strconv.Itoa(id)))
+		switch {
+		case id == 7:
+			dst.rows = append(dst.rows, sinkRowOf(id, "0", "2024-03-01 12:30:00", "n7"))
+		case id >= 12 && id <= 14:
+			// not replicated
+		default:
+			dst.rows = append(dst.rows, sinkRowOf(id, "1", "2024-03-01 12:30:00", "n"+strconv.Itoa(id)))
+		}
+	}
+	dst.rows = append(dst.rows, sinkRowOf(40, "1", "2024-03-01 12:30:00", "n40"))
+
+	res, err := CompareChecksums(context.Background(), src, dst, checksumTable, "public.orders", ChecksumOptions{ChunkSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Chunks != 3 || res.SourceRows != 25 || res.SinkRows != 23 {
+		t.Errorf("expected 3 chunks of 25 source and 23 sink rows, got %+v", res)
+	}
+	if len(res.MismatchedChunks) != 3 {
+		t.Errorf("expected every chunk to mismatch, got %+v", res.MismatchedChunks)
+	}
+
+	var mismatch, missing, extra *Issue
+	for n, iss := range res.Issues {
+		switch {
+		case strings.Contains(iss.Message, MessageForChange("sink_row_mismatch", "", "", "", "")):
+			mismatch = &res.Issues[n]
+		case strings.Contains(iss.Message, MessageForChange("sink_row_missing", "", "", "", "")):
+			missing = &res.Issues[n]
+		case strings.Contains(iss.Message, MessageForChange("sink_row_extra", "", "", "", "")):
+			extra = &res.Issues[n]
+		}
+	}
+	if mismatch == nil || mismatch.Severity != SeverityBlock || !strings.Contains(mismatch.Message, "rows: 1, columns: paid; id=7") {
+		t.Errorf("expected id=7 to differ in paid, got %+v", mismatch)
+	}
+	if missing == nil || missing.Severity != SeverityBlock || !strings.Contains(missing.Message, "id=12..id=14 (3 rows)") {
+		t.Errorf("expected the range id=12..id=14 missing in the sink, got %+v", missing)
+	}
+	if extra == nil || extra.Severity != SeverityWarn || !strings.Contains(extra.Message, "id=40") {
+		t.Errorf("expected id=40 extra in the sink, got %+v", extra)
+	}
+	if len(res.Issues) != 3 {
+		t.Errorf("expected 3 issues, got %+v", res.Issues)
+	}
+}
+
+func TestCompareChecksumsMatching(t *testing.T) {
+	src, dst := &fakeRange{cols: checksumTable.Columns}, &fakeRange{cols: checksumSinkColumns[:3]}
+	for id := 1; id <= 20; id++ {
+		src.rows = append(src.rows, checksumRowOf(id, "0", "2024-03-01 12:30:00", "n"))
+		// The sink lacks the note column and stores rows in another order
+		row := sinkRowOf(21-id, "0", "2024-03-01 12:30:00", "n")
+		delete(row, "note")
+		dst.rows = append([]source.Row{row}, dst.rows...)
+	}
+	res, err := CompareChecksums(context.Background(), src, dst, checksumTable, "orders", ChecksumOptions{ChunkSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.MismatchedChunks) != 0 || len(res.Issues) != 1 ||
+		res.Issues[0].Column != "note" || res.Issues[0].Severity != SeverityWarn {
+		t.Errorf("expected matching chunks and only the missing note column reported, got %+v", res)
+	}
+	// Two full chunks and an empty last one; matching chunks are not read
+	if res.Chunks != 3 || src.read != 0 || dst.read != 0 {
+		t.Errorf("expected each row read once, got %d chunks, %d source and %d sink rows read", res.Chunks, src.read, dst.read)
+	}
+
+	noKey := checksumTable
+	noKey.PrimaryKey = nil
+	if _, err := CompareChecksums(context.Background(), src, dst, noKey, "orders", ChecksumOptions{ChunkSize: 10}); err == nil {
+		t.Error("expected an error for a table without a key")
+	}
+}
+
+func TestCompareChecksumsDuplicateRows(t *testing.T) {
+	src, dst := &fakeRange{cols: checksumTable.Columns}, &fakeRange{cols: checksumSinkColumns}
+	for id := 1; id <= 5; id++ {
+		src.rows = append(src.rows, checksumRowOf(id, "1", "2024-03-01 12:30:00", "n"))
+		switch id {
+		case 3:
+			// A sink without a primary key holding id 3 twice and lacking id 4
+			// has as many rows as the source
+			dst.rows = append(dst.rows, sinkRowOf(id, "1", "2024-03-01 12:30:00", "n"), sinkRowOf(id, "1", "2024-03-01 12:30:00", "n"))
+		case 4:
+		default:
+			dst.rows = append(dst.rows, sinkRowOf(id, "1", "2024-03-01 12:30:00", "n"))
+		}
+	}
+	res, err := CompareChecksums(context.Background(), src, dst, checksumTable, "orders", ChecksumOptions{ChunkSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.MismatchedChunks) != 1 || res.SourceRows != res.SinkRows {
+		t.Fatalf("expected the chunk with a duplicate row to mismatch, got %+v", res)
+	}
+	var missing, extra bool
+	for _, iss := range res.Issues {
+		missing = missing || strings.Contains(iss.Message, MessageForChange("sink_row_missing", "", "", "", "")) && strings.HasSuffix(iss.Message, "id=4)")
+		extra = extra || strings.Contains(iss.Message, MessageForChange("sink_row_extra", "", "", "", "")) && strings.HasSuffix(iss.Message, "id=3)")
+	}
+	if !missing || !extra || len(res.Issues) != 2 {
+		t.Errorf("expected id=4 missing and id=3 extra, got %+v", res.Issues)
+	}
+}
@@ -0,0 +1,152 @@
+package drift
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// checkColumnPolicies reports the columns the connector deliberately
+// excludes or rewrites. They are expected differences, so the schema
+// comparison does not also report them as drift.
+func checkColumnPolicies(table string, policies map[string]cdc.ColumnPolicy) []Issue {
+	columns := make([]string, 0, len(policies))
+	for c := range policies {
+		columns = append(columns, c)
+	}
+	sort.Strings(columns)
+	var issues []Issue
+	for _, c := range columns {
+		issues = append(issues, Issue{
+			Severity: SeverityForChange("column_filtered"),
+			Table:    table,
+			Column:   c,
+			Message:  fmt.Sprintf("%s.%s %s (%s)", table, c, MessageForChange("column_filtered", table, c, "", ""), policies[c]),
+		})
+	}
+	return issues
+}
+
+// expectedAbsence returns the change kind explaining why a source column is
+// legitimately missing from the CDC schema, or "" if its absence is drift.
+// MySQL does not write virtual generated columns to the binlog, so Debezium
+// never emits them; stored generated columns are written like any other.
+// Invisible columns are hidden from SELECT *, so snapshots and schemas built
+// from it omit them.
+func expectedAbsence(col source.ColumnInfo) string {
+	switch {
+	case col.Generated == "virtual":
+		return "column_virtual_generated"
+	case col.Invisible:
+		return "column_invisible"
+	}
+	return ""
+}
+
+// checkEventAbsences reports the source columns missing from the fields of
+// a table's change events that are expected to be absent from them. Columns
+// the schema history lacks too are reported as added by the schema
+// comparison instead.
+func checkEventAbsences(table source.TableInfo, fields map[string]cdc.FieldSchema, history cdc.TableSchema, policies map[string]cdc.ColumnPolicy) []Issue {
+	if len(fields) == 0 {
+		return nil
+	}
+	var issues []Issue
+	for _, col := range table.Columns {
+		if _, ok := fields[col.Name]; ok || policies[col.Name] == cdc.ColumnExcluded {
+			continue
+		}
+		if _, ok := history.Columns[col.Name]; history.Columns != nil && !ok {
+			continue
+		}
+		if kind := expectedAbsence(col); kind != "" {
+			issues = append(issues, Issue{
+				Severity: SeverityForChange(kind),
+				Table:    table.Name,
+				Column:   col.Name,
+				Message:  fmt.Sprintf("%s.%s %s", table.Name, col.Name, MessageForChange(kind, table.Name, col.Name, "", "")),
+			})
+		}
+	}
+	return issues
+}
+
+// comparesType reports whether the CDC type of a column under policy is
+// expected to match the source type. Masked and hashed columns are emitted
+// as strings whatever their source type.
+func comparesType(policy cdc.ColumnPolicy) bool {
+	return policy != cdc.ColumnMasked && policy != cdc.ColumnHashed
+}
+
+// checkColumnAttributes compares the column attributes other than type and
+// nullability that both sides report: character set, default value and
+// ordinal position. Attributes either side does not know are skipped. moved
+// reports whether the column's place among the columns both sides have
+// differs, so a column added or dropped on one side does not shift the
+// others.
+func checkColumnAttributes(table, column string, mcol source.ColumnInfo, ccol cdc.ColumnInfo, moved bool) []Issue {
+	var issues []Issue
+	add := func(kind, from, to string) {
+		issues = append(issues, Issue{
+			Severity: SeverityForChange(kind),
+			Table:    table,
+			Column:   column,
+			Message:  fmt.Sprintf("%s.%s %s (%s -> %s)", table, column, MessageForChange(kind, table, column, from, to), from, to),
+		})
+	}
+	if mcol.Charset != "" && ccol.Charset != "" && normalizeCharset(mcol.Charset) != normalizeCharset(ccol.Charset) {
+		add("charset_changed", mcol.Charset, ccol.Charset)
+	}
+	if mcol.Default != nil && ccol.Default != nil && normalizeDefault(*mcol.Default) != normalizeDefault(*ccol.Default) {
+		add("default_changed", *mcol.Default, *ccol.Default)
+	}
+	if moved && mcol.Position > 0 && ccol.Position > 0 {
+		add("column_reordered", fmt.Sprintf("position %d", mcol.Position), fmt.Sprintf("position %d", ccol.Position))
+	}
+	return issues
+}
+
+// sharedPositions ranks the columns both sides have, by ordinal position on
+// each side. Columns either side has no position for are not ranked.
+func sharedPositions(mysqlCols map[string]source.ColumnInfo, cdcCols map[string]cdc.ColumnInfo) (mysqlRank, cdcRank map[string]int) {
+	var shared []string
+	for name, mcol := range mysqlCols {
+		if ccol, ok := cdcCols[name]; ok && mcol.Position > 0 && ccol.Position > 0 {
+			shared = append(shared, name)
+		}
+	}
+	rank := func(pos func(string) int) map[string]int {
+		sort.Slice(shared, func(a, b int) bool { return pos(shared[a]) < pos(shared[b]) })
+		out := make(map[string]int, len(shared))
+		for n, name := range shared {
+			out[name] = n + 1
+		}
+		return out
+	}
+	mysqlRank = rank(func(name string) int { return mysqlCols[name].Position })
+	cdcRank = rank(func(name string) int { return cdcCols[name].Position })
+	return mysqlRank, cdcRank
+}
+
+// normalizeCharset folds case and the utf8/utf8mb3 alias.
+func normalizeCharset(cs string) string {
+	cs = strings.ToLower(strings.TrimSpace(cs))
+	if cs == "utf8" {
+		return "utf8mb3"
+	}
+	return cs
+}
+
+// normalizeDefault strips the quoting and call parentheses that differ
+// between information_schema and DDL, e.g. "'0.00'" and "0.00", or
+// "CURRENT_TIMESTAMP" and "current_timestamp()".
+func normalizeDefault(def string) string {
+	def = strings.TrimSpace(def)
+	if len(def) >= 2 && def[0] == '\'' && def[len(def)-1] == '\'' {
+		def = def[1 : len(def)-1]
+	}
+	return strings.ToLower(strings.TrimSuffix(def, "()"))
+}
@@ -0,0 +1,153 @@
+package drift
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+func TestColumnPoliciesAreNotDrift(t *testing.T) {
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "users", PrimaryKey: []string{"id"}, Columns: []source.ColumnInfo{
+		{Name: "id", Type: "int"},
+		{Name: "ssn", Type: "char(11)"},
+		{Name: "email", Type: "varchar(255)"},
+		{Name: "phone", Type: "varchar(20)", Nullable: true},
+	}}}}
+	cdcRes := &cdc.Result{
+		CapturedTables: []string{"users"},
+		TableSchemas: map[string]cdc.TableSchema{"users": {Columns: map[string]cdc.ColumnInfo{
+			"id":     {Type: "int"},
+			"email":  {Type: "string"}, // masked, so emitted as a string
+			"legacy": {Type: "int"},
+		}}},
+		ColumnPolicies: map[string]map[string]cdc.ColumnPolicy{"users": {"ssn": cdc.ColumnExcluded, "email": cdc.ColumnMasked}},
+	}
+	rep := Validate(mysql, cdcRes)
+
+	filtered := map[string]bool{}
+	var addedPhone, removedLegacy bool
+	for _, iss := range rep.Issues {
+		switch {
+		case iss.Severity == SeverityInfo && strings.Contains(iss.Message, MessageForChange("column_filtered", "", "", "", "")):
+			filtered[iss.Column] = true
+		case iss.Column == "ssn" || iss.Column == "email":
+			t.Fatalf("expected no drift for filtered column %s, got %+v", iss.Column, iss)
+		case iss.Column == "phone" && iss.Severity == SeverityForChange("column_added"):
+			addedPhone = true
+		case iss.Column == "legacy" && iss.Severity == SeverityForChange("column_removed"):
+			removedLegacy = true
+		}
+	}
+	if !filtered["ssn"] || !filtered["email"] {
+		t.Fatalf("expected column_filtered issues for ssn and email, got %v", rep.Issues)
+	}
+	// Columns missing without a policy are still reported
+	if !addedPhone || !removedLegacy {
+		t.Fatalf("expected unfiltered column differences to be reported, got %v", rep.Issues)
+	}
+}
+
+func TestColumnAttributeDrift(t *testing.T) {
+	latin1, quoted, plain := "latin1", "'0.00'", "0.00"
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "orders", PrimaryKey: []string{"id"}, Columns: []source.ColumnInfo{
+		{Name: "id", Type: "int", Position: 1},
+		{Name: "note", Type: "varchar(64)", Charset: "utf8mb4", Position: 2},
+		{Name: "total", Type: "decimal(10,2)", Default: &quoted, Position: 3},
+	}}}}
+	cdcRes := &cdc.Result{
+		CapturedTables: []string{"orders"},
+		TableSchemas: map[string]cdc.TableSchema{"orders": {PrimaryKey: []string{"id"}, Columns: map[string]cdc.ColumnInfo{
+			"id":    {Type: "INT", Position: 1},
+			"note":  {Type: "VARCHAR", Length: intPtr(64), Charset: latin1, Position: 3},
+			"total": {Type: "DECIMAL", Length: intPtr(10), Scale: intPtr(2), Default: &plain, Position: 2},
+		}}},
+	}
+	rep := Validate(mysql, cdcRes)
+	kinds := map[string]bool{}
+	for _, iss := range rep.Issues {
+		for _, kind := range []string{"charset_changed", "default_changed", "column_reordered"} {
+			if strings.Contains(iss.Message, MessageForChange(kind, "", "", "", "")) {
+				kinds[iss.Column+" "+kind] = true
+			}
+		}
+	}
+	want := map[string]bool{"note charset_changed": true, "note column_reordered": true, "total column_reordered": true}
+	if len(kinds) != len(want) {
+		t.Fatalf("expected %v, got %v (issues %v)", want, kinds, rep.Issues)
+	}
+	for k := range want {
+		if !kinds[k] {
+			t.Fatalf("expected %s, got %v", k, kinds)
+		}
+	}
+}
+
+func TestGeneratedAndInvisibleColumns(t *testing.T) {
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "orders", PrimaryKey: []string{"id"}, Columns: []source.ColumnInfo{
+		{Name: "id", Type: "int"},
+		{Name: "total_cents", Type: "bigint", Generated: "virtual", GenerationExpression: "(`total` * 100)"},
+		{Name: "search_key", Type: "varchar(64)", Generated: "stored", GenerationExpression: "lower(`note`)"},
+		{Name: "row_hash", Type: "char(32)", Invisible: true},
+		{Name: "tax_cents", Type: "bigint", Generated: "virtual", GenerationExpression: "(`tax` * 100)"},
+	}}}}
+	// The schema history is built from DDL, so it has the virtual and
+	// invisible columns; change events do not
+	cdcRes := &cdc.Result{
+		CapturedTables: []string{"orders"},
+		TableSchemas: map[string]cdc.TableSchema{"orders": {PrimaryKey: []string{"id"}, Columns: map[string]cdc.ColumnInfo{
+			"id":          {Type: "INT"},
+			"total_cents": {Type: "BIGINT"},
+			"row_hash":    {Type: "CHAR", Length: intPtr(32)},
+		}}},
+		EventFields: map[string]map[string]cdc.FieldSchema{"orders": {"id": {Type: "int32"}}},
+	}
+	rep := Validate(mysql, cdcRes)
+	got := map[string]string{}
+	for _, iss := range rep.Issues {
+		if iss.Column != "" {
+			got[iss.Column] = iss.Message
+		}
+	}
+	if !strings.Contains(got["total_cents"], MessageForChange("column_virtual_generated", "", "", "", "")) {
+		t.Fatalf("expected the virtual column to be expected-absent from events, got %q", got["total_cents"])
+	}
+	if !strings.Contains(got["row_hash"], MessageForChange("column_invisible", "", "", "", "")) {
+		t.Fatalf("expected the invisible column to be expected-absent from events, got %q", got["row_hash"])
+	}
+	// Stored generated columns are in the binlog, and the schema history has
+	// virtual ones, so their absence from it is drift
+	for _, c := range []string{"search_key", "tax_cents"} {
+		if got[c] != MessageForChange("column_added", "", "", "", "") {
+			t.Fatalf("expected %s to be reported as added, got %q", c, got[c])
+		}
+	}
+}
+
+func TestColumnOrderIgnoresColumnsOnOneSide(t *testing.T) {
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "orders", PrimaryKey: []string{"id"}, Columns: []source.ColumnInfo{
+		{Name: "id", Type: "int", Position: 1},
+		{Name: "added", Type: "int", Position: 2},
+		{Name: "note", Type: "int", Position: 3},
+		{Name: "total", Type: "int", Position: 4},
+	}}}}
+	cdcRes := &cdc.Result{
+		CapturedTables: []string{"orders"},
+		TableSchemas: map[string]cdc.TableSchema{"orders": {PrimaryKey: []string{"id"}, Columns: map[string]cdc.ColumnInfo{
+			"id":    {Type: "INT", Position: 1},
+			"total": {Type: "INT", Position: 2},
+			"note":  {Type: "INT", Position: 3},
+		}}},
+	}
+	reordered := map[string]bool{}
+	for _, iss := range Validate(mysql, cdcRes).Issues {
+		if strings.Contains(iss.Message, MessageForChange("column_reordered", "", "", "", "")) {
+			reordered[iss.Column] = true
+		}
+	}
+	// added shifts every later column, but only note and total swapped places
+	if len(reordered) != 2 || !reordered["note"] || !reordered["total"] {
+		t.Fatalf("expected note and total to be reordered, got %v", reordered)
+	}
+}
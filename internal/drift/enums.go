@@ -0,0 +1,78 @@
+package drift
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// checkEnumValues compares the permitted values of an ENUM or SET column.
+// Debezium serializes the value list into the event schema and MySQL stores
+// enums by ordinal, so only values appended to the end of the list are safe:
+// consumers holding the CDC schema simply do not know them yet. Values
+// inserted or moved within the list change what existing ordinals mean, and
+// removed values can no longer be written at all. The values consumers see are those of field, the column in
+// the newest change event's schema; without one the schema history's are
+// compared.
+func checkEnumValues(table, column string, mcol source.ColumnInfo, ccol cdc.ColumnInfo, field cdc.FieldSchema) []Issue {
+	cdcValues := eventEnumValues(field)
+	if cdcValues == nil {
+		cdcValues = ccol.EnumValues
+	}
+	if len(mcol.EnumValues) == 0 || len(cdcValues) == 0 {
+		return nil
+	}
+	kind, values := classifyEnumValues(cdcValues, mcol.EnumValues)
+	if kind == "" {
+		return nil
+	}
+	return []Issue{{
+		Severity: SeverityForChange(kind),
+		Table:    table,
+		Column:   column,
+		Message:  fmt.Sprintf("%s.%s %s (%s)", table, column, MessageForChange(kind, table, column, "", ""), strings.Join(values, ", ")),
+	}}
+}
+
+// classifyEnumValues compares the CDC value list with the source one and
+// returns the change kind with the values involved, or "" if they match.
+func classifyEnumValues(cdcValues, sourceValues []string) (string, []string) {
+	inSource := map[string]bool{}
+	for _, v := range sourceValues {
+		inSource[v] = true
+	}
+	var removed []string
+	for _, v := range cdcValues {
+		if !inSource[v] {
+			removed = append(removed, v)
+		}
+	}
+	if len(removed) > 0 {
+		return "enum_values_removed", removed
+	}
+	for i, v := range cdcValues {
+		if i >= len(sourceValues) || sourceValues[i] != v {
+			return "enum_values_reordered", sourceValues
+		}
+	}
+	if len(sourceValues) > len(cdcValues) {
+		return "enum_values_added", sourceValues[len(cdcValues):]
+	}
+	return "", nil
+}
+
+// eventEnumValues returns the values the event schema of an ENUM or SET field
+// allows, which Debezium lists comma-separated in its allowed parameter, or
+// nil for other fields.
+func eventEnumValues(f cdc.FieldSchema) []string {
+	if f.Name != "io.debezium.data.Enum" && f.Name != "io.debezium.data.EnumSet" {
+		return nil
+	}
+	allowed, ok := f.Parameters["allowed"]
+	if !ok {
+		return nil
+	}
+	return strings.Split(allowed, ",")
+}
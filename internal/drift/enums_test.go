@@ -0,0 +1,58 @@
+package drift
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+func TestClassifyEnumValues(t *testing.T) {
+	cdcValues := []string{"new", "paid", "shipped"}
+	cases := []struct {
+		source     []string
+		wantKind   string
+		wantValues []string
+	}{
+		{[]string{"new", "paid", "shipped"}, "", nil},
+		{[]string{"new", "paid", "shipped", "refunded"}, "enum_values_added", []string{"refunded"}},
+		{[]string{"new", "shipped"}, "enum_values_removed", []string{"paid"}},
+		{[]string{"new", "refunded", "paid", "shipped"}, "enum_values_reordered", []string{"new", "refunded", "paid", "shipped"}},
+		{[]string{"paid", "new", "shipped"}, "enum_values_reordered", []string{"paid", "new", "shipped"}},
+	}
+	for _, c := range cases {
+		kind, values := classifyEnumValues(cdcValues, c.source)
+		if kind != c.wantKind || !reflect.DeepEqual(values, c.wantValues) {
+			t.Errorf("%v: got %s %v, want %s %v", c.source, kind, values, c.wantKind, c.wantValues)
+		}
+	}
+}
+
+func TestEnumChangeSeverities(t *testing.T) {
+	want := map[string]string{
+		"enum_values_added":     SeverityInfo,
+		"enum_values_reordered": SeverityWarn,
+		"enum_values_removed":   SeverityBlock,
+	}
+	for kind, severity := range want {
+		if got := SeverityForChange(kind); got != severity {
+			t.Errorf("%s: got %s, want %s", kind, got, severity)
+		}
+	}
+}
+
+func TestCheckEnumValuesPrefersEventSchema(t *testing.T) {
+	mcol := source.ColumnInfo{Type: "enum('new','paid','shipped')", EnumValues: []string{"new", "paid", "shipped"}}
+	history := cdc.ColumnInfo{Type: "ENUM", EnumValues: []string{"new", "paid", "shipped"}}
+	// Events are still written with the list before shipped was appended
+	field := cdc.FieldSchema{Type: "string", Name: "io.debezium.data.Enum", Parameters: map[string]string{"allowed": "new,paid"}}
+	issues := checkEnumValues("orders", "status", mcol, history, field)
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, MessageForChange("enum_values_added", "", "", "", "")+" (shipped)") {
+		t.Fatalf("expected shipped to be missing from the event schema, got %v", issues)
+	}
+	if issues := checkEnumValues("orders", "status", mcol, history, cdc.FieldSchema{Type: "string"}); len(issues) != 0 {
+		t.Fatalf("expected the schema history to match without an event schema, got %v", issues)
+	}
+}
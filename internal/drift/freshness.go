@@ -0,0 +1,55 @@
+package drift
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+)
+
+// checkFreshness reports a table whose newest change event is older than
+// maxLag. The table is only idle if the connector is known to be running:
+// its heartbeats are current, or its committed offset is caught up with the
+// source. A connector whose heartbeats are older than the configured
+// heartbeat age has stalled. Disabled heartbeats are reported once per
+// connector by checkHeartbeat.
+func checkFreshness(table string, cdcResult *cdc.Result, maxLag float64, cdcCfg config.CDCConfig, now time.Time) []Issue {
+	last, ok := cdcResult.LastEventTimes[table]
+	if !ok {
+		return nil
+	}
+	limit := time.Duration(maxLag * float64(time.Second))
+	age := now.Sub(last)
+	if age <= limit {
+		return nil
+	}
+	issue := func(kind, detail string) []Issue {
+		return []Issue{{
+			Severity: SeverityForChange(kind),
+			Table:    table,
+			Message:  fmt.Sprintf("%s (last event %s ago; %s)", MessageForChange(kind, table, "", "", ""), age.Round(time.Second), detail),
+		}}
+	}
+
+	hb := cdcResult.Heartbeat
+	if hb != nil && !hb.Read {
+		hb = nil
+	}
+	var maxAge time.Duration
+	if hb != nil {
+		maxAge = cdcCfg.HeartbeatMaxAge(time.Duration(hb.IntervalMs) * time.Millisecond)
+	}
+	switch {
+	case hb != nil && hb.Last.IsZero():
+		return issue("cdc_stalled", fmt.Sprintf("no heartbeat in %s", hb.Topic))
+	case hb != nil && now.Sub(hb.Last) > maxAge:
+		return issue("cdc_stalled", fmt.Sprintf("last heartbeat %s ago > %s", now.Sub(hb.Last).Round(time.Second), maxAge))
+	case hb != nil:
+		return issue("table_idle", fmt.Sprintf("last heartbeat %s ago", now.Sub(hb.Last).Round(time.Second)))
+	}
+	if l := cdcResult.Lag; l != nil && l.Seconds != nil && *l.Seconds <= maxLag {
+		return issue("table_idle", fmt.Sprintf("connector offset %.0fs behind the source", *l.Seconds))
+	}
+	return issue("table_idle", "connector progress unknown")
+}
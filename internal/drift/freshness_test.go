@@ -0,0 +1,51 @@
+package drift
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+)
+
+func TestCheckFreshness(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	stale := map[string]time.Time{"orders": now.Add(-time.Hour)}
+	caughtUp := 0.0
+
+	cases := []struct {
+		name string
+		res  *cdc.Result
+		kind string // empty when no issue is expected
+	}{
+		{"recent event", &cdc.Result{LastEventTimes: map[string]time.Time{"orders": now.Add(-10 * time.Second)}}, ""},
+		{"fresh heartbeat", &cdc.Result{LastEventTimes: stale, Heartbeat: &cdc.Heartbeat{IntervalMs: 10000, Topic: "hb", Read: true, Last: now.Add(-5 * time.Second)}}, "table_idle"},
+		// Heartbeats are judged by their own age limit, five minutes by
+		// default, not by the table's
+		{"heartbeat within its age limit", &cdc.Result{LastEventTimes: stale, Heartbeat: &cdc.Heartbeat{IntervalMs: 10000, Topic: "hb", Read: true, Last: now.Add(-2 * time.Minute)}}, "table_idle"},
+		{"stale heartbeat", &cdc.Result{LastEventTimes: stale, Heartbeat: &cdc.Heartbeat{IntervalMs: 10000, Topic: "hb", Read: true, Last: now.Add(-30 * time.Minute)}}, "cdc_stalled"},
+		{"empty heartbeat topic", &cdc.Result{LastEventTimes: stale, Heartbeat: &cdc.Heartbeat{IntervalMs: 10000, Topic: "hb", Read: true}}, "cdc_stalled"},
+		{"offset caught up", &cdc.Result{LastEventTimes: stale, Lag: &cdc.ReplicationLag{Seconds: &caughtUp}}, "table_idle"},
+		{"no liveness signal", &cdc.Result{LastEventTimes: stale}, "table_idle"},
+		{"heartbeats disabled", &cdc.Result{LastEventTimes: stale, Heartbeat: &cdc.Heartbeat{}}, "table_idle"},
+	}
+	for _, tc := range cases {
+		issues := checkFreshness("orders", tc.res, 60, config.CDCConfig{}, now)
+		if tc.kind == "" {
+			if len(issues) != 0 {
+				t.Errorf("%s: expected no issues, got %v", tc.name, issues)
+			}
+			continue
+		}
+		if len(issues) != 1 || issues[0].Severity != SeverityForChange(tc.kind) || !strings.HasPrefix(issues[0].Message, MessageForChange(tc.kind, "", "", "", "")+" (last event 1h0m0s ago") {
+			t.Errorf("%s: expected %s, got %v", tc.name, tc.kind, issues)
+		}
+	}
+
+	strict := config.CDCConfig{HeartbeatMaxAgeSeconds: 60}
+	res := &cdc.Result{LastEventTimes: stale, Heartbeat: &cdc.Heartbeat{IntervalMs: 10000, Topic: "hb", Read: true, Last: now.Add(-2 * time.Minute)}}
+	if issues := checkFreshness("orders", res, 60, strict, now); len(issues) != 1 || issues[0].Severity != SeverityForChange("cdc_stalled") {
+		t.Errorf("expected heartbeatMaxAgeSeconds to apply, got %v", issues)
+	}
+}
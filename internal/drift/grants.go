@@ -0,0 +1,67 @@
+package drift
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// requiredGlobalPrivileges are the privileges Debezium's MySQL connector
+// needs ON *.* to take consistent snapshots and read the binlog.
+var requiredGlobalPrivileges = []string{"RELOAD", "SHOW DATABASES", "REPLICATION SLAVE", "REPLICATION CLIENT"}
+
+// checkGrants reports the privileges the connector's database user lacks:
+// the required global privileges, and SELECT on each captured table when it
+// is not granted globally or on the whole schema. When the grants are
+// uncertain, e.g. the account's roles cannot be read, gaps are only warned
+// of.
+func checkGrants(grants *source.Grants, tables []string) []Issue {
+	has := func(privs []string, want string) bool {
+		for _, p := range privs {
+			if p == want || p == "ALL PRIVILEGES" {
+				return true
+			}
+		}
+		return false
+	}
+	user, note := grants.User, ""
+	if grants.Host != "" {
+		user += "@" + grants.Host
+	}
+	if grants.Uncertain != "" {
+		note = "; " + grants.Uncertain
+	}
+	severity := func(kind string) string {
+		if grants.Uncertain != "" {
+			return SeverityWarn
+		}
+		return SeverityForChange(kind)
+	}
+	var issues []Issue
+	var missing []string
+	for _, p := range requiredGlobalPrivileges {
+		if !has(grants.Global, p) {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) > 0 {
+		issues = append(issues, Issue{
+			Severity: severity("grant_missing"),
+			Message:  fmt.Sprintf("%s: user %s lacks %s ON *.*%s", MessageForChange("grant_missing", "", "", "", ""), user, strings.Join(missing, ", "), note),
+		})
+	}
+	if has(grants.Global, "SELECT") || has(grants.Schema, "SELECT") {
+		return issues
+	}
+	for _, t := range tables {
+		if !has(grants.Tables[t], "SELECT") {
+			issues = append(issues, Issue{
+				Severity: severity("table_select_missing"),
+				Table:    t,
+				Message:  fmt.Sprintf("%s (user %s%s)", MessageForChange("table_select_missing", t, "", "", ""), user, note),
+			})
+		}
+	}
+	return issues
+}
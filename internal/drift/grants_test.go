@@ -0,0 +1,54 @@
+package drift
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+func TestCheckGrants(t *testing.T) {
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{
+		{Name: "users", PrimaryKey: []string{"id"}},
+		{Name: "orders", PrimaryKey: []string{"id"}},
+	}}
+	cdcRes := &cdc.Result{CapturedTables: []string{"users", "orders"}}
+
+	grants := &source.Grants{
+		User:   "debezium",
+		Global: []string{"RELOAD", "REPLICATION SLAVE"},
+		Tables: map[string][]string{"users": {"SELECT"}},
+	}
+	rep := ValidateWithOptions(mysql, cdcRes, Options{Grants: grants})
+	var global, tables []Issue
+	for _, iss := range rep.Issues {
+		switch {
+		case strings.HasPrefix(iss.Message, MessageForChange("grant_missing", "", "", "", "")):
+			global = append(global, iss)
+		case strings.HasPrefix(iss.Message, MessageForChange("table_select_missing", "", "", "", "")):
+			tables = append(tables, iss)
+		}
+	}
+	if len(global) != 1 || !strings.Contains(global[0].Message, "lacks SHOW DATABASES, REPLICATION CLIENT ON *.*") {
+		t.Fatalf("expected missing global privileges, got %v", global)
+	}
+	if len(tables) != 1 || tables[0].Table != "orders" || tables[0].Severity != SeverityBlock {
+		t.Fatalf("expected a SELECT gap on orders only, got %v", tables)
+	}
+
+	full := &source.Grants{User: "debezium", Global: []string{"ALL PRIVILEGES"}}
+	if issues := checkGrants(full, []string{"users", "orders"}); len(issues) != 0 {
+		t.Fatalf("expected ALL PRIVILEGES to cover everything, got %v", issues)
+	}
+	schemaSelect := &source.Grants{User: "debezium", Global: requiredGlobalPrivileges, Schema: []string{"SELECT"}}
+	if issues := checkGrants(schemaSelect, []string{"users", "orders"}); len(issues) != 0 {
+		t.Fatalf("expected schema-level SELECT to cover the tables, got %v", issues)
+	}
+
+	uncertain := &source.Grants{User: "debezium", Host: "%", Global: requiredGlobalPrivileges, Uncertain: "roles of debezium@% cannot be read"}
+	issues := checkGrants(uncertain, []string{"users"})
+	if len(issues) != 1 || issues[0].Severity != SeverityWarn || !strings.Contains(issues[0].Message, "user debezium@%; roles of debezium@% cannot be read") {
+		t.Fatalf("expected a warning when roles are unknown, got %v", issues)
+	}
+}
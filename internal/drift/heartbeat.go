@@ -0,0 +1,69 @@
+package drift
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+)
+
+// checkHeartbeat reports a connector whose heartbeats are disabled while it
+// captures rarely-written tables, whose committed offset then stops
+// advancing, and a connector whose newest heartbeat is older than allowed.
+func checkHeartbeat(cdcResult *cdc.Result, cfg *config.Config, now time.Time) []Issue {
+	hb := cdcResult.Heartbeat
+	if hb == nil {
+		return nil
+	}
+	var cdcCfg config.CDCConfig
+	if cfg != nil {
+		cdcCfg = cfg.CDC
+	}
+	maxAge := cdcCfg.HeartbeatMaxAge(time.Duration(hb.IntervalMs) * time.Millisecond)
+	issue := func(kind, detail string) []Issue {
+		return []Issue{{
+			Severity: SeverityForChange(kind),
+			Message:  fmt.Sprintf("%s (%s)", MessageForChange(kind, "", "", "", ""), detail),
+		}}
+	}
+
+	if hb.IntervalMs == 0 {
+		// Rarely-written tables have no recent event, or none at all
+		var rare []string
+		for _, t := range cdcResult.CapturedTables {
+			if cfg != nil && !cfg.IncludesTable(t) {
+				continue
+			}
+			if last, ok := cdcResult.LastEventTimes[t]; ok {
+				if now.Sub(last) > maxAge {
+					rare = append(rare, t)
+				}
+			} else if n, ok := cdcResult.EventCounts[t]; ok && n == 0 {
+				rare = append(rare, t)
+			}
+		}
+		if len(rare) == 0 {
+			return nil
+		}
+		sort.Strings(rare)
+		return issue("heartbeat_disabled", fmt.Sprintf("heartbeat.interval.ms is not set and %s had no change events in the last %s", strings.Join(rare, ", "), maxAge))
+	}
+
+	if !hb.Read {
+		return nil
+	}
+	var position string
+	if cdcResult.Offset != nil {
+		position = "; committed offset " + offsetString(cdcResult.Offset)
+	}
+	if hb.Last.IsZero() {
+		return issue("heartbeat_stale", fmt.Sprintf("no heartbeat in %s%s", hb.Topic, position))
+	}
+	if age := now.Sub(hb.Last); age > maxAge {
+		return issue("heartbeat_stale", fmt.Sprintf("newest heartbeat in %s is %s old > %s%s", hb.Topic, age.Round(time.Second), maxAge, position))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package drift
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+)
+
+func TestCheckHeartbeat(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := map[string]time.Time{"orders": now.Add(-10 * time.Second), "countries": now.Add(-48 * time.Hour)}
+	offset := &cdc.SourceOffset{BinlogFile: "mysql-bin.000012", BinlogPosition: 4410}
+
+	cases := []struct {
+		name   string
+		res    *cdc.Result
+		kind   string // empty when no issue is expected
+		detail string
+	}{
+		{"disabled, busy tables", &cdc.Result{
+			CapturedTables: []string{"orders"}, LastEventTimes: events, Heartbeat: &cdc.Heartbeat{},
+		}, "", ""},
+		{"disabled, rarely-written tables", &cdc.Result{
+			CapturedTables: []string{"orders", "countries", "regions"}, LastEventTimes: events,
+			EventCounts: map[string]int64{"regions": 0}, Heartbeat: &cdc.Heartbeat{},
+		}, "heartbeat_disabled", "countries, regions had no change events in the last 5m0s"},
+		{"current heartbeat", &cdc.Result{
+			Heartbeat: &cdc.Heartbeat{IntervalMs: 10000, Topic: "__debezium-heartbeat.dbserver1", Read: true, Last: now.Add(-20 * time.Second)},
+		}, "", ""},
+		{"stale heartbeat", &cdc.Result{
+			Offset:    offset,
+			Heartbeat: &cdc.Heartbeat{IntervalMs: 10000, Topic: "__debezium-heartbeat.dbserver1", Read: true, Last: now.Add(-time.Hour)},
+		}, "heartbeat_stale", "is 1h0m0s old > 5m0s; committed offset mysql-bin.000012:4410"},
+		{"no heartbeat yet", &cdc.Result{
+			Heartbeat: &cdc.Heartbeat{IntervalMs: 10000, Topic: "__debezium-heartbeat.dbserver1", Read: true},
+		}, "heartbeat_stale", "no heartbeat in __debezium-heartbeat.dbserver1"},
+		{"topic not read", &cdc.Result{
+			Heartbeat: &cdc.Heartbeat{IntervalMs: 10000, Topic: "__debezium-heartbeat.dbserver1"},
+		}, "", ""},
+	}
+	for _, tc := range cases {
+		issues := checkHeartbeat(tc.res, nil, now)
+		if tc.kind == "" {
+			if len(issues) != 0 {
+				t.Errorf("%s: expected no issues, got %v", tc.name, issues)
+			}
+			continue
+		}
+		if len(issues) != 1 || issues[0].Severity != SeverityForChange(tc.kind) ||
+			!strings.HasPrefix(issues[0].Message, MessageForChange(tc.kind, "", "", "", "")) || !strings.Contains(issues[0].Message, tc.detail) {
+			t.Errorf("%s: expected %s with %q, got %v", tc.name, tc.kind, tc.detail, issues)
+		}
+	}
+}
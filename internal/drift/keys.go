@@ -0,0 +1,175 @@
+package drift
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// compareKeys reports whether two key column lists are identical, and if not,
+// whether they only differ in column order. Column names compare
+// case-insensitively, as they do in MySQL.
+func compareKeys(a, b []string) (equal, orderOnly bool) {
+	if len(a) != len(b) {
+		return false, false
+	}
+	equal = true
+	counts := map[string]int{}
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			equal = false
+		}
+		counts[strings.ToLower(a[i])]++
+		counts[strings.ToLower(b[i])]--
+	}
+	if equal {
+		return true, false
+	}
+	for _, n := range counts {
+		if n != 0 {
+			return false, false
+		}
+	}
+	return false, true
+}
+
+func formatKey(cols []string) string {
+	return "[" + strings.Join(cols, ",") + "]"
+}
+
+// checkDeclaredKey compares a table's declared primaryKey with the MySQL
+// PRIMARY constraint.
+func checkDeclaredKey(table source.TableInfo, tc *config.TableConfig) []Issue {
+	if tc == nil || len(tc.PrimaryKey) == 0 || len(table.PrimaryKey) == 0 {
+		return nil
+	}
+	equal, orderOnly := compareKeys(tc.PrimaryKey, table.PrimaryKey)
+	if equal {
+		return nil
+	}
+	detail := ""
+	if orderOnly {
+		detail = "; column order differs"
+	}
+	return []Issue{{
+		Severity: SeverityForChange("primary_key_mismatch"),
+		Table:    table.Name,
+		Message:  fmt.Sprintf("%s (declared %s, MySQL %s%s)", MessageForChange("primary_key_mismatch", table.Name, "", "", ""), formatKey(tc.PrimaryKey), formatKey(table.PrimaryKey), detail),
+	}}
+}
+
+// checkCDCKey compares the columns the connector uses as the message key with
+// the expected key: the declared primaryKey when configured, otherwise the
+// MySQL primary key. It is skipped when the CDC key is unknown.
+func checkCDCKey(table source.TableInfo, cdcResult *cdc.Result, tc *config.TableConfig) []Issue {
+	cdcKey, ok := cdcResult.KeyColumns[table.Name]
+	if !ok || len(cdcKey) == 0 {
+		return nil
+	}
+	expected, label := table.PrimaryKey, "MySQL"
+	if tc != nil && len(tc.PrimaryKey) > 0 {
+		expected, label = tc.PrimaryKey, "declared"
+	}
+	if len(expected) == 0 {
+		return nil
+	}
+	equal, orderOnly := compareKeys(expected, cdcKey)
+	if equal {
+		return nil
+	}
+	detail := ""
+	if orderOnly {
+		detail = "; column order differs"
+	}
+	return []Issue{{
+		Severity: SeverityForChange("cdc_key_mismatch"),
+		Table:    table.Name,
+		Message:  fmt.Sprintf("%s (%s %s, CDC %s%s)", MessageForChange("cdc_key_mismatch", table.Name, "", "", ""), label, formatKey(expected), formatKey(cdcKey), detail),
+	}}
+}
+
+// checkUniqueKeys checks the message key of a table against its unique
+// indexes. A table without a primary key is only safe to upsert on when the
+// message key still matches one of its unique keys, and a unique key with a
+// nullable column does not identify rows whose key is NULL.
+func checkUniqueKeys(table source.TableInfo, cdcResult *cdc.Result) []Issue {
+	cdcKey := cdcResult.KeyColumns[table.Name]
+	if len(cdcKey) == 0 {
+		return nil
+	}
+	var match *source.IndexInfo
+	var unique []string
+	for i, idx := range table.Indexes {
+		if !idx.Unique {
+			continue
+		}
+		unique = append(unique, formatKey(idx.Columns))
+		if equal, orderOnly := compareKeys(idx.Columns, cdcKey); equal || orderOnly {
+			match = &table.Indexes[i]
+		}
+	}
+	if match == nil {
+		if len(table.PrimaryKey) > 0 {
+			// checkCDCKey compares the key with the primary key
+			return nil
+		}
+		have := "none"
+		if len(unique) > 0 {
+			have = strings.Join(unique, ", ")
+		}
+		return []Issue{{
+			Severity: SeverityForChange("unique_key_changed"),
+			Table:    table.Name,
+			Message:  fmt.Sprintf("%s (CDC %s, MySQL unique keys %s)", MessageForChange("unique_key_changed", table.Name, "", "", ""), formatKey(cdcKey), have),
+		}}
+	}
+	nullable := map[string]bool{}
+	for _, c := range table.Columns {
+		if c.Nullable {
+			nullable[strings.ToLower(c.Name)] = true
+		}
+	}
+	var nullableCols []string
+	for _, c := range match.Columns {
+		if nullable[strings.ToLower(c)] {
+			nullableCols = append(nullableCols, c)
+		}
+	}
+	if len(nullableCols) == 0 {
+		return nil
+	}
+	return []Issue{{
+		Severity: SeverityForChange("nullable_unique_key"),
+		Table:    table.Name,
+		Message:  fmt.Sprintf("%s (%s %s, nullable %s)", MessageForChange("nullable_unique_key", table.Name, "", "", ""), match.Name, formatKey(match.Columns), strings.Join(nullableCols, ", ")),
+	}}
+}
+
+// checkForeignKeys reports foreign keys from a captured table to a table in
+// the same schema that no connector captures, leaving consumers with
+// references they cannot resolve. Parents outside the config's tables are
+// compared with the connectors' capture filters too; parents missing from
+// the source's table list are skipped.
+func checkForeignKeys(table source.TableInfo, captured, sourceTables map[string]struct{}) []Issue {
+	var issues []Issue
+	for _, fk := range table.ForeignKeys {
+		if fk.RefSchema != "" {
+			continue
+		}
+		if _, ok := sourceTables[fk.RefTable]; !ok {
+			continue
+		}
+		if _, ok := captured[fk.RefTable]; ok {
+			continue
+		}
+		issues = append(issues, Issue{
+			Severity: SeverityForChange("fk_parent_not_captured"),
+			Table:    table.Name,
+			Message:  fmt.Sprintf("%s (%s %s -> %s%s)", MessageForChange("fk_parent_not_captured", table.Name, "", "", ""), fk.Name, formatKey(fk.Columns), fk.RefTable, formatKey(fk.RefColumns)),
+		})
+	}
+	return issues
+}
@@ -0,0 +1,123 @@
+package drift
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+func TestDeclaredKeyMismatch(t *testing.T) {
+	cfg := &config.Config{Tables: []config.TableConfig{{Name: "t1", PrimaryKey: []string{"b", "a"}}}}
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "t1", PrimaryKey: []string{"a", "b"}}}}
+	rep := ValidateTables(mysql, nil, cfg)
+	if len(rep.Issues) != 1 || rep.Issues[0].Severity != SeverityBlock {
+		t.Fatalf("expected a single BLOCK issue, got %v", rep.Issues)
+	}
+	if !strings.Contains(rep.Issues[0].Message, "column order differs") {
+		t.Fatalf("expected order difference to be reported, got %q", rep.Issues[0].Message)
+	}
+}
+
+func TestCDCKeyMismatch(t *testing.T) {
+	cfg := &config.Config{Tables: []config.TableConfig{{Name: "t1", PrimaryKey: []string{"id"}}, {Name: "t2", PrimaryKey: []string{"ID"}}}}
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{
+		{Name: "t1", PrimaryKey: []string{"id"}},
+		{Name: "t2", PrimaryKey: []string{"id"}},
+	}}
+	cdcRes := &cdc.Result{
+		CapturedTables: []string{"t1", "t2"},
+		KeyColumns:     map[string][]string{"t1": {"email"}, "t2": {"id"}},
+	}
+	rep := ValidateWithOptions(mysql, cdcRes, Options{Config: cfg})
+	found := map[string]bool{}
+	for _, iss := range rep.Issues {
+		if iss.Severity == SeverityForChange("cdc_key_mismatch") && strings.HasPrefix(iss.Message, MessageForChange("cdc_key_mismatch", "", "", "", "")) {
+			found[iss.Table] = true
+		}
+	}
+	if !found["t1"] || found["t2"] {
+		t.Fatalf("expected cdc_key_mismatch for t1 only, got %v", rep.Issues)
+	}
+}
+
+func TestCompareKeys(t *testing.T) {
+	cases := []struct {
+		a, b             []string
+		equal, orderOnly bool
+	}{
+		{[]string{"a", "b"}, []string{"a", "b"}, true, false},
+		{[]string{"a", "b"}, []string{"B", "A"}, false, true},
+		{[]string{"a"}, []string{"a", "b"}, false, false},
+		{[]string{"a", "c"}, []string{"a", "b"}, false, false},
+	}
+	for _, c := range cases {
+		equal, orderOnly := compareKeys(c.a, c.b)
+		if equal != c.equal || orderOnly != c.orderOnly {
+			t.Errorf("compareKeys(%v, %v) = %v, %v; want %v, %v", c.a, c.b, equal, orderOnly, c.equal, c.orderOnly)
+		}
+	}
+}
+
+func TestUniqueKeyChecks(t *testing.T) {
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{
+		// no primary key; the connector keys on a unique index that was replaced
+		{Name: "events", Columns: []source.ColumnInfo{{Name: "event_uuid"}, {Name: "source_id"}},
+			Indexes: []source.IndexInfo{{Name: "uq_source", Columns: []string{"source_id"}, Unique: true}}},
+		// keyed on a unique index whose column is nullable
+		{Name: "accounts", Columns: []source.ColumnInfo{{Name: "email", Nullable: true}},
+			Indexes: []source.IndexInfo{{Name: "uq_email", Columns: []string{"email"}, Unique: true}}},
+		{Name: "orders", PrimaryKey: []string{"id"}, Columns: []source.ColumnInfo{{Name: "id"}}},
+	}}
+	cdcRes := &cdc.Result{
+		CapturedTables: []string{"events", "accounts", "orders"},
+		KeyColumns:     map[string][]string{"events": {"event_uuid"}, "accounts": {"email"}, "orders": {"id"}},
+	}
+	rep := Validate(mysql, cdcRes)
+	found := map[string]string{}
+	for _, iss := range rep.Issues {
+		for _, kind := range []string{"unique_key_changed", "nullable_unique_key"} {
+			if strings.HasPrefix(iss.Message, MessageForChange(kind, "", "", "", "")) {
+				found[iss.Table] = kind
+			}
+		}
+	}
+	want := map[string]string{"events": "unique_key_changed", "accounts": "nullable_unique_key"}
+	if len(found) != len(want) || found["events"] != want["events"] || found["accounts"] != want["accounts"] {
+		t.Fatalf("expected %v, got %v (issues %v)", want, found, rep.Issues)
+	}
+}
+
+func TestForeignKeyToUncapturedTable(t *testing.T) {
+	cfg := &config.Config{Tables: []config.TableConfig{{Name: "orders"}, {Name: "order_items"}, {Name: "customers"}}}
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{
+		{Name: "orders", ForeignKeys: []source.ForeignKeyInfo{
+			{Name: "fk_customer", Columns: []string{"customer_id"}, RefTable: "customers", RefColumns: []string{"id"}},
+			{Name: "fk_currency", Columns: []string{"currency"}, RefSchema: "reference", RefTable: "currencies", RefColumns: []string{"code"}},
+			// warehouses and carriers are outside the config's tables; only
+			// warehouses is captured
+			{Name: "fk_warehouse", Columns: []string{"warehouse_id"}, RefTable: "warehouses", RefColumns: []string{"id"}},
+			{Name: "fk_carrier", Columns: []string{"carrier_id"}, RefTable: "carriers", RefColumns: []string{"id"}},
+		}},
+		{Name: "order_items", ForeignKeys: []source.ForeignKeyInfo{
+			{Name: "fk_order", Columns: []string{"order_id"}, RefTable: "orders", RefColumns: []string{"id"}},
+		}},
+		{Name: "customers"},
+	}, OtherTables: []string{"warehouses", "carriers"}}
+	connectors := []*cdc.ConnectorResult{
+		{Name: "a", Result: &cdc.Result{ConnectorReachable: true, CapturedTables: []string{"orders"}, CapturedOther: []string{"warehouses"}}},
+		{Name: "b", Result: &cdc.Result{ConnectorReachable: true, CapturedTables: []string{"order_items"}}},
+	}
+	rep := ValidateTables(mysql, connectors, cfg)
+	var fkIssues []Issue
+	for _, iss := range rep.Issues {
+		if strings.HasPrefix(iss.Message, MessageForChange("fk_parent_not_captured", "", "", "", "")) {
+			fkIssues = append(fkIssues, iss)
+		}
+	}
+	if len(fkIssues) != 2 || !strings.Contains(fkIssues[0].Message, "fk_customer") || !strings.Contains(fkIssues[1].Message, "fk_carrier") {
+		t.Fatalf("expected fk_customer and fk_carrier to be reported, got %v", fkIssues)
+	}
+}
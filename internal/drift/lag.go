@@ -0,0 +1,132 @@
+package drift
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// MeasureLag returns how far a connector's committed offset trails the
+// source's current binlog position: in binlog bytes, in GTID transactions,
+// and in seconds since the event at the offset. It returns nil when the
+// offset or the current position is unknown.
+func MeasureLag(src *source.InspectionResult, off *cdc.SourceOffset, now time.Time) *cdc.ReplicationLag {
+	if src == nil || off == nil || src.LogPosition == nil {
+		return nil
+	}
+	cur := src.LogPosition
+	lag := &cdc.ReplicationLag{}
+
+	if b, ok := binlogDistance(src.BinaryLogs, off, cur); ok {
+		lag.Bytes = &b
+	}
+	if off.GTIDSet != "" && cur.GTIDSet != "" {
+		executed, err := source.ParseGTIDSet(cur.GTIDSet)
+		processed, err2 := source.ParseGTIDSet(off.GTIDSet)
+		if err == nil && err2 == nil {
+			n := executed.Subtract(processed).Count()
+			lag.Transactions = &n
+		}
+	}
+
+	caughtUp := (lag.Bytes != nil || lag.Transactions != nil) &&
+		(lag.Bytes == nil || *lag.Bytes == 0) && (lag.Transactions == nil || *lag.Transactions == 0)
+	switch {
+	case caughtUp:
+		s := 0.0
+		lag.Seconds = &s
+	case !off.Timestamp.IsZero():
+		s := now.Sub(off.Timestamp).Seconds()
+		if s < 0 {
+			s = 0
+		}
+		lag.Seconds = &s
+	}
+	if lag.Bytes == nil && lag.Transactions == nil && lag.Seconds == nil {
+		return nil
+	}
+	return lag
+}
+
+// binlogDistance returns the binlog bytes between off and cur, using the file
+// sizes from SHOW BINARY LOGS when they are in different files. The offset is
+// read after the source, so a connector ahead of cur counts as caught up.
+func binlogDistance(logs []source.BinaryLog, off *cdc.SourceOffset, cur *source.LogPosition) (int64, bool) {
+	if off.BinlogFile == "" || cur.File == "" {
+		return 0, false
+	}
+	if off.BinlogFile == cur.File {
+		return max(cur.Position-off.BinlogPosition, 0), true
+	}
+	if binlogBefore(cur.File, off.BinlogFile) {
+		return 0, true
+	}
+	var bytes int64
+	in := false
+	for _, l := range logs {
+		switch {
+		case l.Name == off.BinlogFile:
+			in = true
+			bytes += max(l.Size-off.BinlogPosition, 0)
+		case l.Name == cur.File:
+			if !in {
+				return 0, false
+			}
+			return bytes + cur.Position, true
+		case in:
+			bytes += l.Size
+		}
+	}
+	return 0, false
+}
+
+// checkReplicationLag compares a connector's lag in bytes, transactions and
+// seconds with the connector-wide tolerances. The lag of each table's newest
+// event is checked per table by checkTolerances.
+func checkReplicationLag(lag *cdc.ReplicationLag, tol config.Tolerances) []Issue {
+	if lag == nil {
+		return nil
+	}
+	var issues []Issue
+	if tol.MaxLagSeconds != nil && lag.Seconds != nil && *lag.Seconds > *tol.MaxLagSeconds {
+		issues = append(issues, Issue{
+			Severity: SeverityForChange("cdc_lag_exceeded"),
+			Message:  fmt.Sprintf("%s (connector offset %.0fs behind > %.0fs)", MessageForChange("cdc_lag_exceeded", "", "", "", ""), *lag.Seconds, *tol.MaxLagSeconds),
+		})
+	}
+	if tol.MaxLagBytes != nil && lag.Bytes != nil && *lag.Bytes > *tol.MaxLagBytes {
+		issues = append(issues, Issue{
+			Severity: SeverityForChange("cdc_lag_exceeded"),
+			Message:  fmt.Sprintf("%s (%d binlog bytes behind > %d)", MessageForChange("cdc_lag_exceeded", "", "", "", ""), *lag.Bytes, *tol.MaxLagBytes),
+		})
+	}
+	if tol.MaxLagTransactions != nil && lag.Transactions != nil && *lag.Transactions > *tol.MaxLagTransactions {
+		issues = append(issues, Issue{
+			Severity: SeverityForChange("cdc_lag_exceeded"),
+			Message:  fmt.Sprintf("%s (%d transactions behind > %d)", MessageForChange("cdc_lag_exceeded", "", "", "", ""), *lag.Transactions, *tol.MaxLagTransactions),
+		})
+	}
+	return issues
+}
+
+// FormatLag renders a lag for human output, e.g. "2048 bytes, 3 transactions, 12s".
+func FormatLag(lag *cdc.ReplicationLag) string {
+	if lag == nil {
+		return "unknown"
+	}
+	var parts []string
+	if lag.Bytes != nil {
+		parts = append(parts, fmt.Sprintf("%d bytes", *lag.Bytes))
+	}
+	if lag.Transactions != nil {
+		parts = append(parts, fmt.Sprintf("%d transactions", *lag.Transactions))
+	}
+	if lag.Seconds != nil {
+		parts = append(parts, fmt.Sprintf("%.0fs", *lag.Seconds))
+	}
+	return strings.Join(parts, ", ")
+}
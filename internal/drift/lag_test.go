@@ -0,0 +1,76 @@
+package drift
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+func TestMeasureLag(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	src := &source.InspectionResult{
+		BinaryLogs: []source.BinaryLog{
+			{Name: "mysql-bin.000010", Size: 1000},
+			{Name: "mysql-bin.000011", Size: 5000},
+			{Name: "mysql-bin.000012", Size: 300},
+		},
+		LogPosition: &source.LogPosition{File: "mysql-bin.000012", Position: 300, GTIDSet: "a:1-120,b:1-5"},
+	}
+
+	lag := MeasureLag(src, &cdc.SourceOffset{BinlogFile: "mysql-bin.000010", BinlogPosition: 400, GTIDSet: "a:1-100,b:1-5", Timestamp: now.Add(-90 * time.Second)}, now)
+	if lag == nil || lag.Bytes == nil || *lag.Bytes != 600+5000+300 {
+		t.Fatalf("expected 5900 bytes across three binlogs, got %+v", lag)
+	}
+	if lag.Transactions == nil || *lag.Transactions != 20 || lag.Seconds == nil || *lag.Seconds != 90 {
+		t.Fatalf("expected 20 transactions and 90s, got %s", FormatLag(lag))
+	}
+
+	// A connector at the current position is caught up, however old its last event
+	lag = MeasureLag(src, &cdc.SourceOffset{BinlogFile: "mysql-bin.000012", BinlogPosition: 300, GTIDSet: "a:1-120,b:1-5", Timestamp: now.Add(-time.Hour)}, now)
+	if got := FormatLag(lag); got != "0 bytes, 0 transactions, 0s" {
+		t.Fatalf("expected a caught-up connector, got %s", got)
+	}
+
+	// A purged binlog file leaves the byte distance unknown
+	lag = MeasureLag(src, &cdc.SourceOffset{BinlogFile: "mysql-bin.000007", BinlogPosition: 4}, now)
+	if lag != nil {
+		t.Fatalf("expected no measurable lag, got %s", FormatLag(lag))
+	}
+	if MeasureLag(&source.InspectionResult{}, &cdc.SourceOffset{BinlogFile: "mysql-bin.000012"}, now) != nil {
+		t.Fatal("expected no lag without the source position")
+	}
+}
+
+func TestReplicationLagTolerances(t *testing.T) {
+	bytes, txns, secs := int64(5900), int64(20), 90.0
+	maxBytes, maxTxns, maxSecs := int64(4096), int64(50), 60.0
+	cfg := &config.Config{
+		Tables:     []config.TableConfig{{Name: "orders", PrimaryKey: []string{"id"}}},
+		Tolerances: config.Tolerances{MaxLagBytes: &maxBytes, MaxLagTransactions: &maxTxns, MaxLagSeconds: &maxSecs},
+	}
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "orders", PrimaryKey: []string{"id"}}}}
+	cdcRes := &cdc.Result{
+		CapturedTables: []string{"orders"},
+		Lag:            &cdc.ReplicationLag{Bytes: &bytes, Transactions: &txns, Seconds: &secs},
+	}
+
+	var lagIssues []Issue
+	for _, iss := range ValidateWithOptions(mysql, cdcRes, Options{Config: cfg}).Issues {
+		if strings.HasPrefix(iss.Message, MessageForChange("cdc_lag_exceeded", "", "", "", "")) {
+			lagIssues = append(lagIssues, iss)
+		}
+	}
+	if len(lagIssues) != 2 {
+		t.Fatalf("expected connector-level seconds and byte lag issues, got %v", lagIssues)
+	}
+	if lagIssues[0].Table != "" || !strings.Contains(lagIssues[0].Message, "connector offset 90s behind > 60s") {
+		t.Fatalf("expected a connector-level seconds lag issue, got %v", lagIssues[0])
+	}
+	if lagIssues[1].Table != "" || !strings.Contains(lagIssues[1].Message, "5900 binlog bytes behind > 4096") {
+		t.Fatalf("expected a connector-level byte lag issue, got %v", lagIssues[1])
+	}
+}
@@ -0,0 +1,91 @@
+package drift
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// offsetExpiringFraction is how much of the binlog retention a connector
+// position may use up before it is reported as close to expiring.
+const offsetExpiringFraction = 0.8
+
+// checkOffset compares a connector's committed binlog position with the
+// binlogs still on the server. The connector cannot resume when its binlog
+// file, or with GTIDs any transaction it has not processed, was purged.
+func checkOffset(connector string, off *cdc.SourceOffset, src *source.InspectionResult, retention time.Duration, now time.Time) []Issue {
+	vars := src.ServerVariables
+	logs := src.BinaryLogs
+	issue := func(kind, detail string) []Issue {
+		return []Issue{{
+			Severity: SeverityForChange(kind),
+			Message:  fmt.Sprintf("%s: connector %s at %s (%s)", MessageForChange(kind, "", "", "", ""), connector, offsetString(off), detail),
+		}}
+	}
+
+	// With GTIDs the connector resumes from its GTID set, on any server of
+	// the topology, so the binlog file name does not matter
+	usesGTIDs := off.GTIDSet != "" && strings.EqualFold(vars["gtid_mode"], "ON")
+	if usesGTIDs {
+		purged, err := source.ParseGTIDSet(vars["gtid_purged"])
+		processed, err2 := source.ParseGTIDSet(off.GTIDSet)
+		if err == nil && err2 == nil {
+			if missing := purged.Subtract(processed); len(missing) > 0 {
+				return issue("binlog_position_purged", fmt.Sprintf("GTIDs %s were purged before the connector read them", missing))
+			}
+		}
+	}
+
+	idx := -1
+	for n, l := range logs {
+		if l.Name == off.BinlogFile {
+			idx = n
+		}
+	}
+	if idx < 0 && len(logs) > 0 && !usesGTIDs {
+		if binlogBefore(off.BinlogFile, logs[0].Name) {
+			return issue("binlog_position_purged", fmt.Sprintf("oldest binlog on the server is %s", logs[0].Name))
+		}
+		return issue("binlog_position_purged", fmt.Sprintf("binlog file not on the server, which has %s to %s; the connector may have been pointed at another server", logs[0].Name, logs[len(logs)-1].Name))
+	}
+
+	// The binlog being written is never purged, however old the position
+	current := idx >= 0 && idx == len(logs)-1
+	switch {
+	case current:
+	case idx == 0 && len(logs) > 1:
+		return issue("binlog_position_expiring", "oldest binlog on the server; it is purged next")
+	case retention > 0 && !off.Timestamp.IsZero():
+		if age := now.Sub(off.Timestamp); age >= time.Duration(float64(retention)*offsetExpiringFraction) {
+			return issue("binlog_position_expiring", fmt.Sprintf("position is %s old, binlog retention %s", age.Round(time.Second), retention))
+		}
+	}
+	return nil
+}
+
+// offsetString formats an offset as file:position, followed by the GTID set
+// when there is one.
+func offsetString(off *cdc.SourceOffset) string {
+	s := fmt.Sprintf("%s:%d", off.BinlogFile, off.BinlogPosition)
+	if off.GTIDSet != "" {
+		s += " gtids=" + off.GTIDSet
+	}
+	return s
+}
+
+// binlogBefore reports whether binlog file a precedes b in the same binlog
+// sequence, e.g. mysql-bin.000009 precedes mysql-bin.000010. Files of
+// different sequences are not ordered.
+func binlogBefore(a, b string) bool {
+	dotA, dotB := strings.LastIndex(a, "."), strings.LastIndex(b, ".")
+	if dotA < 0 || dotB < 0 || a[:dotA] != b[:dotB] {
+		return false
+	}
+	na, errA := strconv.ParseInt(a[dotA+1:], 10, 64)
+	nb, errB := strconv.ParseInt(b[dotB+1:], 10, 64)
+	return errA == nil && errB == nil && na < nb
+}
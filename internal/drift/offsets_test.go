@@ -0,0 +1,55 @@
+package drift
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+func TestCheckOffset(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	retention := 7 * 24 * time.Hour
+	logs := []source.BinaryLog{{Name: "mysql-bin.000010"}, {Name: "mysql-bin.000011"}, {Name: "mysql-bin.000012"}}
+	fileMode := &source.InspectionResult{ServerVariables: map[string]string{"gtid_mode": "OFF"}, BinaryLogs: logs}
+	gtidMode := &source.InspectionResult{
+		ServerVariables: map[string]string{"gtid_mode": "ON", "gtid_purged": "a:1-500"},
+		BinaryLogs:      logs,
+	}
+
+	cases := []struct {
+		name string
+		src  *source.InspectionResult
+		off  cdc.SourceOffset
+		kind string // empty when no issue is expected
+	}{
+		{"current binlog", fileMode, cdc.SourceOffset{BinlogFile: "mysql-bin.000012", Timestamp: now.Add(-30 * 24 * time.Hour)}, ""},
+		{"retained binlog", fileMode, cdc.SourceOffset{BinlogFile: "mysql-bin.000011", Timestamp: now.Add(-time.Hour)}, ""},
+		{"purged binlog", fileMode, cdc.SourceOffset{BinlogFile: "mysql-bin.000009"}, "binlog_position_purged"},
+		{"unknown binlog", fileMode, cdc.SourceOffset{BinlogFile: "replica-bin.000042"}, "binlog_position_purged"},
+		{"oldest binlog", fileMode, cdc.SourceOffset{BinlogFile: "mysql-bin.000010"}, "binlog_position_expiring"},
+		{"old position", fileMode, cdc.SourceOffset{BinlogFile: "mysql-bin.000011", Timestamp: now.Add(-6 * 24 * time.Hour)}, "binlog_position_expiring"},
+		{"purged GTIDs", gtidMode, cdc.SourceOffset{BinlogFile: "mysql-bin.000011", GTIDSet: "a:1-400"}, "binlog_position_purged"},
+		{"GTIDs after failover", gtidMode, cdc.SourceOffset{BinlogFile: "replica-bin.000042", GTIDSet: "a:1-800"}, ""},
+	}
+	for _, tc := range cases {
+		issues := checkOffset("inventory", &tc.off, tc.src, retention, now)
+		if tc.kind == "" {
+			if len(issues) != 0 {
+				t.Errorf("%s: expected no issues, got %v", tc.name, issues)
+			}
+			continue
+		}
+		if len(issues) != 1 || issues[0].Severity != SeverityForChange(tc.kind) ||
+			!strings.HasPrefix(issues[0].Message, MessageForChange(tc.kind, "", "", "", "")+": connector inventory at "+tc.off.BinlogFile) {
+			t.Errorf("%s: expected %s, got %v", tc.name, tc.kind, issues)
+		}
+	}
+
+	issues := checkOffset("inventory", &cdc.SourceOffset{BinlogFile: "mysql-bin.000011", GTIDSet: "a:1-400"}, gtidMode, retention, now)
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "GTIDs a:401-500 were purged") {
+		t.Fatalf("expected the purged GTIDs to be named, got %v", issues)
+	}
+}
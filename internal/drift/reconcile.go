@@ -0,0 +1,393 @@
+package drift
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"hash/maphash"
+	"sort"
+	"strings"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// maxEvidence is how many sample keys a reconciliation issue lists.
+const maxEvidence = 3
+
+// ReconcileOptions configure ReconcileTable.
+type ReconcileOptions struct {
+	SampleSize int // source rows, and live topic keys, to sample
+	// KeyColumns are the columns the connector keys events by; the primary
+	// key when empty
+	KeyColumns []string
+	Settings   map[string]string           // connector emit settings
+	Policies   map[string]cdc.ColumnPolicy // columns the connector alters, which are not compared
+	// Compacted reports whether the topic is compacted, so every live row
+	// still has an event. Rows without one are only reported then; on other
+	// topics retention may have removed them.
+	Compacted bool
+}
+
+// ReconcileResult is the outcome of reconciling a table with its data topic.
+type ReconcileResult struct {
+	Table        string
+	Topic        string
+	Sampled      int // source rows sampled
+	Matched      int // sampled rows whose latest event has the same values
+	TopicSampled int // live topic keys sampled and looked up in the source
+	Issues       []Issue
+}
+
+// reconcileRow is a sampled key with its source row and latest event.
+type reconcileRow struct {
+	key   []string // canonical key values, in key column order
+	row   source.Row
+	event *cdc.RowEvent
+}
+
+// hashedKey is a sampled topic key with its hash.
+type hashedKey struct {
+	hash uint64
+	key  string
+}
+
+// keyHeap is a max-heap of sampled topic keys by hash.
+type keyHeap []hashedKey
+
+func (h keyHeap) Len() int           { return len(h) }
+func (h keyHeap) Less(a, b int) bool { return h[a].hash > h[b].hash }
+func (h keyHeap) Swap(a, b int)      { h[a], h[b] = h[b], h[a] }
+func (h *keyHeap) Push(x any)        { *h = append(*h, x.(hashedKey)) }
+func (h *keyHeap) Pop() any {
+	old := *h
+	k := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return k
+}
+
+// valueDiff is a column whose source and event values differ.
+type valueDiff struct {
+	column, source, event string
+}
+
+// reconciler compares the rows and events of one table.
+type reconciler struct {
+	table   source.TableInfo
+	keyCols []source.ColumnInfo
+	cols    map[string]source.ColumnInfo // by lowercase name
+	opts    ReconcileOptions
+}
+
+// ReconcileTable samples rows of table from the source, finds the latest
+// change event of each sampled key in topic, and compares their values after
+// normalizing both to the column's type. It also samples live keys from the
+// topic and looks them up in the source. Rows that do not match are read
+// again after the topic, so rows changed in the meantime are compared with
+// their current values; rows deleted in the meantime are not reported.
+func ReconcileTable(ctx context.Context, rows source.RowSampler, events cdc.TopicReplayer, table source.TableInfo, topic string, opts ReconcileOptions) (*ReconcileResult, error) {
+	r := &reconciler{table: table, cols: map[string]source.ColumnInfo{}, opts: opts}
+	for _, c := range table.Columns {
+		r.cols[strings.ToLower(c.Name)] = c
+	}
+	keyNames := opts.KeyColumns
+	if len(keyNames) == 0 {
+		keyNames = table.PrimaryKey
+	}
+	if len(keyNames) == 0 {
+		return nil, fmt.Errorf("table %s has no primary key or message key to match events by", table.Name)
+	}
+	for _, k := range keyNames {
+		c, ok := r.cols[strings.ToLower(k)]
+		if !ok {
+			return nil, fmt.Errorf("key column %s is not in table %s", k, table.Name)
+		}
+		r.keyCols = append(r.keyCols, c)
+	}
+
+	res := &ReconcileResult{Table: table.Name, Topic: topic}
+	// The primary key is indexed, so ranges of it are cheap to find
+	sampleKey := table.PrimaryKey
+	if len(sampleKey) == 0 {
+		sampleKey = keyNames
+	}
+	sample, err := rows.SampleRows(ctx, table.Name, sampleKey, opts.SampleSize)
+	if err != nil {
+		return nil, fmt.Errorf("sampling %s: %w", table.Name, err)
+	}
+	wanted := map[string]*reconcileRow{}
+	var order []string
+	for _, row := range sample {
+		key, ok := r.sourceKey(row)
+		if !ok {
+			continue
+		}
+		ks := strings.Join(key, "\x00")
+		if _, dup := wanted[ks]; dup {
+			continue
+		}
+		wanted[ks] = &reconcileRow{key: key, row: row}
+		order = append(order, ks)
+	}
+	res.Sampled = len(order)
+
+	// Sample of the other keys in the topic, kept at their latest event: the
+	// keys with the smallest hashes, so each distinct key is equally likely
+	// to be kept however many events it has, and a key once dropped is
+	// never taken back
+	seed := maphash.MakeSeed()
+	var sampled keyHeap
+	others := map[string]*reconcileRow{}
+	err = events.ReplayTopic(ctx, topic, func(ev cdc.RowEvent) error {
+		key, ok := r.eventKey(ev)
+		if !ok {
+			return nil
+		}
+		ks := strings.Join(key, "\x00")
+		if w, ok := wanted[ks]; ok {
+			w.event = &ev
+			return nil
+		}
+		if o, ok := others[ks]; ok {
+			o.event = &ev
+			return nil
+		}
+		if opts.SampleSize <= 0 {
+			return nil
+		}
+		h := maphash.String(seed, ks)
+		if len(sampled) >= opts.SampleSize {
+			if h >= sampled[0].hash {
+				return nil
+			}
+			delete(others, heap.Pop(&sampled).(hashedKey).key)
+		}
+		heap.Push(&sampled, hashedKey{hash: h, key: ks})
+		others[ks] = &reconcileRow{key: key, event: &ev}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("replaying topic %s: %w", topic, err)
+	}
+
+	// Read rows that do not match again, since they may have changed while
+	// the topic was read
+	var recheck [][]string
+	for _, ks := range order {
+		w := wanted[ks]
+		if w.event == nil || w.event.After == nil || len(r.compare(w.row, w.event)) > 0 {
+			recheck = append(recheck, w.key)
+		}
+	}
+	current, err := r.fetch(ctx, rows, recheck)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	diffs := map[string][]string{} // evidence per column
+	var diffCols []string
+	for _, ks := range order {
+		w := wanted[ks]
+		if w.event == nil || w.event.After == nil || len(r.compare(w.row, w.event)) > 0 {
+			row, ok := current[ks]
+			if !ok {
+				continue
+			}
+			w.row = row
+		}
+		if w.event == nil || w.event.After == nil {
+			if opts.Compacted {
+				missing = append(missing, r.displayKey(w.key))
+			}
+			continue
+		}
+		d := r.compare(w.row, w.event)
+		if len(d) == 0 {
+			res.Matched++
+			continue
+		}
+		for _, vd := range d {
+			if _, seen := diffs[vd.column]; !seen {
+				diffCols = append(diffCols, vd.column)
+			}
+			diffs[vd.column] = append(diffs[vd.column], fmt.Sprintf("%s: source %s, CDC %s", r.displayKey(w.key), vd.source, vd.event))
+		}
+	}
+
+	// Look up the sampled topic keys whose latest event is not a delete.
+	// Keys of types whose canonical form MySQL does not accept as a value
+	// are not looked up.
+	var extra []string
+	if r.keysLookupable() {
+		var live [][]string
+		for _, k := range sampled {
+			if o := others[k.key]; o.event.After != nil {
+				live = append(live, o.key)
+			}
+		}
+		found, err := r.fetch(ctx, rows, live)
+		if err != nil {
+			return nil, err
+		}
+		res.TopicSampled = len(live)
+		for _, key := range live {
+			if _, ok := found[strings.Join(key, "\x00")]; !ok {
+				extra = append(extra, r.displayKey(key))
+			}
+		}
+	}
+
+	sort.Strings(diffCols)
+	for _, c := range diffCols {
+		res.Issues = append(res.Issues, Issue{
+			Severity: SeverityForChange("row_value_mismatch"),
+			Table:    table.Name,
+			Column:   c,
+			Message: fmt.Sprintf("%s (%d of %d sampled rows; %s)", MessageForChange("row_value_mismatch", table.Name, c, "", ""),
+				len(diffs[c]), res.Sampled, strings.Join(diffs[c][:min(len(diffs[c]), maxEvidence)], "; ")),
+		})
+	}
+	if len(missing) > 0 {
+		res.Issues = append(res.Issues, Issue{
+			Severity: SeverityForChange("row_missing_in_cdc"),
+			Table:    table.Name,
+			Message: fmt.Sprintf("%s (%d of %d sampled rows, e.g. %s)", MessageForChange("row_missing_in_cdc", table.Name, "", "", ""),
+				len(missing), res.Sampled, strings.Join(missing[:min(len(missing), maxEvidence)], ", ")),
+		})
+	}
+	if len(extra) > 0 {
+		res.Issues = append(res.Issues, Issue{
+			Severity: SeverityForChange("row_extra_in_cdc"),
+			Table:    table.Name,
+			Message: fmt.Sprintf("%s (%d of %d sampled topic keys, e.g. %s)", MessageForChange("row_extra_in_cdc", table.Name, "", "", ""),
+				len(extra), res.TopicSampled, strings.Join(extra[:min(len(extra), maxEvidence)], ", ")),
+		})
+	}
+	return res, nil
+}
+
+// sourceKey returns the canonical key of a source row.
+func (r *reconciler) sourceKey(row source.Row) ([]string, bool) {
+	key := make([]string, len(r.keyCols))
+	for n, c := range r.keyCols {
+		v, ok := row[c.Name]
+		if !ok {
+			return nil, false
+		}
+		if key[n], ok = sourceValue(c, v); !ok {
+			return nil, false
+		}
+	}
+	return key, true
+}
+
+// eventKey returns the canonical key of a change event.
+func (r *reconciler) eventKey(ev cdc.RowEvent) ([]string, bool) {
+	key := make([]string, len(r.keyCols))
+	for n, c := range r.keyCols {
+		name, v, ok := fieldFold(ev.Key, c.Name)
+		if !ok {
+			return nil, false
+		}
+		if key[n], ok = eventValue(c, v, ev.Fields[name], r.opts.Settings); !ok {
+			return nil, false
+		}
+	}
+	return key, true
+}
+
+// compare returns the columns whose values differ between a source row and
+// an event's row image. Columns the connector alters, columns missing on
+// either side and values that cannot be normalized are not compared.
+func (r *reconciler) compare(row source.Row, ev *cdc.RowEvent) []valueDiff {
+	var out []valueDiff
+	for name, v := range ev.After {
+		col, ok := r.cols[strings.ToLower(name)]
+		if !ok || r.altered(col.Name) {
+			continue
+		}
+		raw, ok := row[col.Name]
+		if !ok {
+			continue
+		}
+		sv, ok := sourceValue(col, raw)
+		if !ok {
+			continue
+		}
+		evv, ok := eventValue(col, v, ev.Fields[name], r.opts.Settings)
+		if !ok || sv == evv {
+			continue
+		}
+		out = append(out, valueDiff{column: col.Name, source: displayValue(col, sv), event: displayValue(col, evv)})
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].column < out[b].column })
+	return out
+}
+
+// altered reports whether the connector drops or rewrites a column.
+func (r *reconciler) altered(column string) bool {
+	for c := range r.opts.Policies {
+		if strings.EqualFold(c, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetch reads the source rows with the given canonical keys, keyed by their
+// joined canonical key.
+func (r *reconciler) fetch(ctx context.Context, rows source.RowSampler, keys [][]string) (map[string]source.Row, error) {
+	out := map[string]source.Row{}
+	if len(keys) == 0 {
+		return out, nil
+	}
+	names := make([]string, len(r.keyCols))
+	for n, c := range r.keyCols {
+		names[n] = c.Name
+	}
+	found, err := rows.FetchRows(ctx, r.table.Name, names, keys)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", r.table.Name, err)
+	}
+	for _, row := range found {
+		if key, ok := r.sourceKey(row); ok {
+			out[strings.Join(key, "\x00")] = row
+		}
+	}
+	return out, nil
+}
+
+// keysLookupable reports whether the canonical key values can be passed to
+// the source as query arguments.
+func (r *reconciler) keysLookupable() bool {
+	for _, c := range r.keyCols {
+		switch valueType(c).family {
+		case famBit, famFloat, famJSON, famGeometry:
+			return false
+		}
+	}
+	return true
+}
+
+// displayKey renders a key for a message, e.g. "id=42".
+func (r *reconciler) displayKey(key []string) string {
+	parts := make([]string, len(key))
+	for n, c := range r.keyCols {
+		parts[n] = c.Name + "=" + displayValue(c, key[n])
+	}
+	return strings.Join(parts, ",")
+}
+
+// fieldFold looks up a field by name, ignoring case.
+func fieldFold(fields map[string]interface{}, name string) (string, interface{}, bool) {
+	if v, ok := fields[name]; ok {
+		return name, v, true
+	}
+	for f, v := range fields {
+		if strings.EqualFold(f, name) {
+			return f, v, true
+		}
+	}
+	return "", nil, false
+}
@@ -0,0 +1,208 @@
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// fakeRows serves rows by id. FetchRows sees the rows in changes instead of
+// the sampled ones, as if they were updated after sampling; nil deletes.
+type fakeRows struct {
+	rows    []source.Row
+	changes map[string]source.Row
+}
+
+func (f *fakeRows) SampleRows(ctx context.Context, table string, keyColumns []string, n int) ([]source.Row, error) {
+	return f.rows[:min(n, len(f.rows))], nil
+}
+
+func (f *fakeRows) FetchRows(ctx context.Context, table string, keyColumns []string, keys [][]string) ([]source.Row, error) {
+	byID := map[string]source.Row{}
+	for _, r := range f.rows {
+		byID[*r["id"]] = r
+	}
+	for id, r := range f.changes {
+		byID[id] = r
+	}
+	var out []source.Row
+	for _, k := range keys {
+		if r, ok := byID[k[0]]; ok && r != nil {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+type fakeEvents []cdc.RowEvent
+
+func (f fakeEvents) ReplayTopic(ctx context.Context, topic string, fn func(cdc.RowEvent) error) error {
+	for _, ev := range f {
+		if err := fn(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reconcileRowOf(id, name, total string) source.Row {
+	return source.Row{"id": &id, "name": &name, "total": &total}
+}
+
+func upsert(id int, name, total string) cdc.RowEvent {
+	key := map[string]interface{}{"id": json.Number(strconv.Itoa(id))}
+	return cdc.RowEvent{Key: key, After: map[string]interface{}{
+		"id": key["id"], "name": name, "total": json.Number(total),
+	}}
+}
+
+func deleted(id int) cdc.RowEvent {
+	return cdc.RowEvent{Key: map[string]interface{}{"id": json.Number(strconv.Itoa(id))}}
+}
+
+var reconcileTable = source.TableInfo{
+	Name: "orders",
+	Columns: []source.ColumnInfo{
+		{Name: "id", Type: "int"},
+		{Name: "name", Type: "varchar(20)"},
+		{Name: "total", Type: "decimal(10,2)"},
+	},
+	PrimaryKey: []string{"id"},
+}
+
+func TestReconcileTable(t *testing.T) {
+	rows := &fakeRows{rows: []source.Row{
+		reconcileRowOf("1", "a", "10.00"),
+		reconcileRowOf("2", "b", "20.00"),
+		reconcileRowOf("3", "c", "30.00"),
+		reconcileRowOf("4", "d", "40.00"),
+		reconcileRowOf("5", "e", "50.00"),
+	}}
+	events := fakeEvents{
+		upsert(1, "a", "10.0"),
+		upsert(2, "b", "99.99"),
+		upsert(3, "x", "30"),
+		upsert(3, "c", "30"), // latest event wins
+		upsert(4, "d", "40"),
+		deleted(4),
+		upsert(7, "g", "70"), // not in the source
+		upsert(8, "h", "80"),
+		deleted(8),
+	}
+	opts := ReconcileOptions{SampleSize: 10, Settings: map[string]string{"decimal.handling.mode": "double"}, Compacted: true}
+	res, err := ReconcileTable(context.Background(), rows, events, reconcileTable, "shop.orders", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Sampled != 5 || res.Matched != 2 || res.TopicSampled != 1 {
+		t.Errorf("expected 5 sampled, 2 matched, 1 topic key looked up, got %+v", res)
+	}
+
+	var mismatch, missing, extra *Issue
+	for n, iss := range res.Issues {
+		switch {
+		case strings.Contains(iss.Message, MessageForChange("row_value_mismatch", "", "", "", "")):
+			mismatch = &res.Issues[n]
+		case strings.Contains(iss.Message, MessageForChange("row_missing_in_cdc", "", "", "", "")):
+			missing = &res.Issues[n]
+		case strings.Contains(iss.Message, MessageForChange("row_extra_in_cdc", "", "", "", "")):
+			extra = &res.Issues[n]
+		}
+	}
+	if mismatch == nil || mismatch.Severity != SeverityBlock || mismatch.Column != "total" ||
+		!strings.Contains(mismatch.Message, "id=2: source 20.00, CDC 99.99") {
+		t.Errorf("expected a total mismatch with evidence for id=2, got %+v", mismatch)
+	}
+	if missing == nil || missing.Severity != SeverityWarn || !strings.Contains(missing.Message, "2 of 5 sampled rows, e.g. id=4, id=5") {
+		t.Errorf("expected id=4 and id=5 missing from the topic, got %+v", missing)
+	}
+	if extra == nil || extra.Severity != SeverityWarn || !strings.Contains(extra.Message, "id=7") {
+		t.Errorf("expected id=7 extra in the topic, got %+v", extra)
+	}
+	if len(res.Issues) != 3 {
+		t.Errorf("expected 3 issues, got %+v", res.Issues)
+	}
+}
+
+func TestReconcileTableRechecksChangedRows(t *testing.T) {
+	// Row 1 was updated after it was sampled and row 2 deleted; the topic
+	// already has both changes
+	rows := &fakeRows{
+		rows: []source.Row{reconcileRowOf("1", "a", "10.00"), reconcileRowOf("2", "b", "20.00")},
+		changes: map[string]source.Row{
+			"1": reconcileRowOf("1", "a", "11.00"),
+			"2": nil,
+		},
+	}
+	events := fakeEvents{upsert(1, "a", "11"), deleted(2)}
+	opts := ReconcileOptions{SampleSize: 10, Settings: map[string]string{"decimal.handling.mode": "double"}, Compacted: true}
+	res, err := ReconcileTable(context.Background(), rows, events, reconcileTable, "shop.orders", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Issues) != 0 || res.Matched != 1 {
+		t.Errorf("expected rows changed during the run not to be reported, got %+v", res)
+	}
+}
+
+func TestReconcileTableSkipsAlteredColumnsAndRetainedTopics(t *testing.T) {
+	rows := &fakeRows{rows: []source.Row{reconcileRowOf("1", "alice", "10.00"), reconcileRowOf("2", "b", "20.00")}}
+	events := fakeEvents{upsert(1, "*****", "10")}
+	opts := ReconcileOptions{
+		SampleSize: 10,
+		Settings:   map[string]string{"decimal.handling.mode": "double"},
+		Policies:   map[string]cdc.ColumnPolicy{"name": cdc.ColumnMasked},
+	}
+	res, err := ReconcileTable(context.Background(), rows, events, reconcileTable, "shop.orders", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Row 2 has no event, but the topic is not compacted
+	if len(res.Issues) != 0 || res.Matched != 1 {
+		t.Errorf("expected masked columns and rows without events on a retained topic to be skipped, got %+v", res)
+	}
+
+	noKey := reconcileTable
+	noKey.PrimaryKey = nil
+	if _, err := ReconcileTable(context.Background(), rows, events, noKey, "shop.orders", opts); err == nil {
+		t.Error("expected an error for a table without a key")
+	}
+}
+
+func TestReconcileTableSamplesDistinctTopicKeys(t *testing.T) {
+	rows := &fakeRows{rows: []source.Row{reconcileRowOf("1", "a", "10.00")}}
+	// Many events for few keys, then every other key deleted
+	var events fakeEvents
+	for n := 0; n < 50; n++ {
+		for id := 10; id < 20; id++ {
+			events = append(events, upsert(id, "x", "1"))
+		}
+	}
+	events = append(events, upsert(1, "a", "10"))
+	for id := 10; id < 20; id += 2 {
+		events = append(events, deleted(id))
+	}
+	opts := ReconcileOptions{SampleSize: 10, Settings: map[string]string{"decimal.handling.mode": "double"}, Compacted: true}
+	res, err := ReconcileTable(context.Background(), rows, events, reconcileTable, "shop.orders", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ten distinct keys fit the sample, each at its latest event
+	if res.TopicSampled != 5 {
+		t.Errorf("expected the 5 live topic keys to be looked up, got %+v", res)
+	}
+
+	opts.SampleSize = 3
+	res, err = ReconcileTable(context.Background(), rows, events, reconcileTable, "shop.orders", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TopicSampled > 3 {
+		t.Errorf("expected at most 3 topic keys looked up, got %+v", res)
+	}
+}
@@ -0,0 +1,133 @@
+package drift
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// ValidateServer checks the source server settings Debezium depends on: the
+// binlog must be enabled and written in ROW format with full row images, must
+// be retained for longer than the connectors can be down, and must still hold
+// the position each connector resumes from. It reports nothing else for
+// sources that do not expose server variables. Optional reads that failed
+// during the inspection are reported too, as their checks were skipped.
+func ValidateServer(
+	src *source.InspectionResult,
+	connectors []*cdc.ConnectorResult,
+	cfg *config.Config,
+) *Report {
+	report := &Report{}
+	for _, w := range src.Warnings {
+		report.Issues = append(report.Issues, Issue{
+			Severity: SeverityForChange("source_inspection_incomplete"),
+			Message:  fmt.Sprintf("%s: %s", MessageForChange("source_inspection_incomplete", "", "", "", ""), w),
+		})
+	}
+	vars := src.ServerVariables
+	if len(vars) == 0 {
+		return report
+	}
+	add := func(kind, variable, detail string) {
+		report.Issues = append(report.Issues, Issue{
+			Severity: SeverityForChange(kind),
+			Message:  fmt.Sprintf("%s: %s=%s (%s)", MessageForChange(kind, "", "", "", ""), variable, vars[variable], detail),
+		})
+	}
+	isOn := func(v string) bool {
+		switch strings.ToUpper(strings.TrimSpace(vars[v])) {
+		case "ON", "1":
+			return true
+		}
+		return false
+	}
+
+	if _, ok := vars["log_bin"]; ok && !isOn("log_bin") {
+		add("binlog_config_incompatible", "log_bin", "Debezium reads changes from the binlog")
+		// Nothing else matters without a binlog
+		return report
+	}
+	if v, ok := vars["binlog_format"]; ok && !strings.EqualFold(v, "ROW") {
+		add("binlog_config_incompatible", "binlog_format", "Debezium requires ROW; STATEMENT and MIXED events carry no row data")
+	}
+	if v, ok := vars["binlog_row_image"]; ok {
+		switch strings.ToUpper(v) {
+		case "FULL":
+		case "NOBLOB":
+			add("binlog_config_risky", "binlog_row_image", "unchanged BLOB and TEXT columns are missing from update events")
+		default:
+			add("binlog_config_incompatible", "binlog_row_image", "Debezium requires FULL; update events would only carry changed columns")
+		}
+	}
+	if v, ok := vars["binlog_row_metadata"]; ok && !strings.EqualFold(v, "FULL") {
+		add("binlog_config_suboptimal", "binlog_row_metadata", "FULL records column names and ENUM/SET values in the binlog")
+	}
+	if v, ok := vars["gtid_mode"]; ok && !strings.EqualFold(v, "ON") {
+		add("binlog_config_risky", "gtid_mode", "without GTIDs the connector cannot resume from a failed-over replica")
+	} else if ok && !isOn("enforce_gtid_consistency") {
+		add("binlog_config_risky", "enforce_gtid_consistency", "gtid_mode=ON needs enforce_gtid_consistency=ON")
+	}
+
+	retention, variable := binlogRetention(vars)
+	for _, cr := range connectors {
+		if cr.Result != nil && cr.Result.Offset != nil {
+			report.Issues = append(report.Issues, checkOffset(cr.Name, cr.Result.Offset, src, retention, time.Now())...)
+		}
+	}
+	if retention <= 0 {
+		// binlogs are never purged automatically
+		return report
+	}
+	var maxLag float64
+	for _, cr := range connectors {
+		if cr.Result == nil {
+			continue
+		}
+		// The offset lag, when measured, is the connector's current lag.
+		// Otherwise the connector has read at least up to the newest change
+		// of any of its tables, so the smallest table lag bounds its lag;
+		// idle tables make the others' lags overstate it.
+		if l := cr.Result.Lag; l != nil && l.Seconds != nil {
+			maxLag = max(maxLag, *l.Seconds)
+			continue
+		}
+		first, connectorLag := true, 0.0
+		for _, lag := range cr.Result.LagSeconds {
+			if first || lag < connectorLag {
+				connectorLag = lag
+			}
+			first = false
+		}
+		maxLag = max(maxLag, connectorLag)
+	}
+	lag := time.Duration(maxLag * float64(time.Second))
+	downtime := config.CDCConfig{}.MaxDowntime()
+	if cfg != nil {
+		downtime = cfg.CDC.MaxDowntime()
+	}
+	switch {
+	case lag >= retention:
+		add("binlog_retention_exceeded", variable, fmt.Sprintf("retention %s, CDC lag %s", retention, lag.Round(time.Second)))
+	case downtime >= retention:
+		add("binlog_retention_short", variable, fmt.Sprintf("retention %s, cdc.maxDowntimeSeconds %s", retention, downtime))
+	}
+	return report
+}
+
+// binlogRetention returns how long the server keeps binlogs and the variable
+// that sets it, or 0 if they are never purged automatically.
+// binlog_expire_logs_seconds takes precedence over the older expire_logs_days.
+func binlogRetention(vars map[string]string) (time.Duration, string) {
+	if s, err := strconv.ParseInt(strings.TrimSpace(vars["binlog_expire_logs_seconds"]), 10, 64); err == nil && s > 0 {
+		return time.Duration(s) * time.Second, "binlog_expire_logs_seconds"
+	}
+	if d, err := strconv.ParseFloat(strings.TrimSpace(vars["expire_logs_days"]), 64); err == nil && d > 0 {
+		return time.Duration(d * 24 * float64(time.Hour)), "expire_logs_days"
+	}
+	return 0, ""
+}
@@ -0,0 +1,93 @@
+package drift
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+func TestValidateServer(t *testing.T) {
+	healthy := map[string]string{
+		"log_bin":                    "ON",
+		"binlog_format":              "ROW",
+		"binlog_row_image":           "FULL",
+		"binlog_row_metadata":        "FULL",
+		"gtid_mode":                  "ON",
+		"enforce_gtid_consistency":   "ON",
+		"binlog_expire_logs_seconds": "604800",
+	}
+	with := func(overrides map[string]string) *source.InspectionResult {
+		vars := map[string]string{}
+		for k, v := range healthy {
+			vars[k] = v
+		}
+		for k, v := range overrides {
+			vars[k] = v
+		}
+		return &source.InspectionResult{ServerVariables: vars}
+	}
+	cfg := &config.Config{}
+
+	if rep := ValidateServer(with(nil), nil, cfg); len(rep.Issues) != 0 {
+		t.Fatalf("expected no issues for a healthy server, got %v", rep.Issues)
+	}
+	if rep := ValidateServer(&source.InspectionResult{}, nil, cfg); len(rep.Issues) != 0 {
+		t.Fatalf("expected no issues without server variables, got %v", rep.Issues)
+	}
+
+	cases := []struct {
+		overrides  map[string]string
+		connectors []*cdc.ConnectorResult
+		cfg        *config.Config
+		kind       string
+		variable   string
+	}{
+		{map[string]string{"log_bin": "OFF"}, nil, cfg, "binlog_config_incompatible", "log_bin"},
+		{map[string]string{"binlog_format": "STATEMENT"}, nil, cfg, "binlog_config_incompatible", "binlog_format"},
+		{map[string]string{"binlog_row_image": "MINIMAL"}, nil, cfg, "binlog_config_incompatible", "binlog_row_image"},
+		{map[string]string{"binlog_row_image": "NOBLOB"}, nil, cfg, "binlog_config_risky", "binlog_row_image"},
+		{map[string]string{"binlog_row_metadata": "MINIMAL"}, nil, cfg, "binlog_config_suboptimal", "binlog_row_metadata"},
+		{map[string]string{"gtid_mode": "OFF_PERMISSIVE"}, nil, cfg, "binlog_config_risky", "gtid_mode"},
+		{map[string]string{"enforce_gtid_consistency": "WARN"}, nil, cfg, "binlog_config_risky", "enforce_gtid_consistency"},
+		{map[string]string{"binlog_expire_logs_seconds": "3600"}, nil, cfg, "binlog_retention_short", "binlog_expire_logs_seconds"},
+		{map[string]string{"binlog_expire_logs_seconds": "0", "expire_logs_days": "2"},
+			nil, &config.Config{CDC: config.CDCConfig{MaxDowntimeSeconds: 3 * 86400}}, "binlog_retention_short", "expire_logs_days"},
+		{nil, []*cdc.ConnectorResult{{Name: "a", Result: &cdc.Result{LagSeconds: map[string]float64{"orders": 8 * 86400}}}},
+			cfg, "binlog_retention_exceeded", "binlog_expire_logs_seconds"},
+	}
+	for _, c := range cases {
+		rep := ValidateServer(with(c.overrides), c.connectors, c.cfg)
+		if len(rep.Issues) != 1 {
+			t.Errorf("%v: expected one issue, got %v", c.overrides, rep.Issues)
+			continue
+		}
+		iss := rep.Issues[0]
+		if iss.Severity != SeverityForChange(c.kind) || !strings.HasPrefix(iss.Message, MessageForChange(c.kind, "", "", "", "")+": "+c.variable+"=") {
+			t.Errorf("%v: expected %s on %s, got %+v", c.overrides, c.kind, c.variable, iss)
+		}
+	}
+}
+
+func TestValidateServerReportsInspectionWarnings(t *testing.T) {
+	src := &source.InspectionResult{Warnings: []string{"cannot list binlogs (needs REPLICATION CLIENT)"}}
+	rep := ValidateServer(src, nil, &config.Config{})
+	if len(rep.Issues) != 1 || rep.Issues[0].Severity != SeverityWarn || !strings.Contains(rep.Issues[0].Message, "REPLICATION CLIENT") {
+		t.Fatalf("expected the inspection warning as a WARN issue, got %v", rep.Issues)
+	}
+}
+
+func TestValidateServerTableLagBoundsConnectorLag(t *testing.T) {
+	src := &source.InspectionResult{ServerVariables: map[string]string{
+		"log_bin": "ON", "binlog_format": "ROW", "binlog_row_image": "FULL", "binlog_row_metadata": "FULL",
+		"gtid_mode": "ON", "enforce_gtid_consistency": "ON", "binlog_expire_logs_seconds": "604800",
+	}}
+	// An idle table's newest change is old, but the connector has read a
+	// recent change of another table
+	connectors := []*cdc.ConnectorResult{{Name: "a", Result: &cdc.Result{LagSeconds: map[string]float64{"orders": 5, "archive": 30 * 86400}}}}
+	if rep := ValidateServer(src, connectors, &config.Config{}); len(rep.Issues) != 0 {
+		t.Fatalf("expected the freshest table to bound the connector lag, got %v", rep.Issues)
+	}
+}
@@ -13,14 +13,38 @@ const (
 )
 
 // Change kinds supported:
-// "column_added", "column_removed", "nullable_to_notnull", "type_changed", "cdc_schema_stale"
+// "column_added", "column_removed", "nullable_to_notnull", "type_widened", "type_narrowed",
+// "type_incompatible", "cdc_schema_stale",
+// "row_count_delta", "cdc_lag_exceeded", "configured_table_missing", "configured_pattern_unmatched",
+// "table_not_captured", "primary_key_mismatch", "cdc_key_mismatch", "cdc_history_incomplete",
+// "cdc_config_issue", "column_filtered", "charset_changed", "default_changed", "column_reordered",
+// "column_virtual_generated", "column_invisible", "enum_values_added", "enum_values_removed",
+// "enum_values_reordered", "unique_key_changed", "nullable_unique_key", "fk_parent_not_captured",
+// "binlog_config_incompatible", "binlog_config_risky", "binlog_config_suboptimal",
+// "binlog_retention_short", "binlog_retention_exceeded", "grant_missing", "table_select_missing",
+// "binlog_position_purged", "binlog_position_expiring", "table_idle", "cdc_stalled",
+// "heartbeat_disabled", "heartbeat_stale", "row_value_mismatch", "row_missing_in_cdc",
+// "row_extra_in_cdc", "sink_row_mismatch", "sink_row_missing", "sink_row_extra",
+// "sink_column_missing", "source_inspection_incomplete"
 func SeverityForChange(kind string) string {
 	switch kind {
-	case "column_removed", "nullable_to_notnull":
+	case "column_removed", "nullable_to_notnull", "configured_table_missing", "primary_key_mismatch", "cdc_key_mismatch",
+		"type_incompatible", "enum_values_removed",
+		"unique_key_changed", "binlog_config_incompatible", "binlog_retention_exceeded",
+		"grant_missing", "table_select_missing", "binlog_position_purged", "row_value_mismatch",
+		"sink_row_mismatch", "sink_row_missing":
 		return SeverityBlock
-	case "type_changed", "cdc_schema_stale", "cdc_snapshot_issue", "cdc_connector_unhealthy":
+	case "type_narrowed", "cdc_schema_stale", "cdc_snapshot_issue", "cdc_connector_unhealthy",
+		"row_count_delta", "cdc_lag_exceeded", "configured_pattern_unmatched", "table_not_captured",
+		"cdc_history_incomplete", "cdc_config_issue", "charset_changed", "enum_values_reordered",
+		"nullable_unique_key", "fk_parent_not_captured", "binlog_config_risky", "binlog_retention_short",
+		"binlog_position_expiring", "cdc_stalled", "heartbeat_disabled", "heartbeat_stale",
+		"row_missing_in_cdc", "row_extra_in_cdc", "sink_row_extra", "sink_column_missing",
+		"source_inspection_incomplete":
 		return SeverityWarn
-	case "column_added":
+	case "column_added", "column_filtered", "type_widened", "default_changed", "column_reordered",
+		"column_virtual_generated", "column_invisible", "enum_values_added", "binlog_config_suboptimal",
+		"table_idle":
 		return SeverityInfo
 	default:
 		return SeverityInfo
@@ -36,14 +60,102 @@ func MessageForChange(kind, table, column, from, to string) string {
 		return "present in CDC but missing in MySQL"
 	case "nullable_to_notnull":
 		return "nullable -> NOT NULL"
-	case "type_changed":
-		return "type mismatch"
+	case "type_widened":
+		return "type widened; every source value fits"
+	case "type_narrowed":
+		return "type narrowed; some source values may not fit"
+	case "type_incompatible":
+		return "type incompatible"
 	case "cdc_schema_stale":
 		return "CDC schema appears stale"
 	case "cdc_snapshot_issue":
 		return "Debezium snapshot mode disabled or inconsistent"
 	case "cdc_connector_unhealthy":
 		return "Debezium connector unhealthy"
+	case "row_count_delta":
+		return "row count delta exceeds tolerance"
+	case "cdc_lag_exceeded":
+		return "CDC lag exceeds tolerance"
+	case "configured_table_missing":
+		return "configured table does not exist in MySQL"
+	case "configured_pattern_unmatched":
+		return "configured table pattern matches no MySQL tables"
+	case "table_not_captured":
+		return "configured table is not captured by any connector"
+	case "primary_key_mismatch":
+		return "declared primary key differs from MySQL primary key"
+	case "cdc_key_mismatch":
+		return "CDC message key differs from expected primary key"
+	case "cdc_history_incomplete":
+		return "Debezium schema history incomplete"
+	case "column_filtered":
+		return "intentionally excluded or rewritten by the connector"
+	case "cdc_config_issue":
+		return "Debezium connector config uses ignored or deprecated keys"
+	case "charset_changed":
+		return "character set differs"
+	case "default_changed":
+		return "default value differs"
+	case "column_reordered":
+		return "ordinal position differs"
+	case "column_virtual_generated":
+		return "virtual generated column; not emitted in change events"
+	case "column_invisible":
+		return "invisible column absent from the CDC schema"
+	case "enum_values_added":
+		return "ENUM/SET values added, not yet in the CDC schema"
+	case "enum_values_removed":
+		return "ENUM/SET values removed from the source"
+	case "enum_values_reordered":
+		return "ENUM/SET values reordered or inserted, changing stored ordinals"
+	case "unique_key_changed":
+		return "CDC message key matches no unique key of a table without a primary key"
+	case "nullable_unique_key":
+		return "CDC message key is a unique key with nullable columns"
+	case "fk_parent_not_captured":
+		return "foreign key references a table no connector captures"
+	case "binlog_config_incompatible":
+		return "binlog setting incompatible with Debezium"
+	case "binlog_config_risky":
+		return "binlog setting risky for Debezium"
+	case "binlog_config_suboptimal":
+		return "binlog setting not recommended for Debezium"
+	case "binlog_retention_short":
+		return "binlog retention shorter than the allowed connector downtime"
+	case "binlog_retention_exceeded":
+		return "CDC lag exceeds binlog retention; the connector position may be purged"
+	case "binlog_position_purged":
+		return "connector offset points at binlog events the server has purged"
+	case "binlog_position_expiring":
+		return "connector offset is close to being purged from the binlog"
+	case "source_inspection_incomplete":
+		return "source inspection incomplete; dependent checks skipped"
+	case "table_idle":
+		return "no recent change events; table appears idle"
+	case "cdc_stalled":
+		return "no recent change events or heartbeats; connector appears stalled"
+	case "heartbeat_disabled":
+		return "connector heartbeats disabled while captured tables are rarely written"
+	case "heartbeat_stale":
+		return "connector heartbeats are stale"
+	case "row_value_mismatch":
+		return "source and CDC values differ"
+	case "row_missing_in_cdc":
+		return "source rows have no live change event in the CDC topic"
+	case "row_extra_in_cdc":
+		return "CDC topic has live keys for rows missing in the source"
+	case "sink_row_mismatch":
+		return "sink rows differ from the source"
+	case "sink_row_missing":
+		return "source rows missing in the sink"
+	case "sink_row_extra":
+		return "sink rows not in the source"
+	case "sink_column_missing":
+		return "source column missing in the sink"
+	case "grant_missing":
+		return "connector database user lacks privileges Debezium requires"
+	case "table_select_missing":
+		return "connector database user cannot SELECT captured table"
 	default:
 		return ""
 	}
@@ -0,0 +1,106 @@
+package drift
+
+import (
+	"fmt"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// ValidateTables checks the configured table list against the inspected
+// source tables and the combined capture set of all connectors. Unlike
+// Validate it is not connector-scoped: a table only needs to be captured by
+// one connector.
+func ValidateTables(
+	mysql *source.InspectionResult,
+	connectors []*cdc.ConnectorResult,
+	cfg *config.Config,
+) *Report {
+	report := &Report{}
+	if cfg == nil {
+		return report
+	}
+
+	mysqlTables := map[string]struct{}{}
+	for _, t := range mysql.Tables {
+		mysqlTables[t.Name] = struct{}{}
+	}
+
+	for _, tc := range cfg.Tables {
+		if tc.IsPattern() {
+			// A pattern whose only matches are excluded selects nothing
+			matched := false
+			for name := range mysqlTables {
+				if tc.Matches(name) && cfg.IncludesTable(name) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				report.Issues = append(report.Issues, Issue{
+					Severity: SeverityForChange("configured_pattern_unmatched"),
+					Message:  fmt.Sprintf("%s: %s", MessageForChange("configured_pattern_unmatched", "", "", "", ""), tc.Name),
+				})
+			}
+			continue
+		}
+		if !cfg.IncludesTable(tc.Name) {
+			// explicitly excluded
+			continue
+		}
+		if _, ok := mysqlTables[tc.Name]; !ok {
+			report.Issues = append(report.Issues, Issue{
+				Severity: SeverityForChange("configured_table_missing"),
+				Table:    tc.Name,
+				Message:  MessageForChange("configured_table_missing", tc.Name, "", "", ""),
+			})
+		}
+	}
+
+	for _, t := range mysql.Tables {
+		report.Issues = append(report.Issues, checkDeclaredKey(t, cfg.TableFor(t.Name))...)
+	}
+
+	// Capture coverage is only meaningful when at least one connector answered.
+	reachable := false
+	captured := map[string]struct{}{}
+	for _, cr := range connectors {
+		if cr.Result == nil || !cr.Result.ConnectorReachable {
+			continue
+		}
+		reachable = true
+		for _, t := range cr.Result.CapturedTables {
+			captured[t] = struct{}{}
+		}
+		for _, t := range cr.Result.CapturedOther {
+			captured[t] = struct{}{}
+		}
+	}
+	if !reachable {
+		return report
+	}
+	// Foreign key parents may be outside the configured tables
+	sourceTables := map[string]struct{}{}
+	for _, t := range mysql.Tables {
+		sourceTables[t.Name] = struct{}{}
+	}
+	for _, t := range mysql.OtherTables {
+		sourceTables[t] = struct{}{}
+	}
+	for _, t := range mysql.Tables {
+		if !cfg.IncludesTable(t.Name) {
+			continue
+		}
+		if _, ok := captured[t.Name]; !ok {
+			report.Issues = append(report.Issues, Issue{
+				Severity: SeverityForChange("table_not_captured"),
+				Table:    t.Name,
+				Message:  MessageForChange("table_not_captured", t.Name, "", "", ""),
+			})
+			continue
+		}
+		report.Issues = append(report.Issues, checkForeignKeys(t, captured, sourceTables)...)
+	}
+	return report
+}
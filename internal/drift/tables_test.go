@@ -0,0 +1,79 @@
+package drift
+
+import (
+	"testing"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+func TestValidateTables(t *testing.T) {
+	cfg := &config.Config{Tables: []config.TableConfig{
+		{Name: "users", PrimaryKey: []string{"id"}},
+		{Name: "orders", PrimaryKey: []string{"id"}},
+		{Name: "ghost", PrimaryKey: []string{"id"}},
+		{Name: "audit_*", PrimaryKey: []string{"id"}},
+	}}
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "users"}, {Name: "orders"}}}
+	connectors := []*cdc.ConnectorResult{
+		{Name: "a", Result: &cdc.Result{ConnectorReachable: true, CapturedTables: []string{"users"}}},
+	}
+	rep := ValidateTables(mysql, connectors, cfg)
+
+	kinds := map[string]string{}
+	for _, iss := range rep.Issues {
+		kinds[iss.Table+"|"+iss.Severity] = iss.Message
+	}
+	if _, ok := kinds["ghost|"+SeverityForChange("configured_table_missing")]; !ok {
+		t.Errorf("expected configured_table_missing for ghost, got %v", rep.Issues)
+	}
+	if _, ok := kinds["orders|"+SeverityForChange("table_not_captured")]; !ok {
+		t.Errorf("expected table_not_captured for orders, got %v", rep.Issues)
+	}
+	if _, ok := kinds["|"+SeverityForChange("configured_pattern_unmatched")]; !ok {
+		t.Errorf("expected configured_pattern_unmatched for audit_*, got %v", rep.Issues)
+	}
+	if len(rep.Issues) != 3 {
+		t.Fatalf("expected 3 issues, got %d: %v", len(rep.Issues), rep.Issues)
+	}
+}
+
+func TestValidateTablesAppliesExcludes(t *testing.T) {
+	cfg := &config.Config{
+		Tables: []config.TableConfig{
+			{Name: "orders", PrimaryKey: []string{"id"}},
+			{Name: "audit_*", PrimaryKey: []string{"id"}},
+		},
+		ExcludeTables: []string{"orders", "audit_*"},
+	}
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "orders"}, {Name: "audit_2024"}}}
+	connectors := []*cdc.ConnectorResult{
+		{Name: "a", Result: &cdc.Result{ConnectorReachable: true}},
+	}
+	rep := ValidateTables(mysql, connectors, cfg)
+	var unmatched bool
+	for _, iss := range rep.Issues {
+		if iss.Table != "" {
+			t.Errorf("expected no issue for excluded table %s, got %v", iss.Table, iss)
+		}
+		if iss.Message == MessageForChange("configured_pattern_unmatched", "", "", "", "")+": audit_*" {
+			unmatched = true
+		}
+	}
+	if !unmatched {
+		t.Fatalf("expected audit_* to be unmatched once its matches are excluded, got %v", rep.Issues)
+	}
+}
+
+func TestValidateSkipsTablesOutsideScope(t *testing.T) {
+	cfg := &config.Config{Tables: []config.TableConfig{{Name: "users", PrimaryKey: []string{"id"}}}}
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "users", PrimaryKey: []string{"id"}}}}
+	cdcRes := &cdc.Result{CapturedTables: []string{"users", "payments"}}
+	rep := ValidateWithOptions(mysql, cdcRes, Options{Config: cfg})
+	for _, iss := range rep.Issues {
+		if iss.Table == "payments" {
+			t.Fatalf("did not expect issues for out-of-scope table payments, got %v", rep.Issues)
+		}
+	}
+}
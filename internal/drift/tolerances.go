@@ -0,0 +1,61 @@
+package drift
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// checkTolerances compares source row counts with live CDC keys, CDC lag and the age of the
+// newest change event for a single table against the configured tolerances.
+// Checks are skipped when either the tolerance or the CDC measurement is
+// absent.
+func checkTolerances(table source.TableInfo, cdcResult *cdc.Result, tol config.Tolerances, cdcCfg config.CDCConfig) []Issue {
+	var issues []Issue
+
+	if tol.RowCountPct != nil {
+		// Only the live keys of a compacted topic count rows. Retained events
+		// include every update, delete and tombstone, so they are not
+		// compared.
+		if keys, ok := cdcResult.KeyCounts[table.Name]; ok {
+			delta := rowCountDeltaPct(table.RowCount, keys)
+			if delta > *tol.RowCountPct {
+				issues = append(issues, Issue{
+					Severity: SeverityForChange("row_count_delta"),
+					Table:    table.Name,
+					Message:  fmt.Sprintf("%s (MySQL rows: %d, CDC keys: %d, delta %.2f%% > %.2f%%)", MessageForChange("row_count_delta", table.Name, "", "", ""), table.RowCount, keys, delta, *tol.RowCountPct),
+				})
+			}
+		}
+	}
+
+	if tol.MaxLagSeconds != nil {
+		// A table whose newest event is itself older than the limit is
+		// reported by checkFreshness as idle or stalled; its lag only says
+		// how long ago its last change was. The connector's offset lag is
+		// checked once by checkReplicationLag.
+		freshness := checkFreshness(table.Name, cdcResult, *tol.MaxLagSeconds, cdcCfg, time.Now())
+		if lag, ok := cdcResult.LagSeconds[table.Name]; ok && lag > *tol.MaxLagSeconds && len(freshness) == 0 {
+			issues = append(issues, Issue{
+				Severity: SeverityForChange("cdc_lag_exceeded"),
+				Table:    table.Name,
+				Message:  fmt.Sprintf("%s (newest event changed %.0fs ago > %.0fs)", MessageForChange("cdc_lag_exceeded", table.Name, "", "", ""), lag, *tol.MaxLagSeconds),
+			})
+		}
+		issues = append(issues, freshness...)
+	}
+
+	return issues
+}
+
+// rowCountDeltaPct returns the absolute difference between source rows and CDC
+// keys as a percentage of the source row count. An empty source table is
+// treated as having one row so that any CDC keys still register as drift.
+func rowCountDeltaPct(sourceRows, cdcKeys int64) float64 {
+	base := math.Max(float64(sourceRows), 1)
+	return math.Abs(float64(sourceRows-cdcKeys)) / base * 100
+}
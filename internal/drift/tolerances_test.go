@@ -0,0 +1,139 @@
+package drift
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+func TestRowCountAndLagTolerances(t *testing.T) {
+	pct := 1.0
+	lag := 60.0
+	override := 600.0
+	cfg := &config.Config{
+		Tables: []config.TableConfig{
+			{Name: "t1", PrimaryKey: []string{"a"}},
+			{Name: "t2", PrimaryKey: []string{"a"}, Tolerances: &config.Tolerances{MaxLagSeconds: &override}},
+		},
+		Tolerances: config.Tolerances{RowCountPct: &pct, MaxLagSeconds: &lag},
+	}
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{
+		{Name: "t1", PrimaryKey: []string{"a"}, RowCount: 1000},
+		{Name: "t2", PrimaryKey: []string{"a"}, RowCount: 1000},
+	}}
+	cdcRes := &cdc.Result{
+		CapturedTables: []string{"t1", "t2"},
+		KeyCounts:      map[string]int64{"t1": 900, "t2": 995},
+		LagSeconds:     map[string]float64{"t1": 120, "t2": 120},
+	}
+	rep := ValidateWithOptions(mysql, cdcRes, Options{Config: cfg})
+
+	count := map[string]int{}
+	for _, iss := range rep.Issues {
+		if strings.HasPrefix(iss.Message, MessageForChange("row_count_delta", "", "", "", "")) {
+			count["row:"+iss.Table]++
+		}
+		if strings.HasPrefix(iss.Message, MessageForChange("cdc_lag_exceeded", "", "", "", "")) {
+			count["lag:"+iss.Table]++
+		}
+	}
+	if count["row:t1"] != 1 || count["row:t2"] != 0 {
+		t.Fatalf("expected a row count issue for t1 only, got %v: %v", count, rep.Issues)
+	}
+	if count["lag:t1"] != 1 || count["lag:t2"] != 0 {
+		t.Fatalf("expected a lag issue for t1 only (t2 overrides maxLagSeconds), got %v: %v", count, rep.Issues)
+	}
+}
+
+func TestTolerancesSkippedWithoutConfig(t *testing.T) {
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "t1", PrimaryKey: []string{"a"}, RowCount: 1000}}}
+	cdcRes := &cdc.Result{CapturedTables: []string{"t1"}, EventCounts: map[string]int64{"t1": 1}}
+	rep := Validate(mysql, cdcRes)
+	for _, iss := range rep.Issues {
+		if iss.Severity != SeverityInfo {
+			t.Fatalf("did not expect non-INFO issues without tolerances, got %v", rep.Issues)
+		}
+	}
+}
+
+func TestRowCountComparesLiveKeyCountsOnly(t *testing.T) {
+	pct := 1.0
+	cfg := &config.Config{
+		Tables:     []config.TableConfig{{Name: "t1", PrimaryKey: []string{"a"}}},
+		Tolerances: config.Tolerances{RowCountPct: &pct},
+	}
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "t1", PrimaryKey: []string{"a"}, RowCount: 1000}}}
+	// Updates inflate the event count; without live keys nothing is compared
+	cdcRes := &cdc.Result{
+		CapturedTables: []string{"t1"},
+		EventCounts:    map[string]int64{"t1": 4200},
+	}
+	for _, iss := range ValidateWithOptions(mysql, cdcRes, Options{Config: cfg}).Issues {
+		if strings.HasPrefix(iss.Message, MessageForChange("row_count_delta", "", "", "", "")) {
+			t.Fatalf("expected raw event counts not to be compared, got %v", iss)
+		}
+	}
+	cdcRes.KeyCounts = map[string]int64{"t1": 1000}
+	for _, iss := range ValidateWithOptions(mysql, cdcRes, Options{Config: cfg}).Issues {
+		if strings.HasPrefix(iss.Message, MessageForChange("row_count_delta", "", "", "", "")) {
+			t.Fatalf("expected live keys to be compared, got %v", iss)
+		}
+	}
+	cdcRes.KeyCounts["t1"] = 800
+	rep := ValidateWithOptions(mysql, cdcRes, Options{Config: cfg})
+	found := false
+	for _, iss := range rep.Issues {
+		found = found || strings.Contains(iss.Message, "CDC keys: 800")
+	}
+	if !found {
+		t.Fatalf("expected a row count issue against the live keys, got %v", rep.Issues)
+	}
+}
+
+func TestConnectorLagReportedOnce(t *testing.T) {
+	lag := 60.0
+	cfg := &config.Config{
+		Tables:     []config.TableConfig{{Name: "t1", PrimaryKey: []string{"a"}}, {Name: "t2", PrimaryKey: []string{"a"}}},
+		Tolerances: config.Tolerances{MaxLagSeconds: &lag},
+	}
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "t1", PrimaryKey: []string{"a"}}, {Name: "t2", PrimaryKey: []string{"a"}}}}
+	// A stalled connector: its offset is an hour old, whatever the tables report
+	stalled := 3600.0
+	cdcRes := &cdc.Result{
+		CapturedTables: []string{"t1", "t2"},
+		LagSeconds:     map[string]float64{"t1": 1, "t2": 1},
+		Lag:            &cdc.ReplicationLag{Seconds: &stalled},
+	}
+	var lagIssues []Issue
+	for _, iss := range ValidateWithOptions(mysql, cdcRes, Options{Config: cfg}).Issues {
+		if strings.HasPrefix(iss.Message, MessageForChange("cdc_lag_exceeded", "", "", "", "")) {
+			lagIssues = append(lagIssues, iss)
+		}
+	}
+	if len(lagIssues) != 1 || lagIssues[0].Table != "" || !strings.Contains(lagIssues[0].Message, "3600s behind > 60s") {
+		t.Fatalf("expected one connector-level lag issue, got %v", lagIssues)
+	}
+}
+
+func TestTableLagOfIdleTableIsFreshness(t *testing.T) {
+	lag := 60.0
+	cfg := &config.Config{
+		Tables:     []config.TableConfig{{Name: "t1", PrimaryKey: []string{"a"}}},
+		Tolerances: config.Tolerances{MaxLagSeconds: &lag},
+	}
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "t1", PrimaryKey: []string{"a"}}}}
+	cdcRes := &cdc.Result{
+		CapturedTables: []string{"t1"},
+		LastEventTimes: map[string]time.Time{"t1": time.Now().Add(-time.Hour)},
+		LagSeconds:     map[string]float64{"t1": 3600},
+	}
+	for _, iss := range ValidateWithOptions(mysql, cdcRes, Options{Config: cfg}).Issues {
+		if strings.HasPrefix(iss.Message, MessageForChange("cdc_lag_exceeded", "", "", "", "")) {
+			t.Fatalf("expected an idle table to be reported by the freshness check only, got %v", iss)
+		}
+	}
+}
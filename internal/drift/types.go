@@ -0,0 +1,462 @@
+package drift
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// Type families. Types in the same family differ only in size or precision;
+// conversions between families are classified case by case.
+const (
+	famInt       = "int"
+	famBool      = "bool"
+	famBit       = "bit"
+	famDecimal   = "decimal"
+	famFloat     = "float"
+	famString    = "string"
+	famBinary    = "binary"
+	famDate      = "date"
+	famTime      = "time"
+	famDatetime  = "datetime"  // wall-clock date and time
+	famTimestamp = "timestamp" // instant, stored or emitted in UTC
+	famYear      = "year"
+	famJSON      = "json"
+	famEnum      = "enum"
+	famSet       = "set"
+	famUUID      = "uuid"
+	famGeometry  = "geometry"
+)
+
+// Results of comparing a source type with a CDC type. typeEquivalent means
+// no issue; the others are change kinds for SeverityForChange.
+const (
+	typeEquivalent   = ""
+	typeWidened      = "type_widened"
+	typeNarrowed     = "type_narrowed"
+	typeIncompatible = "type_incompatible"
+)
+
+// normType is a type reduced to what matters for value compatibility. Zero
+// sizes and precisions mean unknown and compare equal to anything.
+type normType struct {
+	family    string
+	name      string // original base type, for types outside the known families
+	size      int    // int and float: bytes; string and binary: max length; bit: bits
+	unsigned  bool
+	width     int // display width; only used to recognize TINYINT(1)
+	precision int // decimal
+	scale     int // decimal
+	fsp       int // fractional seconds digits for time types
+}
+
+// parseSQLType splits a type such as "decimal(10,2) unsigned zerofill" into
+// its lowercased base name, numeric arguments and signedness.
+func parseSQLType(t string) (base string, args []int, unsigned bool) {
+	s := strings.ToLower(strings.TrimSpace(t))
+	if i := strings.Index(s, "("); i >= 0 {
+		if j := strings.Index(s[i:], ")"); j >= 0 {
+			for _, a := range strings.Split(s[i+1:i+j], ",") {
+				if n, err := strconv.Atoi(strings.TrimSpace(a)); err == nil {
+					args = append(args, n)
+				}
+			}
+			s = s[:i] + " " + s[i+j+1:]
+		}
+	}
+	var words []string
+	for _, w := range strings.Fields(s) {
+		switch w {
+		case "unsigned":
+			unsigned = true
+		case "signed", "zerofill":
+		default:
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, " "), args, unsigned
+}
+
+// normalizeSQLType normalizes a MySQL COLUMN_TYPE or Postgres data type.
+// length and scale, when non-nil, stand in for arguments missing from t.
+func normalizeSQLType(t string, length, scale *int) normType {
+	base, args, unsigned := parseSQLType(t)
+	arg := func(i int, fallback *int) int {
+		if i < len(args) {
+			return args[i]
+		}
+		if fallback != nil {
+			return *fallback
+		}
+		return 0
+	}
+	n := normType{name: base, unsigned: unsigned}
+	switch base {
+	case "tinyint":
+		n.family, n.size, n.width = famInt, 1, arg(0, length)
+	case "smallint", "int2", "smallserial":
+		n.family, n.size = famInt, 2
+	case "mediumint":
+		n.family, n.size = famInt, 3
+	case "int", "integer", "int4", "serial":
+		n.family, n.size = famInt, 4
+	case "bigint", "int8", "bigserial":
+		n.family, n.size = famInt, 8
+	case "bool", "boolean":
+		n.family = famBool
+	case "bit":
+		n.family, n.size = famBit, arg(0, length)
+		if n.size == 0 {
+			n.size = 1
+		}
+	case "decimal", "numeric", "dec", "fixed":
+		n.family, n.precision, n.scale = famDecimal, arg(0, length), arg(1, scale)
+	case "float", "float4", "real":
+		// FLOAT(p) with p > 24 is a DOUBLE
+		n.family, n.size = famFloat, 4
+		if p := arg(0, nil); p > 24 {
+			n.size = 8
+		}
+	case "double", "double precision", "float8":
+		n.family, n.size = famFloat, 8
+	case "char", "character", "nchar", "varchar", "character varying", "nvarchar", "bpchar":
+		n.family, n.size = famString, arg(0, length)
+	case "tinytext":
+		n.family, n.size = famString, 255
+	case "text":
+		n.family, n.size = famString, 65535
+	case "mediumtext":
+		n.family, n.size = famString, 16777215
+	case "longtext":
+		n.family, n.size = famString, 4294967295
+	case "binary", "varbinary":
+		n.family, n.size = famBinary, arg(0, length)
+	case "tinyblob":
+		n.family, n.size = famBinary, 255
+	case "blob":
+		n.family, n.size = famBinary, 65535
+	case "mediumblob":
+		n.family, n.size = famBinary, 16777215
+	case "longblob":
+		n.family, n.size = famBinary, 4294967295
+	case "bytea":
+		n.family = famBinary
+	case "date":
+		n.family = famDate
+	case "time":
+		n.family, n.fsp = famTime, arg(0, length)
+	case "datetime":
+		n.family, n.fsp = famDatetime, arg(0, length)
+	case "timestamp":
+		n.family, n.fsp = famTimestamp, arg(0, length)
+	case "time without time zone":
+		n.family, n.fsp = famTime, 6
+	case "timestamp without time zone":
+		n.family, n.fsp = famDatetime, 6
+	case "timestamp with time zone", "timestamptz":
+		n.family, n.fsp = famTimestamp, 6
+	case "year":
+		n.family = famYear
+	case "json", "jsonb":
+		n.family = famJSON
+	case "enum":
+		n.family = famEnum
+	case "set":
+		n.family = famSet
+	case "uuid":
+		n.family = famUUID
+	case "geometry", "point", "linestring", "polygon", "multipoint", "multilinestring",
+		"multipolygon", "geometrycollection", "geomcollection":
+		n.family = famGeometry
+	}
+	return n
+}
+
+// connectPrimitives are the Kafka Connect schema types Debezium emits.
+var connectPrimitives = map[string]normType{
+	"int8":    {family: famInt, size: 1},
+	"int16":   {family: famInt, size: 2},
+	"int32":   {family: famInt, size: 4},
+	"int64":   {family: famInt, size: 8},
+	"float32": {family: famFloat, size: 4},
+	"float64": {family: famFloat, size: 8},
+	"boolean": {family: famBool},
+	"string":  {family: famString},
+	"bytes":   {family: famBinary},
+}
+
+// connectLogical are the Debezium and Kafka Connect semantic types.
+var connectLogical = map[string]normType{
+	"io.debezium.time.Date":                   {family: famDate},
+	"org.apache.kafka.connect.data.Date":      {family: famDate},
+	"io.debezium.time.Time":                   {family: famTime, fsp: 3},
+	"io.debezium.time.MicroTime":              {family: famTime, fsp: 6},
+	"io.debezium.time.NanoTime":               {family: famTime, fsp: 9},
+	"org.apache.kafka.connect.data.Time":      {family: famTime, fsp: 3},
+	"io.debezium.time.Timestamp":              {family: famDatetime, fsp: 3},
+	"io.debezium.time.MicroTimestamp":         {family: famDatetime, fsp: 6},
+	"io.debezium.time.NanoTimestamp":          {family: famDatetime, fsp: 9},
+	"org.apache.kafka.connect.data.Timestamp": {family: famDatetime, fsp: 3},
+	"io.debezium.time.ZonedTimestamp":         {family: famTimestamp, fsp: 6},
+	"io.debezium.time.Year":                   {family: famYear},
+	"org.apache.kafka.connect.data.Decimal":   {family: famDecimal},
+	"io.debezium.data.VariableScaleDecimal":   {family: famDecimal},
+	"io.debezium.data.Json":                   {family: famJSON},
+	"io.debezium.data.Enum":                   {family: famEnum},
+	"io.debezium.data.EnumSet":                {family: famSet},
+	"io.debezium.data.Bits":                   {family: famBit},
+	"io.debezium.data.Uuid":                   {family: famUUID},
+	"io.debezium.data.geometry.Geometry":      {family: famGeometry},
+	"io.debezium.data.geometry.Point":         {family: famGeometry},
+}
+
+// normalizeConnectType normalizes a Kafka Connect or Debezium semantic type
+// name, reporting false when t is not one.
+func normalizeConnectType(t string) (normType, bool) {
+	t = strings.TrimSpace(t)
+	if n, ok := connectLogical[t]; ok {
+		n.name = t
+		return n, true
+	}
+	if n, ok := connectPrimitives[t]; ok {
+		n.name = t
+		return n, true
+	}
+	return normType{}, false
+}
+
+// emittedType is the Connect type Debezium emits for a source type under the
+// connector's settings.
+func emittedType(src normType, settings map[string]string) normType {
+	mode := func(key, def string) string {
+		if v := strings.ToLower(strings.TrimSpace(settings[key])); v != "" {
+			return v
+		}
+		return def
+	}
+	switch src.family {
+	case famInt:
+		switch {
+		case src.size == 1, src.size == 2 && !src.unsigned:
+			return connectPrimitives["int16"]
+		case src.size <= 3, src.size == 4 && !src.unsigned:
+			return connectPrimitives["int32"]
+		case src.size == 8 && src.unsigned && mode("bigint.unsigned.handling.mode", "long") == "precise":
+			return normType{family: famDecimal, precision: 20}
+		default:
+			return connectPrimitives["int64"]
+		}
+	case famDecimal:
+		switch mode("decimal.handling.mode", "precise") {
+		case "double":
+			return connectPrimitives["float64"]
+		case "string":
+			return connectPrimitives["string"]
+		}
+		return normType{family: famDecimal, precision: src.precision, scale: src.scale}
+	case famFloat:
+		return normType{family: famFloat, size: src.size}
+	case famTime, famDatetime:
+		switch mode("time.precision.mode", "adaptive") {
+		case "connect":
+			return normType{family: src.family, fsp: 3}
+		case "adaptive_time_microseconds":
+			if src.family == famTime {
+				return normType{family: famTime, fsp: 6}
+			}
+		}
+		if src.fsp <= 3 {
+			return normType{family: src.family, fsp: 3}
+		}
+		return normType{family: src.family, fsp: 6}
+	case famTimestamp:
+		return connectLogical["io.debezium.time.ZonedTimestamp"]
+	case famBit:
+		if src.size == 1 {
+			return connectPrimitives["boolean"]
+		}
+		return normType{family: famBit, size: src.size}
+	case famBinary:
+		if m := mode("binary.handling.mode", "bytes"); m != "bytes" {
+			return connectPrimitives["string"]
+		}
+		return connectPrimitives["bytes"]
+	case famString:
+		return connectPrimitives["string"]
+	}
+	return normType{family: src.family}
+}
+
+// compareColumnTypes classifies the difference between a source column type
+// and the type the CDC schema records for it. CDC types that are Connect or
+// Debezium semantic types are compared with the type Debezium would emit for
+// the source type under the connector's settings.
+func compareColumnTypes(mcol source.ColumnInfo, ccol cdc.ColumnInfo, settings map[string]string) string {
+	// Sources that report only a base type, like Postgres, carry length and
+	// precision separately
+	length := mcol.Length
+	if length == nil {
+		length = mcol.Precision
+	}
+	src := normalizeSQLType(mcol.Type, length, mcol.Scale)
+	if mcol.Unsigned {
+		src.unsigned = true
+	}
+	if dst, ok := normalizeConnectType(ccol.Type); ok {
+		if isBoolLike(src) && dst.family == famBool {
+			return typeEquivalent
+		}
+		return classifyTypes(emittedType(src, settings), dst)
+	}
+	return classifyTypes(src, normalizeSQLType(ccol.Type, ccol.Length, ccol.Scale))
+}
+
+// isBoolLike reports whether values of n are booleans: BOOLEAN, TINYINT(1)
+// (which MySQL uses for BOOLEAN) and BIT(1).
+func isBoolLike(n normType) bool {
+	return n.family == famBool ||
+		(n.family == famInt && n.size == 1 && n.width == 1) ||
+		(n.family == famBit && n.size == 1)
+}
+
+// classifyTypes classifies converting values of type from to type to.
+func classifyTypes(from, to normType) string {
+	if isBoolLike(from) && isBoolLike(to) {
+		return typeEquivalent
+	}
+	if from.family == to.family {
+		return classifyWithinFamily(from, to)
+	}
+	switch {
+	case from.family == famBool && to.family == famInt:
+		return typeWidened
+	case from.family == famInt && to.family == famDecimal:
+		if to.precision == 0 || to.precision-to.scale >= intDigits(from) {
+			return typeWidened
+		}
+		return typeNarrowed
+	case from.family == famInt && to.family == famFloat:
+		if intBits(from) <= floatMantissa(to) {
+			return typeWidened
+		}
+		return typeNarrowed
+	case from.family == famYear && to.family == famInt:
+		if intBits(to) >= 12 { // years up to 2155
+			return typeWidened
+		}
+		return typeNarrowed
+	case from.family == famDate && (to.family == famDatetime || to.family == famTimestamp):
+		return typeWidened
+	case from.family == famTimestamp && to.family == famDatetime:
+		return classifyOrder(from.fsp, to.fsp, typeWidened)
+	case from.family == famBit && to.family == famBinary:
+		return typeWidened
+	case (from.family == famEnum || from.family == famSet || from.family == famJSON || from.family == famUUID) && to.family == famString:
+		return typeWidened
+	case isNumeric(from) && isNumeric(to),
+		isDateLike(from) && isDateLike(to),
+		from.family == famInt && to.family == famYear,
+		from.family == famBinary && to.family == famBit,
+		from.family == famString && (to.family == famEnum || to.family == famSet || to.family == famJSON || to.family == famUUID):
+		// the reverse of a widening, or a lossy numeric or date conversion
+		return typeNarrowed
+	}
+	return typeIncompatible
+}
+
+func classifyWithinFamily(from, to normType) string {
+	switch from.family {
+	case famInt:
+		fromBits, toBits := intBits(from), intBits(to)
+		switch {
+		case fromBits == toBits && from.unsigned == to.unsigned:
+			return typeEquivalent
+		case toBits >= fromBits && (!to.unsigned || from.unsigned):
+			return typeWidened
+		}
+		return typeNarrowed
+	case famDecimal:
+		if from.precision == 0 || to.precision == 0 {
+			return typeEquivalent
+		}
+		fromInt, toInt := from.precision-from.scale, to.precision-to.scale
+		switch {
+		case fromInt == toInt && from.scale == to.scale:
+			return typeEquivalent
+		case toInt >= fromInt && to.scale >= from.scale:
+			return typeWidened
+		}
+		return typeNarrowed
+	case famFloat, famString, famBinary, famBit:
+		if from.size == 0 || to.size == 0 {
+			return typeEquivalent
+		}
+		return classifyOrder(from.size, to.size, typeEquivalent)
+	case famTime, famDatetime, famTimestamp:
+		return classifyOrder(from.fsp, to.fsp, typeEquivalent)
+	case famEnum, famSet, famJSON, famUUID, famGeometry, famDate, famYear, famBool:
+		return typeEquivalent
+	}
+	if from.name == to.name {
+		return typeEquivalent
+	}
+	return typeIncompatible
+}
+
+// classifyOrder compares sizes: a larger target widens, a smaller one
+// narrows, and an equal one yields same.
+func classifyOrder(from, to int, same string) string {
+	switch {
+	case to > from:
+		return typeWidened
+	case to < from:
+		return typeNarrowed
+	}
+	return same
+}
+
+func isNumeric(n normType) bool {
+	return n.family == famInt || n.family == famDecimal || n.family == famFloat || n.family == famBool
+}
+
+func isDateLike(n normType) bool {
+	return n.family == famDate || n.family == famDatetime || n.family == famTimestamp
+}
+
+// intBits is the number of magnitude bits of an integer type.
+func intBits(n normType) int {
+	if n.unsigned {
+		return n.size * 8
+	}
+	return n.size*8 - 1
+}
+
+// intDigits is the number of decimal digits of an integer type's maximum.
+func intDigits(n normType) int {
+	bits := intBits(n)
+	if bits >= 64 {
+		return len(strconv.FormatUint(^uint64(0), 10))
+	}
+	return len(strconv.FormatUint(uint64(1)<<bits-1, 10))
+}
+
+func floatMantissa(n normType) int {
+	if n.size == 4 {
+		return 24
+	}
+	return 53
+}
+
+// cdcTypeString formats a CDC column type with its length and scale.
+func cdcTypeString(c cdc.ColumnInfo) string {
+	switch {
+	case c.Length != nil && c.Scale != nil:
+		return fmt.Sprintf("%s(%d,%d)", c.Type, *c.Length, *c.Scale)
+	case c.Length != nil:
+		return fmt.Sprintf("%s(%d)", c.Type, *c.Length)
+	}
+	return c.Type
+}
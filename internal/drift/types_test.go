@@ -0,0 +1,88 @@
+package drift
+
+import (
+	"testing"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestCompareColumnTypes(t *testing.T) {
+	cases := []struct {
+		source   string
+		cdc      cdc.ColumnInfo
+		settings map[string]string
+		want     string
+	}{
+		// display widths and aliases
+		{"int", cdc.ColumnInfo{Type: "INT", Length: intPtr(11)}, nil, typeEquivalent},
+		{"int(11)", cdc.ColumnInfo{Type: "INTEGER"}, nil, typeEquivalent},
+		{"tinyint(1)", cdc.ColumnInfo{Type: "BOOLEAN"}, nil, typeEquivalent},
+		{"tinyint(1)", cdc.ColumnInfo{Type: "boolean"}, nil, typeEquivalent},
+		{"bit(1)", cdc.ColumnInfo{Type: "boolean"}, nil, typeEquivalent},
+		{"decimal(10,2)", cdc.ColumnInfo{Type: "DECIMAL", Length: intPtr(10), Scale: intPtr(2)}, nil, typeEquivalent},
+		{"varchar(255)", cdc.ColumnInfo{Type: "VARCHAR", Length: intPtr(255)}, nil, typeEquivalent},
+		{"double precision", cdc.ColumnInfo{Type: "DOUBLE"}, nil, typeEquivalent},
+
+		// Connect and Debezium semantic types
+		{"int", cdc.ColumnInfo{Type: "int32"}, nil, typeEquivalent},
+		{"smallint", cdc.ColumnInfo{Type: "int16"}, nil, typeEquivalent},
+		{"int unsigned", cdc.ColumnInfo{Type: "int64"}, nil, typeEquivalent},
+		{"int unsigned", cdc.ColumnInfo{Type: "int32"}, nil, typeNarrowed},
+		{"datetime", cdc.ColumnInfo{Type: "io.debezium.time.Timestamp"}, nil, typeEquivalent},
+		{"datetime(6)", cdc.ColumnInfo{Type: "io.debezium.time.MicroTimestamp"}, nil, typeEquivalent},
+		{"datetime(6)", cdc.ColumnInfo{Type: "io.debezium.time.Timestamp"}, nil, typeNarrowed},
+		{"datetime(6)", cdc.ColumnInfo{Type: "org.apache.kafka.connect.data.Timestamp"}, map[string]string{"time.precision.mode": "connect"}, typeEquivalent},
+		{"timestamp", cdc.ColumnInfo{Type: "io.debezium.time.ZonedTimestamp"}, nil, typeEquivalent},
+		{"decimal(10,2)", cdc.ColumnInfo{Type: "org.apache.kafka.connect.data.Decimal"}, nil, typeEquivalent},
+		{"decimal(10,2)", cdc.ColumnInfo{Type: "float64"}, map[string]string{"decimal.handling.mode": "double"}, typeEquivalent},
+		{"decimal(10,2)", cdc.ColumnInfo{Type: "float64"}, nil, typeNarrowed},
+		{"decimal(10,2)", cdc.ColumnInfo{Type: "string"}, map[string]string{"decimal.handling.mode": "string"}, typeEquivalent},
+		{"bigint unsigned", cdc.ColumnInfo{Type: "int64"}, nil, typeEquivalent},
+		{"bigint unsigned", cdc.ColumnInfo{Type: "org.apache.kafka.connect.data.Decimal"}, map[string]string{"bigint.unsigned.handling.mode": "precise"}, typeEquivalent},
+		{"varbinary(16)", cdc.ColumnInfo{Type: "string"}, map[string]string{"binary.handling.mode": "base64"}, typeEquivalent},
+		{"json", cdc.ColumnInfo{Type: "io.debezium.data.Json"}, nil, typeEquivalent},
+
+		// widening, narrowing and incompatible changes
+		{"int", cdc.ColumnInfo{Type: "BIGINT"}, nil, typeWidened},
+		{"bigint", cdc.ColumnInfo{Type: "INT"}, nil, typeNarrowed},
+		{"int unsigned", cdc.ColumnInfo{Type: "INT"}, nil, typeNarrowed},
+		{"varchar(100)", cdc.ColumnInfo{Type: "VARCHAR", Length: intPtr(255)}, nil, typeWidened},
+		{"varchar(255)", cdc.ColumnInfo{Type: "VARCHAR", Length: intPtr(100)}, nil, typeNarrowed},
+		{"decimal(10,2)", cdc.ColumnInfo{Type: "DECIMAL", Length: intPtr(12), Scale: intPtr(2)}, nil, typeWidened},
+		{"decimal(10,2)", cdc.ColumnInfo{Type: "DECIMAL", Length: intPtr(10), Scale: intPtr(4)}, nil, typeNarrowed},
+		{"int", cdc.ColumnInfo{Type: "DECIMAL", Length: intPtr(10)}, nil, typeWidened},
+		{"bigint", cdc.ColumnInfo{Type: "DOUBLE"}, nil, typeNarrowed},
+		{"date", cdc.ColumnInfo{Type: "DATETIME"}, nil, typeWidened},
+		{"datetime", cdc.ColumnInfo{Type: "TIMESTAMP"}, nil, typeNarrowed},
+		{"enum('a','b')", cdc.ColumnInfo{Type: "VARCHAR", Length: intPtr(10)}, nil, typeWidened},
+		{"int", cdc.ColumnInfo{Type: "VARCHAR"}, nil, typeIncompatible},
+		{"datetime", cdc.ColumnInfo{Type: "int64"}, nil, typeIncompatible},
+	}
+	for _, c := range cases {
+		if got := compareColumnTypes(source.ColumnInfo{Type: c.source}, c.cdc, c.settings); got != c.want {
+			t.Errorf("%s -> %s (%v): got %q, want %q", c.source, cdcTypeString(c.cdc), c.settings, got, c.want)
+		}
+	}
+}
+
+func TestEquivalentTypesAreNotDrift(t *testing.T) {
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "t1", Columns: []source.ColumnInfo{
+		{Name: "id", Type: "int(11)"},
+		{Name: "active", Type: "tinyint(1)"},
+		{Name: "created_at", Type: "datetime"},
+	}}}}
+	cdcRes := &cdc.Result{CapturedTables: []string{"t1"}, TableSchemas: map[string]cdc.TableSchema{"t1": {Columns: map[string]cdc.ColumnInfo{
+		"id":         {Type: "INT"},
+		"active":     {Type: "BOOLEAN"},
+		"created_at": {Type: "io.debezium.time.Timestamp"},
+	}}}}
+	rep := Validate(mysql, cdcRes)
+	for _, iss := range rep.Issues {
+		if iss.FromType != "" {
+			t.Fatalf("expected no type issues, got %v", iss)
+		}
+	}
+}
@@ -2,10 +2,10 @@ package drift
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
 	"github.com/alexanderjulianmartinez/data-watch/internal/source"
 )
 
@@ -32,9 +32,31 @@ func (r *Report) BlockingCount() int {
 	return count
 }
 
+// Options carries the optional, config-driven inputs to ValidateWithOptions.
+type Options struct {
+	// Config supplies the table scope and per-table tolerances. May be nil,
+	// in which case every captured table is validated, the tolerance checks
+	// are skipped and source tables the connector does not capture are
+	// noted, as ValidateTables needs a config to report them.
+	Config *config.Config
+	// Grants are the source privileges of the account the connector connects
+	// as. May be nil when unknown, in which case the privilege checks are
+	// skipped.
+	Grants *source.Grants
+}
+
 func Validate(
 	mysql *source.InspectionResult,
 	cdcResult *cdc.Result,
+) *Report {
+	return ValidateWithOptions(mysql, cdcResult, Options{})
+}
+
+// ValidateWithOptions is Validate with additional config-driven checks.
+func ValidateWithOptions(
+	mysql *source.InspectionResult,
+	cdcResult *cdc.Result,
+	opts Options,
 ) *Report {
 	report := &Report{}
 	mysqlTables := map[string]source.TableInfo{}
@@ -54,6 +76,10 @@ func Validate(
 	// Handle captured tables from CDC
 	if cdcResult != nil {
 		for _, tname := range cdcResult.CapturedTables {
+			// Tables outside the configured scope are not inspected in MySQL
+			if opts.Config != nil && !opts.Config.IncludesTable(tname) {
+				continue
+			}
 			mysqlTable, ok := mysqlTables[tname]
 			if !ok {
 				report.Issues = append(report.Issues, Issue{
@@ -73,13 +99,30 @@ func Validate(
 				})
 			}
 
+			var tc *config.TableConfig
+			if opts.Config != nil {
+				tc = opts.Config.TableFor(tname)
+				report.Issues = append(report.Issues, checkTolerances(mysqlTable, cdcResult, opts.Config.TolerancesFor(tname), opts.Config.CDC)...)
+			}
+			report.Issues = append(report.Issues, checkCDCKey(mysqlTable, cdcResult, tc)...)
+			report.Issues = append(report.Issues, checkUniqueKeys(mysqlTable, cdcResult)...)
+			policies := cdcResult.ColumnPolicies[tname]
+			report.Issues = append(report.Issues, checkColumnPolicies(tname, policies)...)
+
 			// If CDC provided schemas, compare columns and types
 			if cdcResult.TableSchemas != nil {
 				if ctable, ok := cdcResult.TableSchemas[tname]; ok {
 					mysqlCols := getMysqlCols(mysqlTable)
+					mysqlRank, cdcRank := sharedPositions(mysqlCols, ctable.Columns)
 					hasMismatch := false
-					// Column exists in MySQL but not CDC -> INFO (column added)
+					// Column exists in MySQL but not CDC -> INFO (column added).
+					// The schema history is built from DDL, so it has virtual
+					// generated and invisible columns too.
 					for cname := range mysqlCols {
+						// Columns the connector excludes are expected to be absent
+						if policies[cname] == cdc.ColumnExcluded {
+							continue
+						}
 						if _, exists := ctable.Columns[cname]; !exists {
 							report.Issues = append(report.Issues, Issue{
 								Severity: SeverityForChange("column_added"),
@@ -113,16 +156,27 @@ func Validate(
 								})
 								hasMismatch = true
 							}
-							// type mismatch -> WARN
-							if !strings.EqualFold(mcol.Type, ccol.Type) {
-								report.Issues = append(report.Issues, Issue{
-									Severity: SeverityForChange("type_changed"),
-									Table:    tname,
-									Column:   cname,
-									FromType: mcol.Type,
-									ToType:   ccol.Type,
-									Message:  fmt.Sprintf("%s.%s %s (%s -> %s)", tname, cname, MessageForChange("type_changed", tname, cname, mcol.Type, ccol.Type), mcol.Type, ccol.Type),
-								})
+							// type differences, classified by value compatibility
+							if comparesType(policies[cname]) {
+								if kind := compareColumnTypes(mcol, ccol, cdcResult.EmitSettings[tname]); kind != typeEquivalent {
+									toType := cdcTypeString(ccol)
+									report.Issues = append(report.Issues, Issue{
+										Severity: SeverityForChange(kind),
+										Table:    tname,
+										Column:   cname,
+										FromType: mcol.Type,
+										ToType:   toType,
+										Message:  fmt.Sprintf("%s.%s %s (%s -> %s)", tname, cname, MessageForChange(kind, tname, cname, mcol.Type, toType), mcol.Type, toType),
+									})
+									hasMismatch = true
+								}
+							}
+							if enumIssues := checkEnumValues(tname, cname, mcol, ccol, cdcResult.EventFields[tname][cname]); len(enumIssues) > 0 {
+								report.Issues = append(report.Issues, enumIssues...)
+								hasMismatch = true
+							}
+							if attrIssues := checkColumnAttributes(tname, cname, mcol, ccol, mysqlRank[cname] != cdcRank[cname]); len(attrIssues) > 0 {
+								report.Issues = append(report.Issues, attrIssues...)
 								hasMismatch = true
 							}
 						}
@@ -148,25 +202,33 @@ func Validate(
 					}
 				}
 			}
+			report.Issues = append(report.Issues, checkEventAbsences(mysqlTable, cdcResult.EventFields[tname], cdcResult.TableSchemas[tname], policies)...)
 		}
 	}
 
-	// Convert CDC-level warnings into WARN-level issues. Classify snapshot vs connector-health warnings.
-	if cdcResult != nil && len(cdcResult.Warnings) > 0 {
+	// Connector-wide replication lag
+	if cdcResult != nil && opts.Config != nil {
+		report.Issues = append(report.Issues, checkReplicationLag(cdcResult.Lag, opts.Config.Tolerances)...)
+	}
+
+	// Connector heartbeats
+	if cdcResult != nil {
+		report.Issues = append(report.Issues, checkHeartbeat(cdcResult, opts.Config, time.Now())...)
+	}
+
+	// Convert CDC-level warnings into issues of the kind each warning carries
+	if cdcResult != nil {
 		for _, w := range cdcResult.Warnings {
-			kind := "cdc_connector_unhealthy"
-			if strings.Contains(w, "snapshot.mode") {
-				kind = "cdc_snapshot_issue"
-			}
 			report.Issues = append(report.Issues, Issue{
-				Severity: SeverityForChange(kind),
-				Message:  w,
+				Severity: SeverityForChange(warningChangeKind(w.Kind)),
+				Message:  w.Message,
 			})
 		}
 	}
 
-	// Optionally, detect mysql-only tables not captured by CDC as INFO
-	if cdcResult != nil {
+	// Without a config there is no ValidateTables pass to report capture
+	// coverage, so note source tables this connector does not capture
+	if cdcResult != nil && opts.Config == nil {
 		captSet := map[string]struct{}{}
 		for _, t := range cdcResult.CapturedTables {
 			captSet[t] = struct{}{}
@@ -182,5 +244,30 @@ func Validate(
 		}
 	}
 
+	// Privileges of the connector's database user on the captured tables
+	if cdcResult != nil && opts.Grants != nil {
+		var tables []string
+		for _, t := range cdcResult.CapturedTables {
+			if _, ok := mysqlTables[t]; ok && (opts.Config == nil || opts.Config.IncludesTable(t)) {
+				tables = append(tables, t)
+			}
+		}
+		report.Issues = append(report.Issues, checkGrants(opts.Grants, tables)...)
+	}
+
 	return report
 }
+
+// warningChangeKind returns the change kind reported for a CDC warning.
+func warningChangeKind(kind cdc.WarningKind) string {
+	switch kind {
+	case cdc.WarningSnapshot:
+		return "cdc_snapshot_issue"
+	case cdc.WarningHistory:
+		return "cdc_history_incomplete"
+	case cdc.WarningConfig:
+		return "cdc_config_issue"
+	default:
+		return "cdc_connector_unhealthy"
+	}
+}
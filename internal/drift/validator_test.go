@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
 	"github.com/alexanderjulianmartinez/data-watch/internal/source"
 )
 
@@ -44,12 +45,12 @@ func TestTypeChanged(t *testing.T) {
 	rep := Validate(mysql, cdcRes)
 	found := false
 	for _, iss := range rep.Issues {
-		if iss.Severity == SeverityForChange("type_changed") && iss.FromType == "int" && iss.ToType == "varchar" {
+		if iss.Severity == SeverityForChange("type_incompatible") && iss.FromType == "int" && iss.ToType == "varchar" {
 			found = true
 		}
 	}
 	if !found {
-		t.Fatalf("expected to find type_changed issue from int to varchar, got %v", rep.Issues)
+		t.Fatalf("expected to find type_incompatible issue from int to varchar, got %v", rep.Issues)
 	}
 }
 
@@ -114,7 +115,7 @@ func TestCDCUpToDateNoWarn(t *testing.T) {
 func TestCDCSnapshotWarning(t *testing.T) {
 	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "t1", Columns: []source.ColumnInfo{{Name: "a", Type: "int", Nullable: false}}}}}
 	warningMsg := "Connector foo has snapshot.mode=never; snapshots disabled or schema-only (CDC may miss initial data). This check will not attempt to trigger snapshots."
-	cdcRes := &cdc.Result{ConnectorReachable: true, CapturedTables: []string{"t1"}, Warnings: []string{warningMsg}}
+	cdcRes := &cdc.Result{ConnectorReachable: true, CapturedTables: []string{"t1"}, Warnings: []cdc.Warning{{Kind: cdc.WarningSnapshot, Message: warningMsg}}}
 	rep := Validate(mysql, cdcRes)
 	found := false
 	for _, iss := range rep.Issues {
@@ -133,7 +134,7 @@ func TestCDCSnapshotWarning(t *testing.T) {
 func TestConnectorHealthWarn(t *testing.T) {
 	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "t1", Columns: []source.ColumnInfo{{Name: "a", Type: "int", Nullable: false}}}}}
 	warningMsg := "Connector foo has failed task(s): [0]"
-	cdcRes := &cdc.Result{ConnectorReachable: true, CapturedTables: []string{"t1"}, Warnings: []string{warningMsg}}
+	cdcRes := &cdc.Result{ConnectorReachable: true, CapturedTables: []string{"t1"}, Warnings: []cdc.Warning{{Kind: cdc.WarningConnector, Message: warningMsg}}}
 	rep := Validate(mysql, cdcRes)
 	found := false
 	for _, iss := range rep.Issues {
@@ -148,3 +149,43 @@ func TestConnectorHealthWarn(t *testing.T) {
 		t.Fatalf("expected to find cdc_connector_unhealthy in report, got %v", rep.Issues)
 	}
 }
+
+func TestWarningChangeKind(t *testing.T) {
+	cases := map[cdc.WarningKind]string{
+		cdc.WarningSnapshot:  "cdc_snapshot_issue",
+		cdc.WarningHistory:   "cdc_history_incomplete",
+		cdc.WarningConfig:    "cdc_config_issue",
+		cdc.WarningConnector: "cdc_connector_unhealthy",
+		"":                   "cdc_connector_unhealthy",
+	}
+	for kind, want := range cases {
+		if got := warningChangeKind(kind); got != want {
+			t.Errorf("%q: got %s, want %s", kind, got, want)
+		}
+	}
+}
+
+func TestUncapturedTableReportedWithoutConfig(t *testing.T) {
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "t1", PrimaryKey: []string{"id"}}, {Name: "t2", PrimaryKey: []string{"id"}}}}
+	cdcRes := &cdc.Result{ConnectorReachable: true, CapturedTables: []string{"t1"}}
+	var found bool
+	for _, iss := range Validate(mysql, cdcRes).Issues {
+		if iss.Table == "t2" && iss.Severity == SeverityInfo && iss.Message == "t2 exists in MySQL but not captured by CDC" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected not-captured notice for t2 without a config")
+	}
+}
+
+func TestUncapturedTableLeftToValidateTables(t *testing.T) {
+	// With a config, coverage is reported once, as table_not_captured, by ValidateTables
+	mysql := &source.InspectionResult{Tables: []source.TableInfo{{Name: "t1", PrimaryKey: []string{"id"}}, {Name: "t2", PrimaryKey: []string{"id"}}}}
+	cdcRes := &cdc.Result{ConnectorReachable: true, CapturedTables: []string{"t1"}}
+	for _, iss := range ValidateWithOptions(mysql, cdcRes, Options{Config: &config.Config{}}).Issues {
+		if iss.Table == "t2" {
+			t.Fatalf("expected no per-connector issue for t2, got %v", iss)
+		}
+	}
+}
@@ -0,0 +1,442 @@
+package drift
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// nullValue is the canonical form of NULL; no canonical value contains NUL.
+const nullValue = "\x00NULL"
+
+// connectDecimal is the semantic type of precise decimals.
+const connectDecimal = "org.apache.kafka.connect.data.Decimal"
+
+// canonicalDatetime formats datetimes and timestamps, which compare as UTC
+// wall-clock time.
+const canonicalDatetime = "2006-01-02 15:04:05.999999999"
+
+// valueType is the normalized type of a source column, for comparing its
+// values with event fields.
+func valueType(col source.ColumnInfo) normType {
+	length := col.Length
+	if length == nil {
+		length = col.Precision
+	}
+	n := normalizeSQLType(col.Type, length, col.Scale)
+	if col.Unsigned {
+		n.unsigned = true
+	}
+	return n
+}
+
+// sourceValue renders a source value in the canonical form of its type. ok is
+// false for values that are not compared, such as geometries and MySQL zero
+// dates, which Debezium does not emit as such.
+func sourceValue(col source.ColumnInfo, v *string) (string, bool) {
+	if v == nil {
+		return nullValue, true
+	}
+	s := *v
+	t := valueType(col)
+	if t.family == famBit {
+		// BIT values are read as big-endian bytes
+		var u uint64
+		for _, b := range []byte(s) {
+			u = u<<8 | uint64(b)
+		}
+		return strconv.FormatUint(u, 10), true
+	}
+	switch t.family {
+	case famBool:
+		switch strings.ToLower(s) {
+		case "t", "true":
+			return "1", true
+		case "f", "false":
+			return "0", true
+		}
+		return canonicalInt(s)
+	case famInt, famYear:
+		return canonicalInt(s)
+	case famDecimal:
+		return canonicalDecimal(s, t.scale)
+	case famFloat:
+		return canonicalFloat(s, t.size)
+	case famDate:
+		if strings.HasPrefix(s, "0000-") {
+			return "", false
+		}
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return "", false
+		}
+		return d.Format("2006-01-02"), true
+	case famDatetime, famTimestamp:
+		if strings.HasPrefix(s, "0000-") {
+			return "", false
+		}
+		ts, err := time.Parse("2006-01-02 15:04:05", s)
+		if err != nil {
+			return "", false
+		}
+		return ts.Format(canonicalDatetime), true
+	case famTime:
+		d, err := parseSQLTime(s)
+		if err != nil {
+			return "", false
+		}
+		return formatTime(d), true
+	case famJSON:
+		return canonicalJSON(s)
+	case famGeometry:
+		return "", false
+	}
+	// Strings, enums, sets, UUIDs and binary values compare as they are
+	return s, true
+}
+
+// sinkValue renders a value read from a sink database in the canonical form
+// of the source column's type. Sinks store values in their own types, e.g. a
+// TINYINT(1) as a Postgres boolean or a TIMESTAMP as timestamptz, which
+// render differently as text.
+func sinkValue(col source.ColumnInfo, v *string) (string, bool) {
+	if v == nil {
+		return nullValue, true
+	}
+	s := *v
+	t := valueType(col)
+	switch t.family {
+	case famInt, famBool, famBit, famYear:
+		switch strings.ToLower(s) {
+		case "t", "true":
+			return "1", true
+		case "f", "false":
+			return "0", true
+		}
+		// A Postgres bit(n) renders as n binary digits
+		if t.family == famBit && len(s) == t.size && strings.Trim(s, "01") == "" {
+			u, err := strconv.ParseUint(s, 2, 64)
+			if err != nil {
+				return "", false
+			}
+			return strconv.FormatUint(u, 10), true
+		}
+	case famDatetime, famTimestamp:
+		for _, layout := range []string{"2006-01-02 15:04:05Z07", "2006-01-02 15:04:05Z07:00", time.RFC3339Nano} {
+			if ts, err := time.Parse(layout, s); err == nil {
+				return ts.UTC().Format(canonicalDatetime), true
+			}
+		}
+	}
+	return sourceValue(col, &s)
+}
+
+// eventValue renders a change event field in the canonical form of the source
+// column's type. f is the field's Connect schema, when the events carry one;
+// otherwise the encoding follows from the source type and the connector's
+// emit settings, as in emittedType.
+func eventValue(col source.ColumnInfo, v interface{}, f cdc.FieldSchema, settings map[string]string) (string, bool) {
+	if v == nil {
+		return nullValue, true
+	}
+	mode := func(key, def string) string {
+		if m := strings.ToLower(strings.TrimSpace(settings[key])); m != "" {
+			return m
+		}
+		return def
+	}
+	t := valueType(col)
+	switch t.family {
+	case famInt, famYear, famBool:
+		switch x := v.(type) {
+		case bool:
+			return boolValue(x), true
+		case json.Number:
+			return canonicalInt(x.String())
+		case string:
+			// BIGINT UNSIGNED as a precise decimal
+			if f.Name == connectDecimal || mode("bigint.unsigned.handling.mode", "long") == "precise" {
+				if n, ok := decodeDecimal(x, 0); ok {
+					return n.FloatString(0), true
+				}
+			}
+			return canonicalInt(x)
+		}
+	case famBit:
+		switch x := v.(type) {
+		case bool:
+			return boolValue(x), true
+		case string:
+			// io.debezium.data.Bits are little-endian bytes
+			b, err := base64.StdEncoding.DecodeString(x)
+			if err != nil || len(b) > 8 {
+				return "", false
+			}
+			var buf [8]byte
+			copy(buf[:], b)
+			return strconv.FormatUint(binary.LittleEndian.Uint64(buf[:]), 10), true
+		}
+	case famDecimal:
+		switch x := v.(type) {
+		case json.Number:
+			return canonicalDecimal(x.String(), t.scale)
+		case string:
+			// Precise decimals are bytes; decimal.handling.mode=string emits text
+			precise := f.Name == connectDecimal || (f.Type == "" && mode("decimal.handling.mode", "precise") == "precise")
+			if !precise {
+				return canonicalDecimal(x, t.scale)
+			}
+			scale := t.scale
+			if p, err := strconv.Atoi(f.Parameters["scale"]); err == nil {
+				scale = p
+			}
+			if n, ok := decodeDecimal(x, scale); ok {
+				return n.FloatString(t.scale), true
+			}
+		case map[string]interface{}:
+			// io.debezium.data.VariableScaleDecimal
+			scale, _ := x["scale"].(json.Number)
+			value, _ := x["value"].(string)
+			sc, err := strconv.Atoi(scale.String())
+			if err != nil {
+				return "", false
+			}
+			if n, ok := decodeDecimal(value, sc); ok {
+				return n.FloatString(t.scale), true
+			}
+		}
+	case famFloat:
+		if x, ok := v.(json.Number); ok {
+			return canonicalFloat(x.String(), t.size)
+		}
+	case famDate:
+		if x, ok := v.(json.Number); ok {
+			days, err := x.Int64()
+			if err != nil {
+				return "", false
+			}
+			return time.Unix(days*86400, 0).UTC().Format("2006-01-02"), true
+		}
+	case famDatetime:
+		if x, ok := v.(json.Number); ok {
+			n, err := x.Int64()
+			if err != nil {
+				return "", false
+			}
+			var ts time.Time
+			switch temporalUnit(f.Name, t, mode("time.precision.mode", "adaptive")) {
+			case time.Millisecond:
+				ts = time.UnixMilli(n)
+			case time.Microsecond:
+				ts = time.UnixMicro(n)
+			default:
+				ts = time.Unix(0, n)
+			}
+			return ts.UTC().Format(canonicalDatetime), true
+		}
+	case famTimestamp:
+		if x, ok := v.(string); ok {
+			ts, err := time.Parse(time.RFC3339Nano, x)
+			if err != nil {
+				return "", false
+			}
+			return ts.UTC().Format(canonicalDatetime), true
+		}
+	case famTime:
+		if x, ok := v.(json.Number); ok {
+			n, err := x.Int64()
+			if err != nil {
+				return "", false
+			}
+			unit := temporalUnit(f.Name, t, mode("time.precision.mode", "adaptive"))
+			return formatTime(time.Duration(n) * unit), true
+		}
+	case famBinary:
+		if x, ok := v.(string); ok {
+			var b []byte
+			var err error
+			switch mode("binary.handling.mode", "bytes") {
+			case "hex":
+				b, err = hex.DecodeString(x)
+			case "base64-url-safe":
+				b, err = base64.URLEncoding.DecodeString(x)
+			default:
+				b, err = base64.StdEncoding.DecodeString(x)
+			}
+			if err != nil {
+				return "", false
+			}
+			return string(b), true
+		}
+	case famJSON:
+		if x, ok := v.(string); ok {
+			return canonicalJSON(x)
+		}
+	case famGeometry:
+		return "", false
+	default:
+		if x, ok := v.(string); ok {
+			return x, true
+		}
+	}
+	return "", false
+}
+
+// temporalUnit is the unit of a numeric TIME or DATETIME field: from its
+// semantic type when known, otherwise from time.precision.mode and the
+// column's fractional seconds.
+func temporalUnit(name string, t normType, precisionMode string) time.Duration {
+	switch name {
+	case "io.debezium.time.Timestamp", "io.debezium.time.Time",
+		"org.apache.kafka.connect.data.Timestamp", "org.apache.kafka.connect.data.Time":
+		return time.Millisecond
+	case "io.debezium.time.MicroTimestamp", "io.debezium.time.MicroTime":
+		return time.Microsecond
+	case "io.debezium.time.NanoTimestamp", "io.debezium.time.NanoTime":
+		return time.Nanosecond
+	}
+	switch {
+	case precisionMode == "connect":
+		return time.Millisecond
+	case precisionMode == "adaptive_time_microseconds" && t.family == famTime:
+		return time.Microsecond
+	case t.fsp <= 3:
+		return time.Millisecond
+	}
+	return time.Microsecond
+}
+
+func boolValue(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func canonicalInt(s string) (string, bool) {
+	n, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+	if !ok {
+		return "", false
+	}
+	return n.String(), true
+}
+
+// canonicalDecimal formats a decimal with the column's scale, so 12.5 and
+// 12.50 compare equal.
+func canonicalDecimal(s string, scale int) (string, bool) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return "", false
+	}
+	return r.FloatString(scale), true
+}
+
+// decodeDecimal decodes a Connect Decimal: the base64 big-endian two's
+// complement unscaled value.
+func decodeDecimal(s string, scale int) (*big.Rat, bool) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, false
+	}
+	n := new(big.Int).SetBytes(b)
+	if b[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+	}
+	return new(big.Rat).SetFrac(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)), true
+}
+
+// canonicalFloat formats a float at the column's precision, so a FLOAT
+// read as 1.1 equals the float32 Debezium emits for it.
+func canonicalFloat(s string, size int) (string, bool) {
+	bits := 64
+	if size == 4 {
+		bits = 32
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), bits)
+	if err != nil {
+		return "", false
+	}
+	return strconv.FormatFloat(f, 'g', -1, bits), true
+}
+
+// canonicalJSON re-encodes a JSON document with sorted keys and without
+// insignificant whitespace.
+func canonicalJSON(s string) (string, bool) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return "", false
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+// parseSQLTime parses a MySQL TIME value such as "-838:59:59.000000".
+func parseSQLTime(s string) (time.Duration, error) {
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	h, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, err
+	}
+	m, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, err
+	}
+	sec, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, err
+	}
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec*1e6+0.5)*time.Microsecond
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// formatTime formats a TIME value as [-]HH:MM:SS[.ffffff].
+func formatTime(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	h := d / time.Hour
+	m := d % time.Hour / time.Minute
+	s := d % time.Minute / time.Second
+	out := fmt.Sprintf("%s%02d:%02d:%02d", sign, h, m, s)
+	if frac := d % time.Second; frac != 0 {
+		out += strings.TrimRight(fmt.Sprintf(".%09d", frac), "0")
+	}
+	return out
+}
+
+// displayValue renders a canonical value for a message: binary values in
+// hex, and long values shortened.
+func displayValue(col source.ColumnInfo, v string) string {
+	if v == nullValue {
+		return "NULL"
+	}
+	if valueType(col).family == famBinary {
+		v = "0x" + hex.EncodeToString([]byte(v))
+	}
+	const maxDisplay = 40
+	if r := []rune(v); len(r) > maxDisplay {
+		v = string(r[:maxDisplay]) + "..."
+	}
+	return v
+}
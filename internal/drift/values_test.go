@@ -0,0 +1,105 @@
+package drift
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+func TestEventValueMatchesSource(t *testing.T) {
+	str := func(s string) *string { return &s }
+	for _, tc := range []struct {
+		name     string
+		colType  string
+		source   *string
+		event    interface{}
+		field    cdc.FieldSchema
+		settings map[string]string
+	}{
+		{"int", "int(11)", str("42"), json.Number("42"), cdc.FieldSchema{}, nil},
+		{"bigint beyond float64", "bigint", str("12345678901234567"), json.Number("12345678901234567"), cdc.FieldSchema{}, nil},
+		{"bigint unsigned precise", "bigint unsigned", str("18446744073709551615"), "AP//////////", cdc.FieldSchema{}, map[string]string{"bigint.unsigned.handling.mode": "precise"}},
+		{"tinyint(1) as boolean", "tinyint(1)", str("1"), true, cdc.FieldSchema{}, nil},
+		{"decimal precise", "decimal(10,2)", str("12.34"), "BNI=", cdc.FieldSchema{Type: "bytes", Name: connectDecimal, Parameters: map[string]string{"scale": "2"}}, nil},
+		{"decimal precise negative", "decimal(10,2)", str("-0.01"), "/w==", cdc.FieldSchema{}, nil},
+		{"decimal string", "decimal(10,2)", str("12.50"), "12.5", cdc.FieldSchema{Type: "string"}, map[string]string{"decimal.handling.mode": "string"}},
+		{"decimal double", "decimal(10,2)", str("0.10"), json.Number("0.1"), cdc.FieldSchema{}, map[string]string{"decimal.handling.mode": "double"}},
+		{"float", "float", str("1.1"), json.Number("1.100000023841858"), cdc.FieldSchema{}, nil},
+		{"date", "date", str("2024-03-01"), json.Number("19783"), cdc.FieldSchema{}, nil},
+		{"datetime millis", "datetime", str("2024-03-01 12:30:00"), json.Number("1709296200000"), cdc.FieldSchema{}, nil},
+		{"datetime micros", "datetime(6)", str("2024-03-01 12:30:00.123456"), json.Number("1709296200123456"), cdc.FieldSchema{}, nil},
+		{"datetime connect", "datetime(6)", str("2024-03-01 12:30:00.123000"), json.Number("1709296200123"), cdc.FieldSchema{}, map[string]string{"time.precision.mode": "connect"}},
+		{"timestamp", "timestamp", str("2024-03-01 12:30:00"), "2024-03-01T12:30:00Z", cdc.FieldSchema{}, nil},
+		{"time", "time(6)", str("-01:02:03.500000"), json.Number("-3723500000"), cdc.FieldSchema{Name: "io.debezium.time.MicroTime"}, nil},
+		{"bit(1)", "bit(1)", str("\x01"), true, cdc.FieldSchema{}, nil},
+		{"bit(16)", "bit(16)", str("\x01\x02"), "AgE=", cdc.FieldSchema{}, nil},
+		{"binary base64", "varbinary(4)", str("\xde\xad"), "3q0=", cdc.FieldSchema{}, nil},
+		{"binary hex", "varbinary(4)", str("\xde\xad"), "dead", cdc.FieldSchema{}, map[string]string{"binary.handling.mode": "hex"}},
+		{"json", "json", str(`{"b": 1, "a": [1, 2]}`), `{"a":[1,2],"b":1}`, cdc.FieldSchema{}, nil},
+		{"varchar", "varchar(20)", str("héllo"), "héllo", cdc.FieldSchema{}, nil},
+		{"null", "varchar(20)", nil, nil, cdc.FieldSchema{}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			col := source.ColumnInfo{Name: "c", Type: tc.colType}
+			sv, ok := sourceValue(col, tc.source)
+			if !ok {
+				t.Fatal("source value not compared")
+			}
+			ev, ok := eventValue(col, tc.event, tc.field, tc.settings)
+			if !ok {
+				t.Fatal("event value not compared")
+			}
+			if sv != ev {
+				t.Errorf("expected equal values, got source %q and event %q", sv, ev)
+			}
+		})
+	}
+}
+
+func TestSinkValueMatchesSource(t *testing.T) {
+	str := func(s string) *string { return &s }
+	for _, tc := range []struct {
+		name    string
+		colType string
+		source  *string
+		sink    *string
+	}{
+		{"postgres boolean", "tinyint(1)", str("1"), str("t")},
+		{"postgres bit", "bit(4)", str("\x05"), str("0101")},
+		{"mysql bit", "bit(1)", str("\x01"), str("\x01")},
+		{"timestamptz", "timestamp(3)", str("2024-03-01 12:30:00.500"), str("2024-03-01 07:30:00.5-05")},
+		{"timestamptz RFC 3339", "datetime", str("2024-03-01 12:30:00"), str("2024-03-01T12:30:00Z")},
+		{"numeric", "decimal(10,2)", str("1.50"), str("1.5")},
+		{"null", "int", nil, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			col := source.ColumnInfo{Name: "c", Type: tc.colType}
+			sv, ok := sourceValue(col, tc.source)
+			if !ok {
+				t.Fatal("source value not compared")
+			}
+			kv, ok := sinkValue(col, tc.sink)
+			if !ok {
+				t.Fatal("sink value not compared")
+			}
+			if sv != kv {
+				t.Errorf("expected equal values, got source %q and sink %q", sv, kv)
+			}
+		})
+	}
+}
+
+func TestValuesNotCompared(t *testing.T) {
+	zero := "0000-00-00 00:00:00"
+	if _, ok := sourceValue(source.ColumnInfo{Type: "datetime"}, &zero); ok {
+		t.Error("expected MySQL zero dates not to be compared")
+	}
+	if _, ok := eventValue(source.ColumnInfo{Type: "point"}, "AQE=", cdc.FieldSchema{}, nil); ok {
+		t.Error("expected geometries not to be compared")
+	}
+	if _, ok := eventValue(source.ColumnInfo{Type: "int"}, map[string]interface{}{}, cdc.FieldSchema{}, nil); ok {
+		t.Error("expected an unexpected encoding not to be compared")
+	}
+}
@@ -0,0 +1,175 @@
+package source
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GTIDSet is a MySQL GTID set, e.g.
+// "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5:11-18". It maps each source
+// server UUID, followed by ":tag" for MySQL 8.3 tagged GTIDs, to the
+// transaction numbers executed from it.
+type GTIDSet map[string][]GTIDInterval
+
+// GTIDInterval is an inclusive range of transaction numbers.
+type GTIDInterval struct {
+	Start, End int64
+}
+
+// ParseGTIDSet parses a GTID set as printed by MySQL, such as the value of
+// gtid_executed or gtid_purged. Whitespace, including the newlines MySQL
+// inserts after each comma, is ignored. An empty string is the empty set.
+func ParseGTIDSet(s string) (GTIDSet, error) {
+	set := GTIDSet{}
+	for _, elem := range strings.Split(s, ",") {
+		elem = strings.Join(strings.Fields(elem), "")
+		if elem == "" {
+			continue
+		}
+		parts := strings.Split(elem, ":")
+		uuid := strings.ToLower(parts[0])
+		if len(parts) < 2 || uuid == "" {
+			return nil, fmt.Errorf("invalid GTID set element %q", elem)
+		}
+		key := uuid
+		for _, p := range parts[1:] {
+			if p == "" || p[0] < '0' || p[0] > '9' {
+				// A tag applies to the intervals that follow it
+				if !isGTIDTag(p) {
+					return nil, fmt.Errorf("invalid GTID set element %q", elem)
+				}
+				key = uuid + ":" + strings.ToLower(p)
+				continue
+			}
+			iv, err := parseGTIDInterval(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid GTID set element %q: %w", elem, err)
+			}
+			set[key] = append(set[key], iv)
+		}
+	}
+	for k, ivs := range set {
+		set[k] = mergeIntervals(ivs)
+	}
+	return set, nil
+}
+
+// isGTIDTag reports whether s is a valid GTID tag: a letter or underscore
+// followed by up to 31 letters, digits or underscores.
+func isGTIDTag(s string) bool {
+	if s == "" || len(s) > 32 || (s[0] >= '0' && s[0] <= '9') {
+		return false
+	}
+	for _, r := range s {
+		if r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func parseGTIDInterval(s string) (GTIDInterval, error) {
+	lo, hi, isRange := strings.Cut(s, "-")
+	start, err := strconv.ParseInt(lo, 10, 64)
+	if err != nil {
+		return GTIDInterval{}, err
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.ParseInt(hi, 10, 64); err != nil {
+			return GTIDInterval{}, err
+		}
+	}
+	if start < 1 || end < start {
+		return GTIDInterval{}, fmt.Errorf("invalid interval %s", s)
+	}
+	return GTIDInterval{Start: start, End: end}, nil
+}
+
+// mergeIntervals sorts intervals and joins overlapping or adjacent ones.
+func mergeIntervals(ivs []GTIDInterval) []GTIDInterval {
+	sort.Slice(ivs, func(a, b int) bool { return ivs[a].Start < ivs[b].Start })
+	var out []GTIDInterval
+	for _, iv := range ivs {
+		if n := len(out); n > 0 && iv.Start <= out[n-1].End+1 {
+			if iv.End > out[n-1].End {
+				out[n-1].End = iv.End
+			}
+			continue
+		}
+		out = append(out, iv)
+	}
+	return out
+}
+
+// Subtract returns the transactions in s that are not in other.
+func (s GTIDSet) Subtract(other GTIDSet) GTIDSet {
+	out := GTIDSet{}
+	for key, ivs := range s {
+		for _, iv := range ivs {
+			rest := []GTIDInterval{iv}
+			for _, o := range other[key] {
+				var next []GTIDInterval
+				for _, r := range rest {
+					if o.End < r.Start || o.Start > r.End {
+						next = append(next, r)
+						continue
+					}
+					if r.Start < o.Start {
+						next = append(next, GTIDInterval{Start: r.Start, End: o.Start - 1})
+					}
+					if r.End > o.End {
+						next = append(next, GTIDInterval{Start: o.End + 1, End: r.End})
+					}
+				}
+				rest = next
+			}
+			out[key] = append(out[key], rest...)
+		}
+		if len(out[key]) == 0 {
+			delete(out, key)
+		}
+	}
+	return out
+}
+
+// Contains reports whether every transaction in other is also in s.
+func (s GTIDSet) Contains(other GTIDSet) bool {
+	return len(other.Subtract(s)) == 0
+}
+
+// Count returns the number of transactions in the set.
+func (s GTIDSet) Count() int64 {
+	var n int64
+	for _, ivs := range s {
+		for _, iv := range ivs {
+			n += iv.End - iv.Start + 1
+		}
+	}
+	return n
+}
+
+// String formats the set as MySQL does, with source UUIDs in sorted order.
+func (s GTIDSet) String() string {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var elems []string
+	for _, k := range keys {
+		var b strings.Builder
+		b.WriteString(k)
+		for _, iv := range s[k] {
+			if iv.Start == iv.End {
+				fmt.Fprintf(&b, ":%d", iv.Start)
+			} else {
+				fmt.Fprintf(&b, ":%d-%d", iv.Start, iv.End)
+			}
+		}
+		elems = append(elems, b.String())
+	}
+	return strings.Join(elems, ",")
+}
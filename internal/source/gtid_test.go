@@ -0,0 +1,46 @@
+package source
+
+import "testing"
+
+func TestParseGTIDSet(t *testing.T) {
+	set, err := ParseGTIDSet("3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5:11-18:6-10,\n  2174b383-5441-11e8-b90a-c80aa9429562:1-3:fleet:7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "2174b383-5441-11e8-b90a-c80aa9429562:1-3,2174b383-5441-11e8-b90a-c80aa9429562:fleet:7,3e11fa47-71ca-11e1-9e33-c80aa9429562:1-18"
+	if got := set.String(); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	if empty, err := ParseGTIDSet(""); err != nil || len(empty) != 0 {
+		t.Fatalf("expected the empty set, got %v (%v)", empty, err)
+	}
+	for _, bad := range []string{"3e11fa47", "3e11fa47:5-2", "3e11fa47:x-1", "3e11fa47::1"} {
+		if _, err := ParseGTIDSet(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestGTIDSetSubtract(t *testing.T) {
+	parse := func(s string) GTIDSet {
+		set, err := ParseGTIDSet(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return set
+	}
+	executed := parse("a:1-100,b:1-20")
+	purged := parse("a:1-50,b:1-5")
+	if !executed.Contains(purged) {
+		t.Fatalf("expected %s to contain %s", executed, purged)
+	}
+	if got := parse("a:1-60,c:1-2").Subtract(executed).String(); got != "c:1-2" {
+		t.Fatalf("expected c:1-2, got %s", got)
+	}
+	if got := parse("a:1-100").Subtract(parse("a:10-20:30")).String(); got != "a:1-9:21-29:31-100" {
+		t.Fatalf("expected holes to be cut out, got %s", got)
+	}
+	if !parse("a:1-10").Contains(parse("")) {
+		t.Fatal("every set contains the empty set")
+	}
+}
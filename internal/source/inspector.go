@@ -0,0 +1,93 @@
+package source
+
+import "context"
+
+// Inspector inspects a source database and reports its tables.
+type Inspector interface {
+	Name() string
+	Inspect(ctx context.Context) (*InspectionResult, error)
+	Close() error
+}
+
+// GrantInspector is implemented by inspectors that can read another
+// account's privileges, such as the account a CDC connector connects as.
+type GrantInspector interface {
+	// FetchGrants returns the privileges of user on the inspected schema, or
+	// nil when the inspecting account cannot see them.
+	FetchGrants(ctx context.Context, user string) (*Grants, error)
+}
+
+// Row is a table row as the source renders it as text, keyed by column name.
+// NULL values are nil; binary and BIT values hold the raw bytes.
+type Row map[string]*string
+
+// RowSampler is implemented by inspectors that can read table rows, for
+// reconciling them with the change events of the CDC stream. TIMESTAMP
+// values are rendered in UTC.
+type RowSampler interface {
+	// SampleRows returns up to n rows of table chosen at random, reading
+	// it in keyColumns order.
+	SampleRows(ctx context.Context, table string, keyColumns []string, n int) ([]Row, error)
+	// FetchRows returns the rows of table whose keyColumns equal one of
+	// keys, each holding a value per key column.
+	FetchRows(ctx context.Context, table string, keyColumns []string, keys [][]string) ([]Row, error)
+}
+
+// RangeReader is implemented by inspectors that can read a table in key
+// order, for comparing it chunk by chunk with another database. Reads run
+// in read-only transactions.
+type RangeReader interface {
+	// ReadRange returns the rows of table whose key, compared as a row of
+	// keyColumns, is greater than after and at most upTo, in key order and
+	// at most limit rows. A nil bound leaves that end open and a zero limit
+	// returns every row in range.
+	ReadRange(ctx context.Context, table string, keyColumns []string, after, upTo []string, limit int) ([]Row, error)
+}
+
+// RangeChecksummer is implemented by inspectors that can checksum a key range
+// of a table in the database, so tables are compared without reading their
+// rows. Queries run in read-only transactions.
+type RangeChecksummer interface {
+	// TableColumns returns the columns of table in ordinal order, or nil when
+	// the table does not exist.
+	TableColumns(ctx context.Context, table string) ([]ColumnInfo, error)
+	// ChunkBound returns the key of the n-th row of table after the key
+	// after, in key order, or nil when fewer rows remain. A nil after starts
+	// at the first row.
+	ChunkBound(ctx context.Context, table string, keyColumns, after []string, n int) ([]string, error)
+	// ChecksumRange returns the rows of table in the key range, as in
+	// RangeReader.ReadRange, and the sum of a hash of each row's columns
+	// rendered in their canonical forms.
+	ChecksumRange(ctx context.Context, table string, keyColumns []string, columns []ChecksumColumn, after, upTo []string) (RangeChecksum, error)
+}
+
+// ValueForm is the canonical text form a column's values are rendered in
+// before hashing, so equal values hash alike in different databases.
+type ValueForm string
+
+const (
+	FormText     ValueForm = "text"     // as stored, in UTF-8
+	FormInteger  ValueForm = "integer"  // decimal digits; booleans as 1 and 0, BIT values as integers
+	FormDecimal  ValueForm = "decimal"  // fixed point with Scale digits after the point
+	FormFloat    ValueForm = "float"    // as the database renders it
+	FormDate     ValueForm = "date"     // YYYY-MM-DD
+	FormDatetime ValueForm = "datetime" // YYYY-MM-DD HH:MM:SS[.ffffff] in UTC, without trailing zeros
+	FormTime     ValueForm = "time"     // [-]HH:MM:SS[.ffffff], without trailing zeros
+	FormBinary   ValueForm = "binary"   // uppercase hex
+	FormJSON     ValueForm = "json"     // the database's normalized JSON text
+)
+
+// ChecksumColumn is a column to checksum and the form to render it in.
+type ChecksumColumn struct {
+	Column ColumnInfo // as the checksummed database reports it
+	Form   ValueForm
+	Scale  int // for FormDecimal
+}
+
+// RangeChecksum is the checksum of a key range. Sum is the decimal sum of
+// a 60-bit hash of each row, so it does not depend on the order rows are
+// read in and duplicate rows do not cancel out.
+type RangeChecksum struct {
+	Rows int64
+	Sum  string
+}
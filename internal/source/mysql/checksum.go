@@ -0,0 +1,91 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// TableColumns returns the columns of table in ordinal order, or nil when the
+// table does not exist.
+func (i *Inspector) TableColumns(ctx context.Context, table string) ([]source.ColumnInfo, error) {
+	schema, err := i.FetchSchema(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	var cols []source.ColumnInfo
+	for _, col := range schema {
+		cols = append(cols, columnInfo(col))
+	}
+	return cols, nil
+}
+
+// ChunkBound returns the key of the n-th row of table after the key after, or
+// nil when fewer rows remain. Only the primary key index is read.
+func (i *Inspector) ChunkBound(ctx context.Context, table string, keyColumns, after []string, n int) ([]string, error) {
+	query, args := source.BoundQuery(i.from(table), keyColumns, after, n, quoteIdent, placeholder)
+	rows, err := i.queryRows(ctx, query, args...)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	key := make([]string, len(keyColumns))
+	for n, c := range keyColumns {
+		if v := rows[0][c]; v != nil {
+			key[n] = *v
+		}
+	}
+	return key, nil
+}
+
+// ChecksumRange counts the rows of table in the key range and sums a 60-bit
+// prefix of the MD5 of each row's canonical values, in the database.
+func (i *Inspector) ChecksumRange(ctx context.Context, table string, keyColumns []string, columns []source.ChecksumColumn, after, upTo []string) (source.RangeChecksum, error) {
+	values := make([]string, len(columns))
+	for n, c := range columns {
+		// NULL and the empty string must differ
+		values[n] = fmt.Sprintf("COALESCE(CONCAT('V', %s), 'N')", canonicalExpr(c))
+	}
+	row := fmt.Sprintf("CONVERT(CONCAT_WS(CHAR(31 USING utf8mb4), %s) USING utf8mb4)", strings.Join(values, ", "))
+	sum := fmt.Sprintf("COALESCE(SUM(CAST(CONV(LEFT(MD5(%s), 15), 16, 10) AS UNSIGNED)), 0)", row)
+	query, args := source.ChecksumQuery(i.from(table), sum, keyColumns, after, upTo, quoteIdent, placeholder)
+
+	var res source.RangeChecksum
+	err := i.readOnly(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, query, args...).Scan(&res.Rows, &res.Sum)
+	})
+	return res, err
+}
+
+// canonicalExpr renders a column in its canonical form. Session time is UTC,
+// so TIMESTAMP values render as UTC.
+func canonicalExpr(c source.ChecksumColumn) string {
+	col := quoteIdent(c.Column.Name)
+	switch c.Form {
+	case source.FormInteger:
+		// BIT and YEAR values convert to integers too
+		return fmt.Sprintf("CAST(%s + 0 AS CHAR)", col)
+	case source.FormDecimal:
+		return fmt.Sprintf("CAST(CAST(%s AS DECIMAL(65, %d)) AS CHAR)", col, c.Scale)
+	case source.FormDate:
+		return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-%%d')", col)
+	case source.FormDatetime:
+		return fmt.Sprintf("TRIM(TRAILING '.' FROM TRIM(TRAILING '0' FROM DATE_FORMAT(%s, '%%Y-%%m-%%d %%H:%%i:%%s.%%f')))", col)
+	case source.FormTime:
+		s := fmt.Sprintf("CAST(%s AS CHAR)", col)
+		return fmt.Sprintf("IF(LOCATE('.', %[1]s) > 0, TRIM(TRAILING '.' FROM TRIM(TRAILING '0' FROM %[1]s)), %[1]s)", s)
+	case source.FormBinary:
+		return fmt.Sprintf("HEX(%s)", col)
+	}
+	// Text, floats and JSON render as MySQL stores them; JSON documents are
+	// stored normalized
+	return fmt.Sprintf("CAST(%s AS CHAR)", col)
+}
+
+func (i *Inspector) from(table string) string {
+	return quoteIdent(i.schema) + "." + quoteIdent(table)
+}
+
+func placeholder(int) string { return "?" }
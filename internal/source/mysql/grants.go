@@ -0,0 +1,306 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	driver "github.com/go-sql-driver/mysql"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// account is a MySQL account or role, 'user'@'host'.
+type account struct {
+	user, host string
+}
+
+// grantee renders the account as the GRANTEE column of the privilege views.
+func (a account) grantee() string {
+	return quoteName(a.user) + "@" + quoteName(a.host)
+}
+
+func quoteName(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func (a account) String() string {
+	return a.user + "@" + a.host
+}
+
+// FetchGrants reads user's privileges from the information_schema privilege
+// views, including those of the roles the account activates on login. Those
+// views only list other accounts' privileges to an inspecting account with
+// SELECT on the mysql schema, so nil is returned when no row for user is
+// visible.
+//
+// When user is defined for several hosts, the account its open connections
+// (such as a connector's binlog reader) match is checked; without one, the
+// account with the least specific host is checked and Grants.Uncertain says
+// so.
+func (i *Inspector) FetchGrants(ctx context.Context, user string) (*source.Grants, error) {
+	// USAGE stands for "no privileges" but still shows the account is visible
+	grantees, err := i.stringRows(ctx, `
+		SELECT DISTINCT GRANTEE
+		FROM INFORMATION_SCHEMA.USER_PRIVILEGES
+		WHERE SUBSTRING_INDEX(GRANTEE, '@', 1) = ?
+	`, quoteName(user))
+	if err != nil {
+		return nil, err
+	}
+	if len(grantees) == 0 {
+		return nil, nil
+	}
+	var hosts []string
+	for _, r := range grantees {
+		_, host, _ := strings.Cut(r[0], "@")
+		hosts = append(hosts, strings.ReplaceAll(strings.Trim(host, "'"), "''", "'"))
+	}
+
+	g := &source.Grants{User: user, Tables: map[string][]string{}}
+	acct := account{user: user}
+	acct.host, g.Uncertain, err = i.accountHost(ctx, user, hosts)
+	if err != nil {
+		return nil, err
+	}
+	g.Host = acct.host
+
+	roles, note, err := i.activeRoles(ctx, acct)
+	if err != nil {
+		return nil, err
+	}
+	if note != "" {
+		g.Uncertain = strings.TrimPrefix(g.Uncertain+"; "+note, "; ")
+	}
+	in := []string{acct.grantee()}
+	for _, r := range roles {
+		g.Roles = append(g.Roles, r.String())
+		in = append(in, r.grantee())
+	}
+	marks := strings.TrimSuffix(strings.Repeat("?, ", len(in)), ", ")
+	args := make([]any, len(in))
+	for n, v := range in {
+		args[n] = v
+	}
+
+	global, err := i.privilegeRows(ctx, `
+		SELECT PRIVILEGE_TYPE
+		FROM INFORMATION_SCHEMA.USER_PRIVILEGES
+		WHERE GRANTEE IN (`+marks+`)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := i.privilegeRows(ctx, `
+		SELECT PRIVILEGE_TYPE
+		FROM INFORMATION_SCHEMA.SCHEMA_PRIVILEGES
+		WHERE GRANTEE IN (`+marks+`) AND TABLE_SCHEMA = ?
+	`, append(args, i.schema)...)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := i.privilegeRows(ctx, `
+		SELECT PRIVILEGE_TYPE, TABLE_NAME
+		FROM INFORMATION_SCHEMA.TABLE_PRIVILEGES
+		WHERE GRANTEE IN (`+marks+`) AND TABLE_SCHEMA = ?
+	`, append(args, i.schema)...)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range global {
+		g.Global = append(g.Global, r[0])
+	}
+	for _, r := range schema {
+		g.Schema = append(g.Schema, r[0])
+	}
+	for _, r := range tables {
+		g.Tables[r[1]] = append(g.Tables[r[1]], r[0])
+	}
+	return g, nil
+}
+
+// accountHost picks which of user's hosts the connector's account is. With
+// several, the hosts user's open connections come from are matched the way
+// the server authenticates them, most specific host first; the process list
+// only shows other accounts' connections to accounts with PROCESS.
+func (i *Inspector) accountHost(ctx context.Context, user string, hosts []string) (string, string, error) {
+	sortHosts(hosts)
+	if len(hosts) == 1 {
+		return hosts[0], "", nil
+	}
+	clients, err := i.stringRows(ctx, `
+		SELECT DISTINCT COALESCE(HOST, '')
+		FROM INFORMATION_SCHEMA.PROCESSLIST
+		WHERE USER = ?
+	`, user)
+	if err != nil {
+		return "", "", err
+	}
+	for _, c := range clients {
+		if host, ok := matchHost(hosts, clientHost(c[0])); ok {
+			return host, "", nil
+		}
+	}
+	last := hosts[len(hosts)-1]
+	return last, fmt.Sprintf("user %s is defined for hosts %s and has no visible connection to tell which applies; checked %s@%s",
+		user, strings.Join(hosts, ", "), user, last), nil
+}
+
+// activeRoles returns the roles account activates on login: its default
+// roles, or every granted role when activate_all_roles_on_login is set, and
+// the roles granted to those. Servers without roles (before MySQL 8.0) have
+// none. When the role tables cannot be read the roles are unknown, and the
+// returned note says so.
+func (i *Inspector) activeRoles(ctx context.Context, acct account) ([]account, string, error) {
+	activateAll := false
+	if err := i.db.QueryRowContext(ctx, "SELECT @@GLOBAL.activate_all_roles_on_login").Scan(&activateAll); err != nil {
+		if mysqlError(err, 1193) { // unknown system variable: no roles
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	edges, err := i.stringRows(ctx, "SELECT FROM_USER, FROM_HOST, TO_USER, TO_HOST FROM mysql.role_edges")
+	if err != nil {
+		if mysqlError(err, 1142, 1044) { // no SELECT on the mysql schema
+			return nil, fmt.Sprintf("roles of %s cannot be read (needs SELECT on mysql.role_edges and mysql.default_roles)", acct), nil
+		}
+		return nil, "", err
+	}
+	granted := map[account][]account{}
+	for _, e := range edges {
+		to := account{user: e[2], host: e[3]}
+		granted[to] = append(granted[to], account{user: e[0], host: e[1]})
+	}
+
+	start := granted[acct]
+	if !activateAll {
+		defaults, err := i.stringRows(ctx, `
+			SELECT DEFAULT_ROLE_USER, DEFAULT_ROLE_HOST
+			FROM mysql.default_roles
+			WHERE USER = ? AND HOST = ?
+		`, acct.user, acct.host)
+		if err != nil {
+			if mysqlError(err, 1142, 1044) {
+				return nil, fmt.Sprintf("default roles of %s cannot be read (needs SELECT on mysql.default_roles)", acct), nil
+			}
+			return nil, "", err
+		}
+		start = nil
+		for _, d := range defaults {
+			start = append(start, account{user: d[0], host: d[1]})
+		}
+	}
+	return roleClosure(start, granted), "", nil
+}
+
+// roleClosure returns roles and every role granted to them, transitively.
+func roleClosure(roles []account, granted map[account][]account) []account {
+	seen := map[account]bool{}
+	var out []account
+	for len(roles) > 0 {
+		r := roles[0]
+		roles = roles[1:]
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		out = append(out, r)
+		roles = append(roles, granted[r]...)
+	}
+	return out
+}
+
+// sortHosts orders account hosts as the server matches them: literal hosts
+// before patterns, and patterns with longer literal prefixes first.
+func sortHosts(hosts []string) {
+	literal := func(h string) int {
+		if n := strings.IndexAny(h, "%_"); n >= 0 {
+			return n
+		}
+		return len(h) + 1<<16
+	}
+	sort.SliceStable(hosts, func(a, b int) bool { return literal(hosts[a]) > literal(hosts[b]) })
+}
+
+// matchHost returns the first of the sorted account hosts a client host
+// matches, with % and _ as wildcards.
+func matchHost(hosts []string, client string) (string, bool) {
+	for _, h := range hosts {
+		var pattern strings.Builder
+		for _, r := range h {
+			switch r {
+			case '%':
+				pattern.WriteString(".*")
+			case '_':
+				pattern.WriteString(".")
+			default:
+				pattern.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		if ok, _ := regexp.MatchString("(?i)^"+pattern.String()+"$", client); ok {
+			return h, true
+		}
+	}
+	return "", false
+}
+
+// clientHost strips the port from a process list host, e.g. 10.0.0.5:53122.
+func clientHost(host string) string {
+	if n := strings.LastIndex(host, ":"); n >= 0 && strings.Trim(host[n+1:], "0123456789") == "" {
+		return host[:n]
+	}
+	return host
+}
+
+// mysqlError reports whether err is a server error with one of the numbers.
+func mysqlError(err error, numbers ...uint16) bool {
+	var me *driver.MySQLError
+	if !errors.As(err, &me) {
+		return false
+	}
+	for _, n := range numbers {
+		if me.Number == n {
+			return true
+		}
+	}
+	return false
+}
+
+// privilegeRows runs a privilege query whose first column is PRIVILEGE_TYPE,
+// returning each row with the privilege uppercased.
+func (i *Inspector) privilegeRows(ctx context.Context, query string, args ...any) ([][]string, error) {
+	rows, err := i.stringRows(ctx, query, args...)
+	for _, r := range rows {
+		r[0] = strings.ToUpper(r[0])
+	}
+	return rows, err
+}
+
+// stringRows runs a query whose columns are all non-NULL text.
+func (i *Inspector) stringRows(ctx context.Context, query string, args ...any) ([][]string, error) {
+	rows, err := i.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var out [][]string
+	for rows.Next() {
+		vals := make([]string, len(cols))
+		ptrs := make([]any, len(cols))
+		for n := range vals {
+			ptrs[n] = &vals[n]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		out = append(out, vals)
+	}
+	return out, rows.Err()
+}
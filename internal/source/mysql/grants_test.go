@@ -0,0 +1,42 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAccountHostMatching(t *testing.T) {
+	hosts := []string{"%", "10.0.%", "10.0.0.5", "localhost"}
+	sortHosts(hosts)
+	if want := []string{"localhost", "10.0.0.5", "10.0.%", "%"}; !reflect.DeepEqual(hosts, want) {
+		t.Fatalf("expected %v, got %v", want, hosts)
+	}
+	for client, want := range map[string]string{
+		"10.0.0.5:53122": "10.0.0.5",
+		"10.0.7.1:40000": "10.0.%",
+		"192.168.1.2":    "%",
+		"localhost":      "localhost",
+	} {
+		if got, ok := matchHost(hosts, clientHost(client)); !ok || got != want {
+			t.Errorf("client %s: expected %s, got %q", client, want, got)
+		}
+	}
+	if _, ok := matchHost([]string{"10.0.0.5"}, "10.0.0.50"); ok {
+		t.Error("expected a literal host to match exactly")
+	}
+}
+
+func TestRoleClosure(t *testing.T) {
+	reader := account{"cdc_reader", "%"}
+	repl := account{"replication", "%"}
+	base := account{"base", "%"}
+	granted := map[account][]account{
+		reader: {base},
+		repl:   {base},
+		base:   {reader}, // cycles are tolerated
+	}
+	got := roleClosure([]account{reader, repl}, granted)
+	if want := []account{reader, repl, base}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
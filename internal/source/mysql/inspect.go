@@ -2,8 +2,11 @@ package mysql
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+	"github.com/alexanderjulianmartinez/data-watch/pkg/types"
 )
 
 func (i *Inspector) Inspect(ctx context.Context) (*source.InspectionResult, error) {
@@ -13,7 +16,13 @@ func (i *Inspector) Inspect(ctx context.Context) (*source.InspectionResult, erro
 	}
 
 	var results []source.TableInfo
+	var others []string
 	for _, tableName := range tables {
+		if i.filter != nil && !i.filter(tableName) {
+			others = append(others, tableName)
+			continue
+		}
+
 		schema, err := i.FetchSchema(ctx, tableName)
 		if err != nil {
 			return nil, err
@@ -29,14 +38,20 @@ func (i *Inspector) Inspect(ctx context.Context) (*source.InspectionResult, erro
 			return nil, err
 		}
 
-		// Build columns with type and nullable info
+		// Build columns with their full metadata
 		var columns []source.ColumnInfo
 		for _, col := range schema {
-			columns = append(columns, source.ColumnInfo{
-				Name:     col.Name,
-				Type:     col.Type,
-				Nullable: col.Nullable,
-			})
+			columns = append(columns, columnInfo(col))
+		}
+
+		indexes, err := i.FetchIndexes(ctx, tableName)
+		if err != nil {
+			return nil, err
+		}
+
+		foreignKeys, err := i.FetchForeignKeys(ctx, tableName)
+		if err != nil {
+			return nil, err
 		}
 
 		ddlTime, err := i.FetchTableDDLTime(ctx, tableName)
@@ -45,15 +60,104 @@ func (i *Inspector) Inspect(ctx context.Context) (*source.InspectionResult, erro
 		}
 
 		results = append(results, source.TableInfo{
-			Name:       tableName,
-			Columns:    columns,
-			PrimaryKey: primaryKey,
-			RowCount:   rowCount,
-			DDLTime:    ddlTime,
+			Name:        tableName,
+			Columns:     columns,
+			PrimaryKey:  primaryKey,
+			Indexes:     indexes,
+			ForeignKeys: foreignKeys,
+			RowCount:    rowCount,
+			DDLTime:     ddlTime,
 		})
 	}
 
+	// Without the server variables the binlog settings are not checked
+	var warnings []string
+	vars, err := i.FetchServerVariables(ctx)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("cannot read server variables; binlog settings are not checked: %v", err))
+	}
+
+	// Listing binlogs needs REPLICATION CLIENT; without it the connector
+	// offsets are not checked against the binlogs on the server, nor is the
+	// replication lag measured
+	var binaryLogs []source.BinaryLog
+	var position *source.LogPosition
+	if !strings.EqualFold(vars["log_bin"], "OFF") {
+		if binaryLogs, err = i.FetchBinaryLogs(ctx); err != nil {
+			warnings = append(warnings, fmt.Sprintf("cannot list binlogs (needs REPLICATION CLIENT); connector offsets are not checked against them: %v", err))
+		}
+		if position, err = i.FetchLogPosition(ctx); err != nil {
+			warnings = append(warnings, fmt.Sprintf("cannot read the current binlog position (needs REPLICATION CLIENT); replication lag is not measured: %v", err))
+		}
+	}
+
 	return &source.InspectionResult{
-		Tables: results,
+		Tables:          results,
+		OtherTables:     others,
+		ServerVariables: vars,
+		BinaryLogs:      binaryLogs,
+		LogPosition:     position,
+		Warnings:        warnings,
 	}, nil
 }
+
+// columnInfo converts a column read from INFORMATION_SCHEMA.
+func columnInfo(col types.ColumnSpec) source.ColumnInfo {
+	return source.ColumnInfo{
+		Name:                 col.Name,
+		Type:                 col.Type,
+		Nullable:             col.Nullable,
+		DataType:             col.DataType,
+		Length:               col.Length,
+		Precision:            col.Precision,
+		Scale:                col.Scale,
+		Unsigned:             col.Unsigned,
+		Charset:              col.Charset,
+		Collation:            col.Collation,
+		Default:              col.Default,
+		Extra:                col.Extra,
+		Position:             col.Position,
+		Generated:            col.Generated,
+		GenerationExpression: col.GenerationExpression,
+		Invisible:            col.Invisible,
+		EnumValues:           enumValues(col.Type),
+	}
+}
+
+// enumValues returns the permitted values of an ENUM or SET COLUMN_TYPE such
+// as "enum('small','medium','large')", in declaration order, or nil for other
+// types.
+func enumValues(columnType string) []string {
+	open := strings.Index(columnType, "(")
+	if open < 0 {
+		return nil
+	}
+	if base := strings.ToLower(strings.TrimSpace(columnType[:open])); base != "enum" && base != "set" {
+		return nil
+	}
+	var values []string
+	var cur strings.Builder
+	inQuote := false
+	for i := open + 1; i < len(columnType); i++ {
+		c := columnType[i]
+		switch {
+		case inQuote && c == '\\' && i+1 < len(columnType):
+			i++
+			cur.WriteByte(columnType[i])
+		case inQuote && c == '\'' && i+1 < len(columnType) && columnType[i+1] == '\'':
+			i++
+			cur.WriteByte('\'')
+		case c == '\'':
+			inQuote = !inQuote
+			if !inQuote {
+				values = append(values, cur.String())
+				cur.Reset()
+			}
+		case inQuote:
+			cur.WriteByte(c)
+		case c == ')':
+			return values
+		}
+	}
+	return values
+}
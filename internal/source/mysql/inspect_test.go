@@ -0,0 +1,35 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnumValues(t *testing.T) {
+	cases := map[string][]string{
+		"enum('small','medium','large')":     {"small", "medium", "large"},
+		"set('a,b','it''s','back\\\\slash')": {"a,b", "it's", "back\\slash"},
+		"enum('')":                           {""},
+		"varchar(10)":                        nil,
+		"enumerated(1)":                      nil,
+	}
+	for in, want := range cases {
+		if got := enumValues(in); !reflect.DeepEqual(got, want) {
+			t.Errorf("enumValues(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPickDistinct(t *testing.T) {
+	for _, tc := range []struct{ n, k, want int }{{100, 10, 10}, {5, 10, 5}, {0, 3, 0}} {
+		got := pickDistinct(tc.n, tc.k)
+		if len(got) != tc.want {
+			t.Fatalf("pickDistinct(%d, %d): expected %d values, got %v", tc.n, tc.k, tc.want, got)
+		}
+		for j, v := range got {
+			if v < 0 || v >= tc.n || (j > 0 && v <= got[j-1]) {
+				t.Fatalf("pickDistinct(%d, %d): expected ascending distinct values in range, got %v", tc.n, tc.k, got)
+			}
+		}
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
 	"github.com/alexanderjulianmartinez/data-watch/pkg/types"
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -15,6 +17,7 @@ type Inspector struct {
 	db       *sql.DB
 	schema   string
 	timemout time.Duration
+	filter   func(table string) bool
 }
 
 func NewInspector(dsn string, schema string) (*Inspector, error) {
@@ -37,6 +40,24 @@ func NewInspector(dsn string, schema string) (*Inspector, error) {
 	}, nil
 }
 
+func (i *Inspector) Name() string {
+	return "mysql"
+}
+
+// Close releases the database connection pool.
+func (i *Inspector) Close() error {
+	if i.db == nil {
+		return nil
+	}
+	return i.db.Close()
+}
+
+// SetTableFilter restricts Inspect to tables for which match returns true.
+// A nil filter inspects every table in the schema.
+func (i *Inspector) SetTableFilter(match func(table string) bool) {
+	i.filter = match
+}
+
 func (i *Inspector) FetchAllTableNames(ctx context.Context) ([]string, error) {
 	rows, err := i.db.QueryContext(ctx, `
 		SELECT TABLE_NAME
@@ -83,10 +104,91 @@ func (i *Inspector) FetchPrimaryKey(ctx context.Context, tableName string) ([]st
 	return pkColumns, rows.Err()
 }
 
+// FetchIndexes returns the table's secondary indexes in name order. Indexes
+// with functional key parts are skipped, since no column list describes them.
+func (i *Inspector) FetchIndexes(ctx context.Context, tableName string) ([]source.IndexInfo, error) {
+	rows, err := i.db.QueryContext(ctx, `
+		SELECT INDEX_NAME, NON_UNIQUE, COLUMN_NAME
+		FROM INFORMATION_SCHEMA.STATISTICS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND INDEX_NAME <> 'PRIMARY'
+		ORDER BY INDEX_NAME, SEQ_IN_INDEX
+	`, i.schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []source.IndexInfo
+	functional := map[string]bool{}
+	for rows.Next() {
+		var name string
+		var nonUnique int
+		var column sql.NullString
+		if err := rows.Scan(&name, &nonUnique, &column); err != nil {
+			return nil, err
+		}
+		if !column.Valid {
+			functional[name] = true
+			continue
+		}
+		if n := len(indexes); n == 0 || indexes[n-1].Name != name {
+			indexes = append(indexes, source.IndexInfo{Name: name, Unique: nonUnique == 0})
+		}
+		idx := &indexes[len(indexes)-1]
+		idx.Columns = append(idx.Columns, column.String)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	var out []source.IndexInfo
+	for _, idx := range indexes {
+		if !functional[idx.Name] {
+			out = append(out, idx)
+		}
+	}
+	return out, nil
+}
+
+// FetchForeignKeys returns the table's foreign key constraints in name order.
+func (i *Inspector) FetchForeignKeys(ctx context.Context, tableName string) ([]source.ForeignKeyInfo, error) {
+	rows, err := i.db.QueryContext(ctx, `
+		SELECT CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_SCHEMA, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+		FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION
+	`, i.schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fks []source.ForeignKeyInfo
+	for rows.Next() {
+		var name, column, refSchema, refTable, refColumn string
+		if err := rows.Scan(&name, &column, &refSchema, &refTable, &refColumn); err != nil {
+			return nil, err
+		}
+		if n := len(fks); n == 0 || fks[n-1].Name != name {
+			fk := source.ForeignKeyInfo{Name: name, RefTable: refTable}
+			if refSchema != i.schema {
+				fk.RefSchema = refSchema
+			}
+			fks = append(fks, fk)
+		}
+		fk := &fks[len(fks)-1]
+		fk.Columns = append(fk.Columns, column)
+		fk.RefColumns = append(fk.RefColumns, refColumn)
+	}
+	return fks, rows.Err()
+}
+
 func (i *Inspector) FetchSchema(ctx context.Context, tableName string) ([]types.ColumnSpec, error) {
 
 	rows, err := i.db.QueryContext(ctx, `
-		SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE
+		SELECT COLUMN_NAME, COLUMN_TYPE, DATA_TYPE, IS_NULLABLE,
+			CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE,
+			CHARACTER_SET_NAME, COLLATION_NAME, COLUMN_DEFAULT, EXTRA, ORDINAL_POSITION,
+			GENERATION_EXPRESSION
 		FROM INFORMATION_SCHEMA.COLUMNS
 		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
 		ORDER BY ORDINAL_POSITION
@@ -97,19 +199,168 @@ func (i *Inspector) FetchSchema(ctx context.Context, tableName string) ([]types.
 	defer rows.Close()
 	var cols []types.ColumnSpec
 	for rows.Next() {
-		var name, dataType, nullable string
-		if err := rows.Scan(&name, &dataType, &nullable); err != nil {
+		var name, columnType, dataType, nullable, extra string
+		var length, precision, scale sql.NullInt64
+		var charset, collation, def, genExpr sql.NullString
+		var position int
+		if err := rows.Scan(&name, &columnType, &dataType, &nullable, &length, &precision, &scale,
+			&charset, &collation, &def, &extra, &position, &genExpr); err != nil {
 			return nil, err
 		}
-		cols = append(cols, types.ColumnSpec{
-			Name:     name,
-			Type:     dataType,
-			Nullable: nullable == "YES",
-		})
+		col := types.ColumnSpec{
+			Name:      name,
+			Type:      columnType, // full type, e.g. "int unsigned" or "decimal(10,2)"
+			Nullable:  nullable == "YES",
+			DataType:  dataType,
+			Length:    nullInt(length),
+			Precision: nullInt(precision),
+			Scale:     nullInt(scale),
+			Unsigned:  strings.Contains(strings.ToLower(columnType), "unsigned"),
+			Charset:   charset.String,
+			Collation: collation.String,
+			Extra:     extra,
+			Position:  position,
+		}
+		if def.Valid {
+			col.Default = &def.String
+		}
+		// EXTRA holds "VIRTUAL GENERATED" or "STORED GENERATED" for generated
+		// columns (DEFAULT_GENERATED only marks an expression default) and
+		// "INVISIBLE" for invisible ones
+		upperExtra := strings.ToUpper(extra)
+		switch {
+		case strings.Contains(upperExtra, "VIRTUAL GENERATED"):
+			col.Generated = "virtual"
+		case strings.Contains(upperExtra, "STORED GENERATED"):
+			col.Generated = "stored"
+		}
+		if col.Generated != "" {
+			col.GenerationExpression = genExpr.String
+		}
+		col.Invisible = strings.Contains(upperExtra, "INVISIBLE")
+		cols = append(cols, col)
 	}
 	return cols, rows.Err()
 }
 
+func nullInt(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
+
+// serverVariables are the global variables that decide whether Debezium can
+// read the binlog and for how long it can fall behind.
+var serverVariables = []string{
+	"log_bin",
+	"binlog_format",
+	"binlog_row_image",
+	"binlog_row_metadata",
+	"gtid_mode",
+	"enforce_gtid_consistency",
+	"binlog_expire_logs_seconds",
+	"expire_logs_days",
+	"gtid_purged",
+}
+
+// FetchServerVariables returns the binlog related global variables, keyed by
+// lowercase name. Variables the server does not have, like
+// binlog_row_metadata before 8.0, are absent.
+func (i *Inspector) FetchServerVariables(ctx context.Context) (map[string]string, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(serverVariables)), ",")
+	args := make([]any, len(serverVariables))
+	for n, v := range serverVariables {
+		args[n] = v
+	}
+	rows, err := i.db.QueryContext(ctx, "SHOW GLOBAL VARIABLES WHERE Variable_name IN ("+placeholders+")", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vars := map[string]string{}
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		vars[strings.ToLower(name)] = value
+	}
+	return vars, rows.Err()
+}
+
+// FetchBinaryLogs lists the binlog files on the server, oldest first, as
+// SHOW BINARY LOGS reports them.
+func (i *Inspector) FetchBinaryLogs(ctx context.Context) ([]source.BinaryLog, error) {
+	rows, err := i.db.QueryContext(ctx, "SHOW BINARY LOGS")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// MySQL 8.0 adds an Encrypted column
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var logs []source.BinaryLog
+	for rows.Next() {
+		var log source.BinaryLog
+		dest := []any{&log.Name, &log.Size}
+		for range cols[2:] {
+			dest = append(dest, new(sql.RawBytes))
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, rows.Err()
+}
+
+// FetchLogPosition returns the server's current binlog position. It uses
+// SHOW BINARY LOG STATUS, which replaced SHOW MASTER STATUS in MySQL 8.2, and
+// returns nil when binary logging is disabled.
+func (i *Inspector) FetchLogPosition(ctx context.Context) (*source.LogPosition, error) {
+	rows, err := i.db.QueryContext(ctx, "SHOW BINARY LOG STATUS")
+	if err != nil {
+		if rows, err = i.db.QueryContext(ctx, "SHOW MASTER STATUS"); err != nil {
+			return nil, err
+		}
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	values := make([]sql.NullString, len(cols))
+	dest := make([]any, len(cols))
+	for n := range values {
+		dest[n] = &values[n]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+	pos := &source.LogPosition{}
+	for n, c := range cols {
+		switch c {
+		case "File":
+			pos.File = values[n].String
+		case "Position":
+			pos.Position, _ = strconv.ParseInt(values[n].String, 10, 64)
+		case "Executed_Gtid_Set":
+			pos.GTIDSet = strings.Join(strings.Fields(values[n].String), "")
+		}
+	}
+	return pos, nil
+}
+
 func (i *Inspector) FetchRowCount(ctx context.Context, tableName string) (int64, error) {
 	var count int64
 	query := fmt.Sprintf("SELECT COUNT(*) FROM `%s`", tableName)
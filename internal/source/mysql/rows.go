@@ -0,0 +1,196 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// fetchRowsBatch is how many keys FetchRows looks up per query.
+const fetchRowsBatch = 500
+
+// sampleRangeRows is how many consecutive rows each range SampleRows picks
+// holds.
+const sampleRangeRows = 10
+
+// SampleRows returns up to n rows of table chosen at random. The table is
+// split into ranges of sampleRangeRows rows in keyColumns order and whole
+// ranges are picked at random, so every row is about as likely to be picked
+// wherever its key falls. The starts of the picked ranges are found by
+// walking the key in order, reading only the key columns.
+func (i *Inspector) SampleRows(ctx context.Context, table string, keyColumns []string, n int) ([]source.Row, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	if len(keyColumns) == 0 {
+		return nil, fmt.Errorf("no key columns given for table %s", table)
+	}
+	var count int64
+	if err := i.readOnly(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+i.from(table)).Scan(&count)
+	}); err != nil {
+		return nil, err
+	}
+	ranges := int((count + sampleRangeRows - 1) / sampleRangeRows)
+	starts := pickDistinct(ranges, (n+sampleRangeRows-1)/sampleRangeRows)
+
+	var out []source.Row
+	var after []string // key of the row before position pos
+	pos := 0
+	for _, r := range starts {
+		start := r * sampleRangeRows
+		if start > pos {
+			key, err := i.ChunkBound(ctx, table, keyColumns, after, start-pos)
+			if err != nil {
+				return nil, err
+			}
+			if key == nil {
+				break // rows were deleted since counting
+			}
+			after = key
+		}
+		rows, err := i.ReadRange(ctx, table, keyColumns, after, nil, sampleRangeRows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rows...)
+		if len(rows) < sampleRangeRows {
+			break
+		}
+		last := rows[len(rows)-1]
+		after = make([]string, len(keyColumns))
+		for n, c := range keyColumns {
+			if v := last[c]; v != nil {
+				after[n] = *v
+			}
+		}
+		pos = start + len(rows)
+	}
+	rand.Shuffle(len(out), func(a, b int) { out[a], out[b] = out[b], out[a] })
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out, nil
+}
+
+// pickDistinct returns k distinct integers in [0, n) chosen at random, in
+// ascending order, using Floyd's algorithm.
+func pickDistinct(n, k int) []int {
+	k = min(k, n)
+	chosen := make(map[int]bool, k)
+	for j := n - k; j < n; j++ {
+		if t := rand.IntN(j + 1); chosen[t] {
+			chosen[j] = true
+		} else {
+			chosen[t] = true
+		}
+	}
+	out := make([]int, 0, k)
+	for v := range chosen {
+		out = append(out, v)
+	}
+	sort.Ints(out)
+	return out
+}
+
+// FetchRows returns the rows of table whose keyColumns equal one of keys.
+func (i *Inspector) FetchRows(ctx context.Context, table string, keyColumns []string, keys [][]string) ([]source.Row, error) {
+	if len(keyColumns) == 0 {
+		return nil, fmt.Errorf("no key columns given for table %s", table)
+	}
+	cols := make([]string, len(keyColumns))
+	for n, c := range keyColumns {
+		cols[n] = quoteIdent(c)
+	}
+	tuple := "(" + strings.TrimSuffix(strings.Repeat("?,", len(cols)), ",") + ")"
+	target := strings.Join(cols, ", ")
+	if len(cols) > 1 {
+		target = "(" + target + ")"
+	}
+
+	var out []source.Row
+	for start := 0; start < len(keys); start += fetchRowsBatch {
+		batch := keys[start:min(start+fetchRowsBatch, len(keys))]
+		var args []interface{}
+		for _, k := range batch {
+			if len(k) != len(cols) {
+				return nil, fmt.Errorf("key %v does not match key columns %v", k, keyColumns)
+			}
+			for _, v := range k {
+				args = append(args, v)
+			}
+		}
+		placeholders := tuple
+		if len(cols) == 1 {
+			placeholders = "?"
+		}
+		list := strings.TrimSuffix(strings.Repeat(placeholders+",", len(batch)), ",")
+		query := fmt.Sprintf("SELECT * FROM %s WHERE %s IN (%s)", quoteIdent(table), target, list)
+		rows, err := i.queryRows(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rows...)
+	}
+	return out, nil
+}
+
+// ReadRange returns the rows of table in the key range, in key order.
+func (i *Inspector) ReadRange(ctx context.Context, table string, keyColumns []string, after, upTo []string, limit int) ([]source.Row, error) {
+	query, args := source.RangeQuery(i.from(table), keyColumns, after, upTo, limit, quoteIdent, placeholder)
+	return i.queryRows(ctx, query, args...)
+}
+
+// queryRows runs a row query in a read-only transaction and renders each row
+// as text.
+func (i *Inspector) queryRows(ctx context.Context, query string, args ...interface{}) ([]source.Row, error) {
+	var out []source.Row
+	err := i.readOnly(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		out, err = source.ScanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+// readOnly runs fn in a read-only transaction, on a connection whose session
+// time zone is UTC so TIMESTAMP values compare with the UTC instants CDC
+// events and sinks hold.
+func (i *Inspector) readOnly(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	conn, err := i.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var tz string
+	if err := conn.QueryRowContext(ctx, "SELECT @@session.time_zone").Scan(&tz); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "SET time_zone = '+00:00'"); err != nil {
+		return err
+	}
+	// The connection goes back to the pool afterwards
+	defer conn.ExecContext(context.Background(), "SET time_zone = ?", tz)
+
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	return fn(tx)
+}
+
+// quoteIdent quotes a MySQL identifier.
+func quoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// TableColumns returns the columns of table in ordinal order, or nil when the
+// table does not exist.
+func (i *Inspector) TableColumns(ctx context.Context, table string) ([]source.ColumnInfo, error) {
+	cols, err := i.FetchColumns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cols[table], nil
+}
+
+// ChunkBound returns the key of the n-th row of table after the key after, or
+// nil when fewer rows remain. Only the primary key index is read.
+func (i *Inspector) ChunkBound(ctx context.Context, table string, keyColumns, after []string, n int) ([]string, error) {
+	query, args := source.BoundQuery(i.from(table), keyColumns, after, n, pq.QuoteIdentifier, placeholder)
+	var key []string
+	err := i.readOnly(ctx, func(tx *sql.Tx) error {
+		values := make([]sql.NullString, len(keyColumns))
+		dest := make([]interface{}, len(values))
+		for n := range values {
+			dest[n] = &values[n]
+		}
+		err := tx.QueryRowContext(ctx, query, args...).Scan(dest...)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			key = append(key, v.String)
+		}
+		return nil
+	})
+	return key, err
+}
+
+// ChecksumRange counts the rows of table in the key range and sums a 60-bit
+// prefix of the MD5 of each row's canonical values, in the database.
+func (i *Inspector) ChecksumRange(ctx context.Context, table string, keyColumns []string, columns []source.ChecksumColumn, after, upTo []string) (source.RangeChecksum, error) {
+	values := make([]string, len(columns))
+	for n, c := range columns {
+		// NULL and the empty string must differ
+		values[n] = fmt.Sprintf("COALESCE('V' || %s, 'N')", canonicalExpr(c))
+	}
+	row := fmt.Sprintf("concat_ws(chr(31), %s)", strings.Join(values, ", "))
+	sum := fmt.Sprintf("COALESCE(SUM(('x' || left(md5(%s), 15))::bit(60)::bigint), 0)", row)
+	query, args := source.ChecksumQuery(i.from(table), sum, keyColumns, after, upTo, pq.QuoteIdentifier, placeholder)
+
+	var res source.RangeChecksum
+	err := i.readOnly(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, query, args...).Scan(&res.Rows, &res.Sum)
+	})
+	return res, err
+}
+
+// canonicalExpr renders a column in its canonical form. Sinks store values in
+// their own types, e.g. a MySQL TINYINT(1) as boolean, so the expression
+// depends on the column's Postgres type; values of other types render as
+// text.
+func canonicalExpr(c source.ChecksumColumn) string {
+	col := pq.QuoteIdentifier(c.Column.Name)
+	typ := strings.ToLower(c.Column.DataType)
+	text := col + "::text"
+	switch c.Form {
+	case source.FormInteger:
+		switch typ {
+		case "boolean":
+			return fmt.Sprintf("CASE WHEN %s THEN '1' ELSE '0' END", col)
+		case "bit":
+			return col + "::bigint::text"
+		}
+	case source.FormDecimal:
+		if isNumeric(typ) {
+			return fmt.Sprintf("round(%s::numeric, %d)::text", col, c.Scale)
+		}
+	case source.FormDate:
+		if typ == "date" || strings.HasPrefix(typ, "timestamp") {
+			return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD')", col)
+		}
+	case source.FormDatetime:
+		if typ == "date" || strings.HasPrefix(typ, "timestamp") {
+			return fmt.Sprintf("rtrim(rtrim(to_char(%s, 'YYYY-MM-DD HH24:MI:SS.US'), '0'), '.')", col)
+		}
+	case source.FormTime:
+		return fmt.Sprintf("CASE WHEN strpos(%[1]s, '.') > 0 THEN rtrim(rtrim(%[1]s, '0'), '.') ELSE %[1]s END", text)
+	case source.FormBinary:
+		if typ == "bytea" {
+			return fmt.Sprintf("upper(encode(%s, 'hex'))", col)
+		}
+	case source.FormJSON:
+		if typ == "json" || typ == "jsonb" {
+			// jsonb orders keys as MySQL JSON does: by length, then bytes
+			return col + "::jsonb::text"
+		}
+	}
+	return text
+}
+
+func isNumeric(typ string) bool {
+	switch typ {
+	case "smallint", "integer", "bigint", "numeric", "real", "double precision":
+		return true
+	}
+	return false
+}
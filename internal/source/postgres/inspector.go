@@ -0,0 +1,295 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// rowSource runs a named catalog query and returns every column as a nullable
+// string. Queries are named so tests can substitute recorded fixtures for a
+// live database.
+type rowSource interface {
+	Query(ctx context.Context, name, query string, args ...any) ([][]sql.NullString, error)
+}
+
+type Inspector struct {
+	db     *sql.DB
+	rows   rowSource
+	schema string
+	filter func(table string) bool
+}
+
+func NewInspector(dsn string, schema string) (*Inspector, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("postgres ping failed: %w", err)
+	}
+
+	return &Inspector{
+		db:     db,
+		rows:   dbRows{db: db},
+		schema: schema,
+	}, nil
+}
+
+func (i *Inspector) Name() string {
+	return "postgres"
+}
+
+// Close releases the database connection pool.
+func (i *Inspector) Close() error {
+	if i.db == nil {
+		return nil
+	}
+	return i.db.Close()
+}
+
+// SetTableFilter restricts Inspect to tables for which match returns true.
+// A nil filter inspects every table in the schema.
+func (i *Inspector) SetTableFilter(match func(table string) bool) {
+	i.filter = match
+}
+
+func (i *Inspector) Inspect(ctx context.Context) (*source.InspectionResult, error) {
+	tables, err := i.FetchAllTableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := i.FetchColumns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	primaryKeys, err := i.FetchPrimaryKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ddlTimes, err := i.FetchTableDDLTimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []source.TableInfo
+	var others []string
+	for _, tableName := range tables {
+		if i.filter != nil && !i.filter(tableName) {
+			others = append(others, tableName)
+			continue
+		}
+
+		rowCount, err := i.FetchRowCount(ctx, tableName)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, source.TableInfo{
+			Name:       tableName,
+			Columns:    columns[tableName],
+			PrimaryKey: primaryKeys[tableName],
+			RowCount:   rowCount,
+			DDLTime:    ddlTimes[tableName],
+		})
+	}
+
+	return &source.InspectionResult{
+		Tables:      results,
+		OtherTables: others,
+	}, nil
+}
+
+func (i *Inspector) FetchAllTableNames(ctx context.Context) ([]string, error) {
+	rows, err := i.rows.Query(ctx, "tables", `
+		SELECT table_name
+		FROM information_schema.tables
+		WHERE table_schema = $1 AND table_type = 'BASE TABLE'
+		ORDER BY table_name
+	`, i.schema)
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for _, r := range rows {
+		tables = append(tables, r[0].String)
+	}
+	return tables, nil
+}
+
+// FetchColumns returns the columns of every table in the schema, keyed by
+// table name and in ordinal order.
+func (i *Inspector) FetchColumns(ctx context.Context) (map[string][]source.ColumnInfo, error) {
+	rows, err := i.rows.Query(ctx, "columns", `
+		SELECT table_name, column_name, data_type, is_nullable,
+			character_maximum_length, numeric_precision, numeric_scale,
+			collation_name, column_default, ordinal_position, is_identity, is_generated
+		FROM information_schema.columns
+		WHERE table_schema = $1
+		ORDER BY table_name, ordinal_position
+	`, i.schema)
+	if err != nil {
+		return nil, err
+	}
+	cols := map[string][]source.ColumnInfo{}
+	for _, r := range rows {
+		col := source.ColumnInfo{
+			Name:      r[1].String,
+			Type:      r[2].String,
+			Nullable:  r[3].String == "YES",
+			DataType:  r[2].String,
+			Length:    atoiOrNil(r[4]),
+			Precision: atoiOrNil(r[5]),
+			Scale:     atoiOrNil(r[6]),
+			Collation: r[7].String,
+		}
+		if r[8].Valid {
+			col.Default = &r[8].String
+		}
+		if p := atoiOrNil(r[9]); p != nil {
+			col.Position = *p
+		}
+		switch {
+		case r[10].String == "YES":
+			col.Extra = "identity"
+		case r[11].String == "ALWAYS":
+			// Postgres only has stored generated columns
+			col.Extra = "STORED GENERATED"
+			col.Generated = "stored"
+		}
+		cols[r[0].String] = append(cols[r[0].String], col)
+	}
+	return cols, nil
+}
+
+// atoiOrNil parses a nullable integer column, returning nil for NULL.
+func atoiOrNil(v sql.NullString) *int {
+	if !v.Valid {
+		return nil
+	}
+	n, err := strconv.Atoi(v.String)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+// FetchPrimaryKeys returns the primary key columns of every table in the
+// schema, keyed by table name and in key order.
+func (i *Inspector) FetchPrimaryKeys(ctx context.Context) (map[string][]string, error) {
+	rows, err := i.rows.Query(ctx, "primary_keys", `
+		SELECT tc.table_name, kcu.column_name
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu
+			ON kcu.constraint_schema = tc.constraint_schema
+			AND kcu.constraint_name = tc.constraint_name
+			AND kcu.table_name = tc.table_name
+		WHERE tc.table_schema = $1 AND tc.constraint_type = 'PRIMARY KEY'
+		ORDER BY tc.table_name, kcu.ordinal_position
+	`, i.schema)
+	if err != nil {
+		return nil, err
+	}
+	pks := map[string][]string{}
+	for _, r := range rows {
+		pks[r[0].String] = append(pks[r[0].String], r[1].String)
+	}
+	return pks, nil
+}
+
+func (i *Inspector) FetchRowCount(ctx context.Context, tableName string) (int64, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.%s", pq.QuoteIdentifier(i.schema), pq.QuoteIdentifier(tableName))
+	rows, err := i.rows.Query(ctx, "row_count."+tableName, query)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return 0, fmt.Errorf("row count for %s returned no rows", tableName)
+	}
+	return strconv.ParseInt(rows[0][0].String, 10, 64)
+}
+
+// ddlTimesUnavailable are the SQLSTATEs meaning commit timestamps cannot be
+// read: track_commit_timestamp is off, the function does not exist, or the
+// account may not call it.
+var ddlTimesUnavailable = map[pq.ErrorCode]bool{
+	"55000": true, // object_not_in_prerequisite_state
+	"42883": true, // undefined_function
+	"42501": true, // insufficient_privilege
+}
+
+// FetchTableDDLTimes returns a best-effort DDL timestamp per table. Postgres
+// does not record DDL times, but every ALTER rewrites the table's pg_class
+// row, so the commit timestamp of that row approximates the last DDL change.
+// This requires track_commit_timestamp=on; when it is off, the server is
+// older than 9.5 or the account may not call pg_xact_commit_timestamp, the
+// timestamps are unavailable and an empty map is returned. Other errors are
+// returned.
+func (i *Inspector) FetchTableDDLTimes(ctx context.Context) (map[string]*time.Time, error) {
+	rows, err := i.rows.Query(ctx, "ddl_times", `
+		SELECT c.relname, pg_xact_commit_timestamp(c.xmin)
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p')
+	`, i.schema)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && ddlTimesUnavailable[pqErr.Code] {
+			return map[string]*time.Time{}, nil
+		}
+		return nil, err
+	}
+	times := map[string]*time.Time{}
+	for _, r := range rows {
+		if !r[1].Valid {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339Nano, r[1].String); err == nil {
+			t = t.UTC()
+			times[r[0].String] = &t
+		}
+	}
+	return times, nil
+}
+
+// dbRows is the rowSource backed by a live database connection.
+type dbRows struct {
+	db *sql.DB
+}
+
+func (d dbRows) Query(ctx context.Context, name, query string, args ...any) ([][]sql.NullString, error) {
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s query: %w", name, err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var out [][]sql.NullString
+	for rows.Next() {
+		vals := make([]sql.NullString, len(cols))
+		ptrs := make([]any, len(cols))
+		for j := range vals {
+			ptrs[j] = &vals[j]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		out = append(out, vals)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// fixtureRows serves recorded catalog query results keyed by query name.
+type fixtureRows map[string][][]*string
+
+func (f fixtureRows) Query(ctx context.Context, name, query string, args ...any) ([][]sql.NullString, error) {
+	rows, ok := f[name]
+	if !ok {
+		return nil, fmt.Errorf("no fixture for query %s", name)
+	}
+	var out [][]sql.NullString
+	for _, r := range rows {
+		vals := make([]sql.NullString, len(r))
+		for j, v := range r {
+			if v != nil {
+				vals[j] = sql.NullString{String: *v, Valid: true}
+			}
+		}
+		out = append(out, vals)
+	}
+	return out, nil
+}
+
+func loadFixture(t *testing.T, path string) fixtureRows {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f fixtureRows
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestInspectFromFixtures(t *testing.T) {
+	i := &Inspector{rows: loadFixture(t, "testdata/catalog.json"), schema: "public"}
+	i.SetTableFilter(func(name string) bool { return name != "audit_log" })
+
+	res, err := i.Inspect(context.Background())
+	if err != nil {
+		t.Fatalf("inspect error: %v", err)
+	}
+	if len(res.Tables) != 2 {
+		t.Fatalf("expected 2 tables after filtering, got %d: %+v", len(res.Tables), res.Tables)
+	}
+
+	orders := res.Tables[0]
+	if orders.Name != "orders" || orders.RowCount != 42 {
+		t.Fatalf("unexpected orders table: %+v", orders)
+	}
+	if !reflect.DeepEqual(orders.PrimaryKey, []string{"tenant_id", "id"}) {
+		t.Fatalf("expected composite primary key in key order, got %v", orders.PrimaryKey)
+	}
+	ptr := func(n int) *int { return &n }
+	zero := "0.00"
+	wantCols := []source.ColumnInfo{
+		{Name: "id", Type: "bigint", Nullable: false, DataType: "bigint", Precision: ptr(64), Scale: ptr(0), Extra: "identity", Position: 1},
+		{Name: "tenant_id", Type: "integer", Nullable: false, DataType: "integer", Precision: ptr(32), Scale: ptr(0), Position: 2},
+		{Name: "total", Type: "numeric", Nullable: true, DataType: "numeric", Precision: ptr(12), Scale: ptr(2), Default: &zero, Position: 3},
+	}
+	if !reflect.DeepEqual(orders.Columns, wantCols) {
+		t.Fatalf("expected columns %v, got %v", wantCols, orders.Columns)
+	}
+	wantDDL := time.Date(2026, 1, 28, 12, 34, 56, 123456000, time.UTC)
+	if orders.DDLTime == nil || !orders.DDLTime.Equal(wantDDL) {
+		t.Fatalf("expected DDL time %v, got %v", wantDDL, orders.DDLTime)
+	}
+
+	users := res.Tables[1]
+	if users.Name != "users" || users.DDLTime != nil {
+		t.Fatalf("expected users without DDL time, got %+v", users)
+	}
+}
+
+// failingRows serves fixtures but fails the queries in fail.
+type failingRows struct {
+	fixtureRows
+	fail map[string]error
+}
+
+func (f failingRows) Query(ctx context.Context, name, query string, args ...any) ([][]sql.NullString, error) {
+	if err, ok := f.fail[name]; ok {
+		return nil, fmt.Errorf("%s query: %w", name, err)
+	}
+	return f.fixtureRows.Query(ctx, name, query, args...)
+}
+
+func TestDDLTimesUnavailable(t *testing.T) {
+	f := loadFixture(t, "testdata/catalog.json")
+	// track_commit_timestamp=off
+	off := &pq.Error{Code: "55000", Message: "could not get commit timestamp data"}
+	i := &Inspector{rows: failingRows{f, map[string]error{"ddl_times": off}}, schema: "public"}
+	res, err := i.Inspect(context.Background())
+	if err != nil {
+		t.Fatalf("expected inspection to succeed without DDL times, got %v", err)
+	}
+	for _, tbl := range res.Tables {
+		if tbl.DDLTime != nil {
+			t.Fatalf("expected no DDL times, got %v for %s", tbl.DDLTime, tbl.Name)
+		}
+	}
+}
+
+func TestDDLTimesError(t *testing.T) {
+	f := loadFixture(t, "testdata/catalog.json")
+	lost := errors.New("connection reset by peer")
+	i := &Inspector{rows: failingRows{f, map[string]error{"ddl_times": lost}}, schema: "public"}
+	if _, err := i.Inspect(context.Background()); !errors.Is(err, lost) {
+		t.Fatalf("expected the DDL time query error, got %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package postgres
+
+import (
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+func init() {
+	source.Register("postgres", func(cfg *config.Config) (source.Inspector, error) {
+		i, err := NewInspector(cfg.Source.DSN, cfg.Source.Schema)
+		if err != nil {
+			return nil, err
+		}
+		i.SetTableFilter(cfg.IncludesTable)
+		return i, nil
+	})
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/lib/pq"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+)
+
+// ReadRange returns the rows of table in the key range, in key order.
+func (i *Inspector) ReadRange(ctx context.Context, table string, keyColumns []string, after, upTo []string, limit int) ([]source.Row, error) {
+	query, args := source.RangeQuery(i.from(table), keyColumns, after, upTo, limit, pq.QuoteIdentifier, placeholder)
+	var out []source.Row
+	err := i.readOnly(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		out, err = source.ScanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+// readOnly runs fn in a read-only transaction with the time zone set to UTC,
+// so timestamptz values render as UTC.
+func (i *Inspector) readOnly(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := i.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, "SET LOCAL TIME ZONE 'UTC'"); err != nil {
+		return err
+	}
+	return fn(tx)
+}
+
+func (i *Inspector) from(table string) string {
+	return pq.QuoteIdentifier(i.schema) + "." + pq.QuoteIdentifier(table)
+}
+
+func placeholder(n int) string { return "$" + strconv.Itoa(n) }
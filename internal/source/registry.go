@@ -0,0 +1,60 @@
+package source
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+)
+
+// Factory creates an Inspector for the loaded config. Factories are expected
+// to apply the configured table scope themselves.
+type Factory func(cfg *config.Config) (Inspector, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+func init() {
+	config.SetKnownTypes("source.type", Types)
+}
+
+// Register makes a source inspector available under the given source.type.
+// It panics if typ is empty, factory is nil or typ is registered twice.
+func Register(typ string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if typ == "" || factory == nil {
+		panic("source: Register requires a type and a factory")
+	}
+	if _, dup := registry[typ]; dup {
+		panic("source: Register called twice for type " + typ)
+	}
+	registry[typ] = factory
+}
+
+// Types returns the registered source types in sorted order.
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	var types []string
+	for t := range registry {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// New creates the inspector registered for cfg.Source.Type.
+func New(cfg *config.Config) (Inspector, error) {
+	registryMu.RLock()
+	factory, ok := registry[cfg.Source.Type]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported source.type %q (registered: %s)", cfg.Source.Type, strings.Join(Types(), ", "))
+	}
+	return factory(cfg)
+}
@@ -0,0 +1,44 @@
+package source
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+)
+
+type stubInspector struct{ schema string }
+
+func (s *stubInspector) Name() string { return "stub" }
+func (s *stubInspector) Inspect(ctx context.Context) (*InspectionResult, error) {
+	return &InspectionResult{Tables: []TableInfo{{Name: s.schema}}}, nil
+}
+func (s *stubInspector) Close() error { return nil }
+
+func TestRegistry(t *testing.T) {
+	Register("stub", func(cfg *config.Config) (Inspector, error) {
+		return &stubInspector{schema: cfg.Source.Schema}, nil
+	})
+
+	i, err := New(&config.Config{Source: config.SourceConfig{Type: "stub", Schema: "db"}})
+	if err != nil {
+		t.Fatalf("expected registered inspector, got: %v", err)
+	}
+	res, err := i.Inspect(context.Background())
+	if err != nil || len(res.Tables) != 1 || res.Tables[0].Name != "db" {
+		t.Fatalf("unexpected inspection result: %+v, %v", res, err)
+	}
+
+	_, err = New(&config.Config{Source: config.SourceConfig{Type: "oracle"}})
+	if err == nil || !strings.Contains(err.Error(), "stub") {
+		t.Fatalf("expected unsupported type error listing registered types, got: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected duplicate registration to panic")
+		}
+	}()
+	Register("stub", func(cfg *config.Config) (Inspector, error) { return nil, nil })
+}
@@ -0,0 +1,113 @@
+package source
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// keyRange is the WHERE clause selecting the rows of a key range, with the
+// bound values it takes as arguments.
+type keyRange struct {
+	cols        []string // quoted key columns
+	where       string   // empty for an open range
+	args        []interface{}
+	placeholder func(n int) string
+}
+
+func newKeyRange(keyColumns []string, after, upTo []string, quote func(string) string, placeholder func(n int) string) *keyRange {
+	r := &keyRange{placeholder: placeholder}
+	for _, c := range keyColumns {
+		r.cols = append(r.cols, quote(c))
+	}
+	key := strings.Join(r.cols, ", ")
+	if len(r.cols) > 1 {
+		key = "(" + key + ")"
+	}
+	var where []string
+	if after != nil {
+		where = append(where, key+" > "+r.bound(after))
+	}
+	if upTo != nil {
+		where = append(where, key+" <= "+r.bound(upTo))
+	}
+	if len(where) > 0 {
+		r.where = " WHERE " + strings.Join(where, " AND ")
+	}
+	return r
+}
+
+func (r *keyRange) bound(values []string) string {
+	marks := make([]string, len(values))
+	for n, v := range values {
+		r.args = append(r.args, v)
+		marks[n] = r.placeholder(len(r.args))
+	}
+	if len(marks) == 1 {
+		return marks[0]
+	}
+	return "(" + strings.Join(marks, ", ") + ")"
+}
+
+func (r *keyRange) orderBy() string {
+	return " ORDER BY " + strings.Join(r.cols, ", ")
+}
+
+// RangeQuery builds the query for RangeReader.ReadRange over from, a quoted
+// table name. quote quotes a column name and placeholder returns the
+// parameter marker for the n-th argument, counting from 1.
+func RangeQuery(from string, keyColumns, after, upTo []string, limit int, quote func(string) string, placeholder func(n int) string) (string, []interface{}) {
+	r := newKeyRange(keyColumns, after, upTo, quote, placeholder)
+	query := "SELECT * FROM " + from + r.where + r.orderBy()
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	return query, r.args
+}
+
+// BoundQuery builds the query for RangeChecksummer.ChunkBound, which reads
+// only the key of the n-th row after the key after.
+func BoundQuery(from string, keyColumns, after []string, n int, quote func(string) string, placeholder func(n int) string) (string, []interface{}) {
+	r := newKeyRange(keyColumns, after, nil, quote, placeholder)
+	query := "SELECT " + strings.Join(r.cols, ", ") + " FROM " + from + r.where + r.orderBy()
+	query += fmt.Sprintf(" LIMIT 1 OFFSET %d", max(n-1, 0))
+	return query, r.args
+}
+
+// ChecksumQuery builds the query for RangeChecksummer.ChecksumRange, which
+// returns the row count and sum, the dialect's aggregate of the row hashes,
+// for the rows in the key range.
+func ChecksumQuery(from, sum string, keyColumns, after, upTo []string, quote func(string) string, placeholder func(n int) string) (string, []interface{}) {
+	r := newKeyRange(keyColumns, after, upTo, quote, placeholder)
+	return "SELECT COUNT(*), " + sum + " FROM " + from + r.where, r.args
+}
+
+// ScanRows reads every row of rows with each column rendered as text.
+func ScanRows(rows *sql.Rows) ([]Row, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var out []Row
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for n := range values {
+		dest[n] = &values[n]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		row := Row{}
+		for n, c := range columns {
+			if values[n] == nil {
+				row[c] = nil
+				continue
+			}
+			v := string(values[n])
+			row[c] = &v
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,46 @@
+package source
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRangeQuery(t *testing.T) {
+	quote := func(s string) string { return `"` + s + `"` }
+	dollar := func(n int) string { return "$" + strconv.Itoa(n) }
+
+	query, args := RangeQuery(`"public"."orders"`, []string{"id"}, nil, nil, 100, quote, dollar)
+	if want := `SELECT * FROM "public"."orders" ORDER BY "id" LIMIT 100`; query != want || len(args) != 0 {
+		t.Errorf("unexpected open range query %q %v", query, args)
+	}
+
+	query, args = RangeQuery(`"public"."orders"`, []string{"tenant", "id"}, []string{"a", "10"}, []string{"b", "5"}, 0, quote, dollar)
+	want := `SELECT * FROM "public"."orders" WHERE ("tenant", "id") > ($1, $2) AND ("tenant", "id") <= ($3, $4) ORDER BY "tenant", "id"`
+	if query != want {
+		t.Errorf("expected %q, got %q", want, query)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"a", "10", "b", "5"}) {
+		t.Errorf("unexpected args %v", args)
+	}
+
+	query, _ = RangeQuery("`orders`", []string{"id"}, []string{"10"}, nil, 5, quote, func(int) string { return "?" })
+	if want := "SELECT * FROM `orders` WHERE \"id\" > ? ORDER BY \"id\" LIMIT 5"; query != want {
+		t.Errorf("expected %q, got %q", want, query)
+	}
+}
+
+func TestChecksumAndBoundQueries(t *testing.T) {
+	quote := func(s string) string { return `"` + s + `"` }
+	dollar := func(n int) string { return "$" + strconv.Itoa(n) }
+
+	query, args := BoundQuery(`"orders"`, []string{"tenant", "id"}, []string{"a", "10"}, 1000, quote, dollar)
+	if want := `SELECT "tenant", "id" FROM "orders" WHERE ("tenant", "id") > ($1, $2) ORDER BY "tenant", "id" LIMIT 1 OFFSET 999`; query != want || len(args) != 2 {
+		t.Errorf("expected %q, got %q %v", want, query, args)
+	}
+
+	query, args = ChecksumQuery(`"orders"`, "SUM(h)", []string{"id"}, nil, []string{"10"}, quote, dollar)
+	if want := `SELECT COUNT(*), SUM(h) FROM "orders" WHERE "id" <= $1`; query != want || !reflect.DeepEqual(args, []interface{}{"10"}) {
+		t.Errorf("expected %q, got %q %v", want, query, args)
+	}
+}
@@ -3,19 +3,98 @@ package source
 import "time"
 
 type ColumnInfo struct {
-	Name     string
-	Type     string
-	Nullable bool
+	Name      string
+	Type      string // full column type where the source reports one, e.g. "decimal(10,2) unsigned"
+	Nullable  bool
+	DataType  string  `json:",omitempty"` // base type without length or modifiers
+	Length    *int    `json:",omitempty"` // maximum character length, when known
+	Precision *int    `json:",omitempty"` // numeric precision, when known
+	Scale     *int    `json:",omitempty"` // numeric scale, when known
+	Unsigned  bool    `json:",omitempty"`
+	Charset   string  `json:",omitempty"`
+	Collation string  `json:",omitempty"`
+	Default   *string `json:",omitempty"` // default value expression; nil when there is none
+	Extra     string  `json:",omitempty"` // e.g. "auto_increment" or "VIRTUAL GENERATED"
+	Position  int     `json:",omitempty"` // 1-based ordinal position, when known
+	// Generated is "virtual" or "stored" for generated columns
+	Generated            string `json:",omitempty"`
+	GenerationExpression string `json:",omitempty"`
+	Invisible            bool   `json:",omitempty"` // MySQL 8 INVISIBLE column, hidden from SELECT *
+	// EnumValues are the permitted values of an ENUM or SET column, in order
+	EnumValues []string `json:",omitempty"`
 }
 
 type TableInfo struct {
+	Name        string
+	Columns     []ColumnInfo
+	PrimaryKey  []string
+	Indexes     []IndexInfo      `json:",omitempty"` // secondary indexes, excluding the primary key
+	ForeignKeys []ForeignKeyInfo `json:",omitempty"`
+	RowCount    int64
+	DDLTime     *time.Time // best-effort table DDL timestamp (CREATE/ALTER)
+}
+
+// IndexInfo is a secondary index. Indexes on expressions are not reported.
+type IndexInfo struct {
+	Name    string
+	Columns []string // in index order
+	Unique  bool
+}
+
+// ForeignKeyInfo is a foreign key constraint. RefSchema is empty when the
+// referenced table is in the inspected schema.
+type ForeignKeyInfo struct {
 	Name       string
-	Columns    []ColumnInfo
-	PrimaryKey []string
-	RowCount   int64
-	DDLTime    *time.Time // best-effort table DDL timestamp (CREATE/ALTER)
+	Columns    []string
+	RefSchema  string `json:",omitempty"`
+	RefTable   string
+	RefColumns []string
 }
 
 type InspectionResult struct {
 	Tables []TableInfo
+	// OtherTables are the names of the schema's tables outside the inspected
+	// scope, which are not inspected.
+	OtherTables []string `json:",omitempty"`
+	// ServerVariables holds the server settings CDC depends on, keyed by
+	// lowercase variable name, e.g. binlog_format. Sources that do not report
+	// them leave it nil.
+	ServerVariables map[string]string `json:",omitempty"`
+	// BinaryLogs are the binlog files still on the server, oldest first. Nil
+	// when the source has no binlog or the inspecting account cannot list it.
+	BinaryLogs []BinaryLog `json:",omitempty"`
+	// LogPosition is the server's current binlog position, read at the end
+	// of the inspection. Nil when unknown.
+	LogPosition *LogPosition `json:",omitempty"`
+	// Warnings describe optional reads that failed, such as server variables
+	// or binlogs the inspecting account cannot read. The checks that need them are skipped.
+	Warnings []string `json:",omitempty"`
+}
+
+// LogPosition is a position in the source's binlog.
+type LogPosition struct {
+	File     string
+	Position int64
+	GTIDSet  string `json:",omitempty"` // executed GTID set, when GTIDs are enabled
+}
+
+// BinaryLog is a binlog file on the source server.
+type BinaryLog struct {
+	Name string
+	Size int64
+}
+
+// Grants are the privileges of a database account, including those of the
+// roles it activates on login. Privilege names are uppercase, e.g.
+// "REPLICATION SLAVE".
+type Grants struct {
+	User   string
+	Host   string              // host of the account checked
+	Roles  []string            // active roles, as user@host
+	Global []string            // granted ON *.*
+	Schema []string            // granted on the inspected schema
+	Tables map[string][]string // granted per table of the inspected schema
+	// Uncertain explains why the privileges may be incomplete, e.g. when the
+	// account's roles cannot be read; empty when they are known
+	Uncertain string
 }
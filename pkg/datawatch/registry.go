@@ -0,0 +1,120 @@
+// Package datawatch exposes the extension points needed to embed DataWatch as
+// a library: programs outside this module can register their own source and
+// CDC inspectors, create the registered inspector for a loaded config and
+// validate the inspection results.
+//
+// Built-in inspectors register themselves when their packages are imported;
+// this package imports all of them.
+package datawatch
+
+import (
+	"github.com/alexanderjulianmartinez/data-watch/internal/cdc"
+	_ "github.com/alexanderjulianmartinez/data-watch/internal/cdc/debezium"
+	"github.com/alexanderjulianmartinez/data-watch/internal/config"
+	"github.com/alexanderjulianmartinez/data-watch/internal/drift"
+	"github.com/alexanderjulianmartinez/data-watch/internal/source"
+	_ "github.com/alexanderjulianmartinez/data-watch/internal/source/mysql"
+	_ "github.com/alexanderjulianmartinez/data-watch/internal/source/postgres"
+)
+
+type (
+	Config = config.Config
+
+	SourceInspector  = source.Inspector
+	SourceFactory    = source.Factory
+	InspectionResult = source.InspectionResult
+	TableInfo        = source.TableInfo
+	ColumnInfo       = source.ColumnInfo
+	GrantInspector   = source.GrantInspector
+	Grants           = source.Grants
+	RowSampler       = source.RowSampler
+	Row              = source.Row
+	RangeReader      = source.RangeReader
+	RangeChecksummer = source.RangeChecksummer
+	ChecksumColumn   = source.ChecksumColumn
+	RangeChecksum    = source.RangeChecksum
+
+	CDCInspector       = cdc.Inspector
+	CDCFactory         = cdc.Factory
+	ConnectorInspector = cdc.ConnectorInspector
+	CDCSourceAware     = cdc.SourceAware
+	CDCResult          = cdc.Result
+	ConnectorResult    = cdc.ConnectorResult
+	TableSchema        = cdc.TableSchema
+	CDCColumnInfo      = cdc.ColumnInfo
+	SourceOffset       = cdc.SourceOffset
+	ReplicationLag     = cdc.ReplicationLag
+	TopicReplayer      = cdc.TopicReplayer
+	RowEvent           = cdc.RowEvent
+	FieldSchema        = cdc.FieldSchema
+	CDCWarning         = cdc.Warning
+	CDCWarningKind     = cdc.WarningKind
+
+	Issue           = drift.Issue
+	Report          = drift.Report
+	ValidateOptions = drift.Options
+)
+
+// Issue severities, from least to most serious.
+const (
+	SeverityInfo  = drift.SeverityInfo
+	SeverityWarn  = drift.SeverityWarn
+	SeverityBlock = drift.SeverityBlock
+)
+
+// CDC warning kinds.
+const (
+	CDCWarningConnector = cdc.WarningConnector
+	CDCWarningSnapshot  = cdc.WarningSnapshot
+	CDCWarningHistory   = cdc.WarningHistory
+	CDCWarningConfig    = cdc.WarningConfig
+)
+
+// LoadConfig reads and validates a DataWatch config file.
+func LoadConfig(path string) (*Config, error) {
+	return config.LoadConfig(path)
+}
+
+// RegisterSource makes a source inspector available under the given source.type.
+func RegisterSource(typ string, factory SourceFactory) {
+	source.Register(typ, factory)
+}
+
+// RegisterCDC makes a CDC inspector available under the given cdc.type.
+func RegisterCDC(typ string, factory CDCFactory) {
+	cdc.Register(typ, factory)
+}
+
+// NewSourceInspector creates the inspector registered for cfg.Source.Type.
+func NewSourceInspector(cfg *Config) (SourceInspector, error) {
+	return source.New(cfg)
+}
+
+// NewCDCInspector creates the inspector registered for cfg.CDC.Type.
+func NewCDCInspector(cfg *Config) (CDCInspector, error) {
+	return cdc.New(cfg)
+}
+
+// Validate compares a source inspection with a CDC inspection.
+func Validate(src *InspectionResult, cdcResult *CDCResult) *Report {
+	return drift.Validate(src, cdcResult)
+}
+
+// ValidateWithOptions is Validate with the config-driven checks: table scope,
+// tolerances and the connector account's privileges.
+func ValidateWithOptions(src *InspectionResult, cdcResult *CDCResult, opts ValidateOptions) *Report {
+	return drift.ValidateWithOptions(src, cdcResult, opts)
+}
+
+// ValidateTables checks the configured tables against the source and the
+// tables captured by any of the connectors. It reports nothing without a
+// config.
+func ValidateTables(src *InspectionResult, connectors []*ConnectorResult, cfg *Config) *Report {
+	return drift.ValidateTables(src, connectors, cfg)
+}
+
+// ValidateServer checks the source server settings the connectors depend on
+// and the reads that failed during the source inspection.
+func ValidateServer(src *InspectionResult, connectors []*ConnectorResult, cfg *Config) *Report {
+	return drift.ValidateServer(src, connectors, cfg)
+}
@@ -1,7 +1,21 @@
 package types
 
 type ColumnSpec struct {
-	Name     string
-	Type     string
-	Nullable bool
+	Name      string
+	Type      string
+	Nullable  bool
+	DataType  string
+	Length    *int
+	Precision *int
+	Scale     *int
+	Unsigned  bool
+	Charset   string
+	Collation string
+	Default   *string
+	Extra     string
+	Position  int
+	// Generated is "virtual" or "stored" for generated columns
+	Generated            string
+	GenerationExpression string
+	Invisible            bool
 }